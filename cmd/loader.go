@@ -27,6 +27,7 @@ package main
 import (
 	"flag"
 	"fmt"
+	"math"
 	"os"
 	"time"
 
@@ -37,6 +38,7 @@ import (
 	"github.com/vhive-serverless/loader/pkg/common"
 	"github.com/vhive-serverless/loader/pkg/config"
 	"github.com/vhive-serverless/loader/pkg/driver"
+	"github.com/vhive-serverless/loader/pkg/driver/deployment"
 	"github.com/vhive-serverless/loader/pkg/trace"
 
 	log "github.com/sirupsen/logrus"
@@ -45,6 +47,9 @@ import (
 
 const (
 	zipkinAddr = "http://localhost:9411/api/v2/spans"
+
+	exitCodeSLOViolated = 1
+	exitCodeAborted     = 2
 )
 
 var (
@@ -54,6 +59,8 @@ var (
 	iatGeneration = flag.Bool("iatGeneration", false, "Generate IATs only or run invocations as well")
 	iatFromFile   = flag.Bool("generated", false, "True if iats were already generated")
 	dryRun        = flag.Bool("dryRun", false, "Dry run mode - do not deploy functions or generate invocations")
+
+	failOnSLOViolation = flag.Bool("failOnSLOViolation", false, "Exit with a non-zero code if any SLO has been violated")
 )
 
 func init() {
@@ -76,6 +83,12 @@ func init() {
 }
 
 func main() {
+	os.Exit(run())
+}
+
+// run runs the loader and returns its exit code, which is non-zero if the experiment has been aborted, or if its SLOs
+// have not been met and failOnSLOViolation is set
+func run() int {
 	cfg := config.ReadConfigurationFile(*configPath)
 	if cfg.EnableZipkinTracing {
 		// TODO: how not to exclude Zipkin spans here? - file a feature request
@@ -100,15 +113,37 @@ func main() {
 		log.Fatal("Unsupported platform!")
 	}
 
+	if args := flag.Args(); len(args) > 0 {
+		if len(args) != 3 || args[0] != "cleanup" || args[1] != "run" {
+			log.Fatal("Unknown command - usage: loader [flags] cleanup run <run ID>")
+		}
+
+		cleanupRun(&cfg, args[2])
+		return 0
+	}
+
 	if cfg.Platform == common.PlatformKnative {
 		common.CheckCPULimit(cfg.CPULimit)
 	}
 
-	if cfg.TracePath == "RPS" {
-		runRPSMode(&cfg, *iatFromFile, *iatGeneration)
+	var slosMet bool
+	var err error
+	if cfg.TracePath == "RPS" && cfg.SaturationSearch != nil {
+		slosMet, err = runSaturationSearch(&cfg)
+	} else if cfg.TracePath == "RPS" {
+		slosMet, err = runRPSMode(&cfg, *iatFromFile, *iatGeneration)
 	} else {
-		runTraceMode(&cfg, *iatFromFile, *iatGeneration)
+		slosMet, err = runTraceMode(&cfg, *iatFromFile, *iatGeneration)
+	}
+
+	if err != nil {
+		log.Errorf("Experiment aborted - %v", err)
+		return exitCodeAborted
 	}
+	if !slosMet && *failOnSLOViolation {
+		return exitCodeSLOViolated
+	}
+	return 0
 }
 
 func determineDurationToParse(runtimeDuration int, warmupDuration int) int {
@@ -170,7 +205,9 @@ func parseTraceGranularity(cfg *config.LoaderConfiguration) common.TraceGranular
 	return common.MinuteGranularity
 }
 
-func runTraceMode(cfg *config.LoaderConfiguration, readIATFromFile bool, writeIATsToFile bool) {
+// runTraceMode runs the experiment and returns false if any of the SLOs has been violated, or an error if the experiment
+// has been aborted
+func runTraceMode(cfg *config.LoaderConfiguration, readIATFromFile bool, writeIATsToFile bool) (bool, error) {
 	durationToParse := determineDurationToParse(cfg.ExperimentDuration, cfg.WarmupDuration)
 	yamlPath := parseYAMLSpecification(cfg)
 	var functions []*common.Function
@@ -187,6 +224,12 @@ func runTraceMode(cfg *config.LoaderConfiguration, readIATFromFile bool, writeIA
 	// Dirigent metadata parsing
 	dirigentMetadataParser := trace.NewDirigentMetadataParser(cfg.TracePath, functions, yamlPath, cfg.Platform)
 	dirigentMetadataParser.Parse()
+	// Payload size distributions parsing
+	payloadParser := trace.NewPayloadParser(cfg.TracePath, cfg.PayloadDistributionPath, functions)
+	payloadParser.Parse()
+	// Knative autoscaling settings parsing
+	autoscalingParser := trace.NewAutoscalingParser(cfg.TracePath, cfg.AutoscalingConfigPath, functions)
+	autoscalingParser.Parse()
 
 	log.Infof("Traces contain the following %d functions:\n", len(functions))
 	for _, function := range functions {
@@ -201,6 +244,7 @@ func runTraceMode(cfg *config.LoaderConfiguration, readIATFromFile bool, writeIA
 
 		// loads dirigent config only if the platform is 'dirigent'
 		DirigentConfiguration: config.ReadDirigentConfig(cfg),
+		RequestTemplates:      config.ReadRequestTemplateConfig(cfg.RequestTemplatePath),
 
 		IATDistribution:  iatType,
 		ShiftIAT:         shiftIAT,
@@ -214,46 +258,143 @@ func runTraceMode(cfg *config.LoaderConfiguration, readIATFromFile bool, writeIA
 
 	// Skip experiments execution during dry run mode
 	if *dryRun {
-		return
+		return true, nil
 	}
 
 	log.Infof("Using %s as a service YAML specification file.\n", yamlPath)
 
 	experimentDriver.GenerateSpecification()
 	experimentDriver.ReadOrWriteFileSpecification(writeIATsToFile, readIATFromFile)
-	experimentDriver.RunExperiment()
+	return experimentDriver.RunExperiment()
 }
 
-func runRPSMode(cfg *config.LoaderConfiguration, readIATFromFile bool, writeIATsToFile bool) {
+// runRPSMode runs the experiment and returns false if any of the SLOs has been violated, or an error if the experiment
+// has been aborted
+func runRPSMode(cfg *config.LoaderConfiguration, readIATFromFile bool, writeIATsToFile bool) (bool, error) {
 	experimentDuration := determineDurationToParse(cfg.ExperimentDuration, cfg.WarmupDuration)
 	yamlPath := parseYAMLSpecification(cfg)
 
-	rpsTarget := cfg.RpsTarget
-	coldStartPercentage := cfg.RpsColdStartRatioPercentage
-
-	warmStartRPS := rpsTarget * (100 - coldStartPercentage) / 100
-	coldStartRPS := rpsTarget * coldStartPercentage / 100
-
-	warmFunction, warmStartCount := generator.GenerateWarmStartFunction(experimentDuration, warmStartRPS)
-	coldFunctions, coldStartCount := generator.GenerateColdStartFunctions(experimentDuration, coldStartRPS, cfg.RpsCooldownSeconds)
+	rpsProfile, err := generator.CreateRPSProfile(cfg, experimentDuration)
+	if err != nil {
+		log.Fatalf("Failed to create RPS profile - %v", err)
+	}
+	if rpsProfile != nil && cfg.RpsSpecificationPath != "" {
+		log.Fatal("RPS profiles are not supported in combination with an RPS specification file.")
+	}
 
 	// loads dirigent config only if the platform is 'dirigent'
 	dirigentConfig := config.ReadDirigentConfig(cfg)
 
+	var functions []*common.Function
+	var totalRPS, coldStartRPSSum, maxKeepAlive float64
+	for _, class := range generator.RPSFunctionClasses(cfg) {
+		keepAlive, err := generator.KeepAliveSeconds(cfg.RpsKeepAlive, class.CooldownSeconds)
+		if err != nil {
+			log.Fatalf("Failed to determine keep-alive - %v", err)
+		}
+		// each function is invoked less often than the keep-alive, so that every invocation is a cold start
+		cooldownSeconds := int(math.Ceil(keepAlive))
+
+		warmStartRPS := class.RpsTarget * (100 - class.ColdStartRatioPercentage) / 100
+		coldStartRPS := class.RpsTarget * class.ColdStartRatioPercentage / 100
+
+		totalRPS += class.RpsTarget
+		coldStartRPSSum += coldStartRPS
+		if coldStartRPS > 0 {
+			maxKeepAlive = max(maxKeepAlive, keepAlive)
+		}
+
+		var warmFunction common.IATArray
+		var warmStartCount []int
+		var coldFunctions []common.IATArray
+		var coldStartCount [][]int
+		if rpsProfile == nil {
+			warmFunction, warmStartCount = generator.GenerateWarmStartFunction(experimentDuration, warmStartRPS)
+			coldFunctions, coldStartCount = generator.GenerateColdStartFunctions(experimentDuration, coldStartRPS, cooldownSeconds)
+		} else {
+			warmFunction, warmStartCount = generator.GenerateWarmStartFunctionByProfile(experimentDuration,
+				generator.ScaleRPSProfile(rpsProfile, (100-class.ColdStartRatioPercentage)/100))
+			coldFunctions, coldStartCount = generator.GenerateColdStartFunctionsByProfile(experimentDuration,
+				generator.ScaleRPSProfile(rpsProfile, class.ColdStartRatioPercentage/100), cooldownSeconds)
+		}
+
+		functions = append(functions, generator.CreateRPSFunctions(cfg, dirigentConfig, &class, warmFunction, warmStartCount, coldFunctions, coldStartCount, yamlPath)...)
+	}
+
+	trace.NewPayloadParser("", cfg.PayloadDistributionPath, functions).Parse()
+	trace.NewAutoscalingParser("", cfg.AutoscalingConfigPath, functions).Parse()
+
+	coldStartTarget := &config.ColdStartTarget{Warmup: time.Duration(maxKeepAlive * float64(time.Second))}
+	if totalRPS > 0 {
+		coldStartTarget.RatioPercentage = coldStartRPSSum * 100 / totalRPS
+	}
+
 	experimentDriver := driver.NewDriver(&config.Configuration{
 		LoaderConfiguration: cfg,
 		TraceDuration:       experimentDuration,
 
 		DirigentConfiguration: dirigentConfig,
+		RequestTemplates:      config.ReadRequestTemplateConfig(cfg.RequestTemplatePath),
+
+		ColdStartTarget: coldStartTarget,
 
-		Functions: generator.CreateRPSFunctions(cfg, dirigentConfig, warmFunction, warmStartCount, coldFunctions, coldStartCount, yamlPath),
+		Functions: functions,
 	})
 
 	// Skip experiments execution during dry run mode
 	if *dryRun {
-		return
+		return true, nil
 	}
 
+	experimentDriver.GeneratePayloadSpecification()
 	experimentDriver.ReadOrWriteFileSpecification(writeIATsToFile, readIATFromFile)
-	experimentDriver.RunExperiment()
+	return experimentDriver.RunExperiment()
+}
+
+// runSaturationSearch looks for the maximum RPS at which the SLOs are met by running short RPS-mode trials and returns
+// false if the SLOs are not met even at the minimum RPS, or an error if a trial has been aborted
+func runSaturationSearch(cfg *config.LoaderConfiguration) (bool, error) {
+	if len(cfg.SLOs) == 0 {
+		log.Fatal("Saturation search requires at least one SLO to judge the trials.")
+	}
+	if cfg.RpsSpecificationPath != "" {
+		log.Fatal("Saturation search is not supported in combination with an RPS specification file.")
+	}
+
+	search := cfg.SaturationSearch
+	sustainableRPS, trials, err := driver.SearchSaturation(search, func(step int, rps float64) (bool, error) {
+		trialCfg := *cfg
+		trialCfg.RpsTarget = rps
+		trialCfg.OutputPathPrefix = fmt.Sprintf("%s_saturation_step%d", cfg.OutputPathPrefix, step)
+		if search.TrialDuration > 0 {
+			trialCfg.ExperimentDuration = search.TrialDuration
+		}
+
+		return runRPSMode(&trialCfg, false, false)
+	})
+
+	driver.WriteSaturationTrials(fmt.Sprintf("%s_saturation.csv", cfg.OutputPathPrefix), trials)
+
+	if err != nil {
+		return false, err
+	}
+	if sustainableRPS == 0 {
+		log.Errorf("SLOs are not met even at %.2f RPS.", search.MinRps)
+		return false, nil
+	}
+
+	log.Infof("Maximum sustainable RPS: %.2f (%d trials)", sustainableRPS, len(trials))
+	return true, nil
+}
+
+// cleanupRun removes the resources left on the platform by the run with the given ID, e.g., after a crash
+func cleanupRun(cfg *config.LoaderConfiguration, runID string) {
+	runCfg := &config.Configuration{
+		LoaderConfiguration:   cfg,
+		DirigentConfiguration: config.ReadDirigentConfig(cfg),
+		RunID:                 runID,
+	}
+
+	deployment.CreateDeployer(runCfg).CleanRun(runCfg, runID)
+	log.Infof("Cleaned up run %s", runID)
 }
@@ -27,6 +27,10 @@ package common
 const (
 	FunctionNamePrefix      = "trace-func"
 	OneSecondInMicroseconds = 1_000_000.0
+
+	// ResponsePayloadKey is the HTTP header and the gRPC metadata key through which the loader requests the
+	// function to append the given number of bytes to its response
+	ResponsePayloadKey = "response_payload_bytes"
 )
 
 const (
@@ -93,11 +97,6 @@ const (
 	IssuedVsFailed    RuntimeAssertType = 1
 )
 
-const (
-	AwsRegion                  = "us-east-1"
-	AwsTraceFuncRepositoryName = "invitro_trace_function_aws"
-)
-
 // CPULimits
 const (
 	CPULimit1vCPU string = "1vCPU"
@@ -35,6 +35,10 @@ type ProbabilisticDuration []float64
 type RuntimeSpecification struct {
 	Runtime int
 	Memory  int
+
+	// Payload sizes in bytes
+	RequestPayloadBytes  int
+	ResponsePayloadBytes int
 }
 
 type RuntimeSpecificationArray []RuntimeSpecification
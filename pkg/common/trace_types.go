@@ -72,6 +72,58 @@ type FunctionMemoryStats struct {
 	Percentile100 float64 `csv:"AverageAllocatedMb_pct100"`
 }
 
+type FunctionPayloadStats struct {
+	HashOwner    string `csv:"HashOwner"`
+	HashApp      string `csv:"HashApp"`
+	HashFunction string `csv:"HashFunction"`
+
+	RequestPercentile0   float64 `csv:"RequestKb_pct0"`
+	RequestPercentile25  float64 `csv:"RequestKb_pct25"`
+	RequestPercentile50  float64 `csv:"RequestKb_pct50"`
+	RequestPercentile75  float64 `csv:"RequestKb_pct75"`
+	RequestPercentile99  float64 `csv:"RequestKb_pct99"`
+	RequestPercentile100 float64 `csv:"RequestKb_pct100"`
+
+	ResponsePercentile0   float64 `csv:"ResponseKb_pct0"`
+	ResponsePercentile25  float64 `csv:"ResponseKb_pct25"`
+	ResponsePercentile50  float64 `csv:"ResponseKb_pct50"`
+	ResponsePercentile75  float64 `csv:"ResponseKb_pct75"`
+	ResponsePercentile99  float64 `csv:"ResponseKb_pct99"`
+	ResponsePercentile100 float64 `csv:"ResponseKb_pct100"`
+}
+
+// PayloadCDF is an empirical cumulative distribution function of payload sizes. Probabilities are in [0, 1] and
+// sizes between two points of the distribution are linearly interpolated.
+type PayloadCDF struct {
+	SizeKB      []float64 `json:"SizeKB"`
+	Probability []float64 `json:"Probability"`
+}
+
+type PayloadDistribution struct {
+	Request  *PayloadCDF `json:"Request"`
+	Response *PayloadCDF `json:"Response"`
+}
+
+// AutoscalingSettings override the Knative autoscaling annotations of the YAML template of a function, unset if nil
+type AutoscalingSettings struct {
+	HashFunction string `json:"HashFunction"`
+
+	// kpa.autoscaling.knative.dev or hpa.autoscaling.knative.dev
+	Class *string `json:"Class"`
+	// concurrency, rps, cpu or memory
+	Metric                      *string  `json:"Metric"`
+	Target                      *float64 `json:"Target"`
+	TargetUtilizationPercentage *float64 `json:"TargetUtilizationPercentage"`
+	StableWindowSeconds         *int     `json:"StableWindowSeconds"`
+	// the scale-to-zero grace period is cluster-wide, so the last instance is kept through its per-revision equivalent
+	ScaleToZeroPodRetentionSeconds *int `json:"ScaleToZeroPodRetentionSeconds"`
+	MinScale                       *int `json:"MinScale"`
+	MaxScale                       *int `json:"MaxScale"`
+	InitialScale                   *int `json:"InitialScale"`
+	// 0 for unlimited concurrency
+	ContainerConcurrency *int `json:"ContainerConcurrency"`
+}
+
 type DirigentMetadata struct {
 	HashFunction        string   `json:"HashFunction"`
 	Image               string   `json:"Image"`
@@ -104,6 +156,10 @@ type Function struct {
 	RuntimeStats     *FunctionRuntimeStats
 	MemoryStats      *FunctionMemoryStats
 	DirigentMetadata *DirigentMetadata
+	// From the trace or the payload distribution configuration
+	PayloadDistribution *PayloadDistribution
+	// From the trace or the autoscaling configuration
+	Autoscaling *AutoscalingSettings
 
 	ColdStartBusyLoopMs int
 
@@ -123,4 +179,15 @@ type Node struct {
 	Branches []*list.List
 	Depth    int
 	DAG      string
+
+	// Set by the edges from the parents in explicit DAG topologies
+	FanOut       int // number of parallel invocations per invocation of the parent, 1 if 0
+	PayloadBytes int // request payload size overriding the runtime specification if positive
+	// A node with multiple parents is invoked once all of them have completed
+	Parents int
+
+	// Set by explicit DAG topologies to make the invocation of the node conditional
+	ID          string  // identifier of the node in the recorded paths, the name of the function if empty
+	Probability float64 // probability of invoking the node per invocation of the parent, or its weight if the parent is a choice, 1 if 0
+	Choice      bool    // exactly one of the children of the node is invoked
 }
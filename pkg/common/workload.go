@@ -7,7 +7,19 @@ package common
 // }
 import "C"
 import (
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
 )
 
 const (
@@ -34,6 +46,70 @@ func busySpin(multiplier, runtimeMilli uint32) {
 	}
 }
 
+const (
+	calibrationWarmupUnits = 1e5
+	calibrationRounds      = 5
+	// how many execution units to run between two reads of the clock
+	calibrationBatchUnits = 64
+)
+
+// CalibrateIterationsMultiplier measures the throughput of the busy loop on the host and returns the number of
+// execution units that take one millisecond, i.e., the value of the iterations multiplier for which the busy loop
+// consumes the requested runtime. The measurement takes the given duration and the median over several rounds is
+// returned to filter out interference.
+func CalibrateIterationsMultiplier(duration time.Duration) uint32 {
+	for i := 0; i < calibrationWarmupUnits; i++ {
+		takeSqrts()
+	}
+
+	roundDuration := duration / calibrationRounds
+	multipliers := make([]float64, 0, calibrationRounds)
+
+	for round := 0; round < calibrationRounds; round++ {
+		units := 0
+		start := time.Now()
+
+		for time.Since(start) < roundDuration {
+			for i := 0; i < calibrationBatchUnits; i++ {
+				takeSqrts()
+			}
+			units += calibrationBatchUnits
+		}
+
+		elapsedMilliseconds := float64(time.Since(start).Microseconds()) / 1e3
+		multipliers = append(multipliers, float64(units)/elapsedMilliseconds)
+	}
+
+	sort.Float64s(multipliers)
+
+	return uint32(math.Round(multipliers[len(multipliers)/2]))
+}
+
+// ReadIterationsMultiplier reads the ITERATIONS_MULTIPLIER environment variable. The value 'auto' calibrates the
+// multiplier on the host, while the default value is returned if the variable is not set or is invalid.
+func ReadIterationsMultiplier(defaultValue int) int {
+	value, ok := os.LookupEnv("ITERATIONS_MULTIPLIER")
+	if !ok {
+		return defaultValue
+	}
+
+	if strings.ToLower(value) == "auto" {
+		start := time.Now()
+		multiplier := int(CalibrateIterationsMultiplier(time.Second))
+		log.Infof("Calibrated ITERATIONS_MULTIPLIER in %v", time.Since(start))
+
+		return multiplier
+	}
+
+	multiplier, err := strconv.Atoi(value)
+	if err != nil || multiplier <= 0 {
+		log.Warnf("Invalid ITERATIONS_MULTIPLIER %s. Using the default value %d.", value, defaultValue)
+		return defaultValue
+	}
+
+	return multiplier
+}
+
 func TraceFunctionExecution(start time.Time, IterationsMultiplier uint32, timeLeftMilliseconds uint32) (msg string) {
 	timeConsumedMilliseconds := uint32(time.Since(start).Milliseconds())
 	if timeConsumedMilliseconds < timeLeftMilliseconds {
@@ -45,3 +121,156 @@ func TraceFunctionExecution(start time.Time, IterationsMultiplier uint32, timeLe
 
 	return msg
 }
+
+type IOMode string
+
+const (
+	// IOModeSleep waits idly
+	IOModeSleep IOMode = "sleep"
+	// IOModeDisk writes to and synchronizes a file on the local disk
+	IOModeDisk IOMode = "disk"
+	// IOModeDownstream calls a downstream HTTP endpoint
+	IOModeDownstream IOMode = "downstream"
+
+	ioBlockSize = 64 * 1024
+)
+
+type IOConfiguration struct {
+	Mode IOMode
+	// directory in which the file for disk IO is created
+	DiskPath string
+	// URL called by the downstream IO mode
+	DownstreamURL string
+}
+
+// MixedFunctionExecution splits the time left into IO wait and CPU spin according to the IO percentage. The IO part is
+// executed first, followed by the CPU part as in TraceFunctionExecution.
+func MixedFunctionExecution(start time.Time, IterationsMultiplier uint32, timeLeftMilliseconds uint32, ioPercentage uint32, ioConfig *IOConfiguration) (msg string) {
+	if ioPercentage > 0 && ioConfig != nil {
+		ioDuration := time.Duration(timeLeftMilliseconds*min(ioPercentage, 100)/100) * time.Millisecond
+		waitForIO(start.Add(ioDuration), ioConfig)
+	}
+
+	return TraceFunctionExecution(start, IterationsMultiplier, timeLeftMilliseconds)
+}
+
+func waitForIO(deadline time.Time, ioConfig *IOConfiguration) {
+	var err error
+	switch ioConfig.Mode {
+	case IOModeDisk:
+		err = diskIO(deadline, ioConfig.DiskPath)
+	case IOModeDownstream:
+		err = downstreamIO(deadline, ioConfig.DownstreamURL)
+	}
+
+	if err != nil {
+		log.Debugf("IO failed, waiting idly instead - %v", err)
+	}
+
+	// sleep mode, as well as the remainder of the failed or the last IO operation
+	time.Sleep(time.Until(deadline))
+}
+
+func diskIO(deadline time.Time, directory string) error {
+	file, err := os.CreateTemp(directory, "io-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	block := make([]byte, ioBlockSize)
+	for time.Now().Before(deadline) {
+		if _, err = file.WriteAt(block, 0); err != nil {
+			return err
+		}
+		if err = file.Sync(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func downstreamIO(deadline time.Time, url string) error {
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	for time.Now().Before(deadline) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil // deadline reached during the call
+			}
+
+			return err
+		}
+
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}
+
+	return nil
+}
+
+// InstanceTelemetry holds the per-instance information functions report through the v2 Executor protocol.
+type InstanceTelemetry struct {
+	StartTime time.Time
+
+	served atomic.Bool
+}
+
+func NewInstanceTelemetry() *InstanceTelemetry {
+	return &InstanceTelemetry{StartTime: time.Now()}
+}
+
+// ColdStart returns true only for the first request served by the instance.
+func (t *InstanceTelemetry) ColdStart() bool {
+	return !t.served.Swap(true)
+}
+
+// ThreadCPUTime returns the CPU time consumed by the calling OS thread. The caller should lock its goroutine to the
+// OS thread with runtime.LockOSThread for the difference of two calls to be attributable to the goroutine.
+func ThreadCPUTime() time.Duration {
+	var usage unix.Rusage
+	if err := unix.Getrusage(unix.RUSAGE_THREAD, &usage); err != nil {
+		return 0
+	}
+
+	return time.Duration(usage.Utime.Nano() + usage.Stime.Nano())
+}
+
+// PeakRSSKib returns the peak resident set size of the process in KiB.
+func PeakRSSKib() uint64 {
+	var usage unix.Rusage
+	if err := unix.Getrusage(unix.RUSAGE_SELF, &usage); err != nil {
+		return 0
+	}
+
+	return uint64(usage.Maxrss)
+}
+
+// RSSKib returns the current resident set size of the process in KiB.
+func RSSKib() uint64 {
+	statm, err := os.ReadFile("/proc/self/statm")
+	if err != nil {
+		return 0
+	}
+
+	fields := strings.Fields(string(statm))
+	if len(fields) < 2 {
+		return 0
+	}
+
+	residentPages, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	return residentPages * uint64(os.Getpagesize()) / 1024
+}
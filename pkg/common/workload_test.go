@@ -0,0 +1,65 @@
+package common
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMixedFunctionExecution(t *testing.T) {
+	var downstreamCalls atomic.Int64
+	downstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		downstreamCalls.Add(1)
+		time.Sleep(5 * time.Millisecond)
+	}))
+	defer downstream.Close()
+
+	diskPath := t.TempDir()
+
+	tests := []struct {
+		name     string
+		ioConfig *IOConfiguration
+	}{
+		{name: "sleep", ioConfig: &IOConfiguration{Mode: IOModeSleep}},
+		{name: "disk", ioConfig: &IOConfiguration{Mode: IOModeDisk, DiskPath: diskPath}},
+		{name: "downstream", ioConfig: &IOConfiguration{Mode: IOModeDownstream, DownstreamURL: downstream.URL}},
+		{name: "downstream_unreachable", ioConfig: &IOConfiguration{Mode: IOModeDownstream, DownstreamURL: "http://localhost:1"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			start := time.Now()
+			MixedFunctionExecution(start, 0, 100, 100, test.ioConfig)
+
+			if elapsed := time.Since(start); elapsed < 100*time.Millisecond || elapsed > 200*time.Millisecond {
+				t.Errorf("Unexpected execution time %v for 100ms of IO.", elapsed)
+			}
+		})
+	}
+
+	if downstreamCalls.Load() == 0 {
+		t.Error("Downstream endpoint has not been called.")
+	}
+
+	if files, _ := os.ReadDir(diskPath); len(files) != 0 {
+		t.Error("File used for disk IO has not been removed.")
+	}
+}
+
+func TestCalibrateIterationsMultiplier(t *testing.T) {
+	multiplier := CalibrateIterationsMultiplier(500 * time.Millisecond)
+	if multiplier == 0 {
+		t.Fatal("Calibrated iterations multiplier should be positive.")
+	}
+
+	start := time.Now()
+	TraceFunctionExecution(start, multiplier, 200)
+
+	// loose bounds, as the test may share the CPU with other tests
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond || elapsed > 400*time.Millisecond {
+		t.Errorf("Calibrated multiplier %d yields %v for 200ms of requested runtime.", multiplier, elapsed)
+	}
+}
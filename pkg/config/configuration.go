@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	"github.com/vhive-serverless/loader/pkg/common"
 )
 
@@ -8,6 +10,7 @@ type Configuration struct {
 	LoaderConfiguration   *LoaderConfiguration
 	FailureConfiguration  *FailureConfiguration
 	DirigentConfiguration *DirigentConfig
+	RequestTemplates      *RequestTemplateConfig
 
 	IATDistribution  common.IatDistribution
 	ShiftIAT         bool // shift the invocations inside minute
@@ -17,9 +20,24 @@ type Configuration struct {
 
 	TestMode bool
 
+	// identifies the resources created by the deployers in this run
+	RunID string
+
+	// set only in RPS mode
+	ColdStartTarget *ColdStartTarget
+
 	Functions []*common.Function
 }
 
+// ColdStartTarget is the cold start ratio the RPS mode aims for
+type ColdStartTarget struct {
+	RatioPercentage float64
+	// Invocations issued within this period since the beginning of the experiment are tagged as cold start warm-up,
+	// as instances left from the deployment may still serve them. They are excluded from the validation of the
+	// achieved ratio and from the SLOs.
+	Warmup time.Duration
+}
+
 func (c *Configuration) WithWarmup() bool {
 	if c.LoaderConfiguration.WarmupDuration > 0 {
 		return true
@@ -36,9 +36,36 @@ import (
 type FailureConfiguration struct {
 	FailureEnabled bool `json:"FailureEnabled"`
 
+	// single failure, used if no events are set
 	FailAt        int    `json:"FailAt"`
 	FailComponent string `json:"FailComponent"`
 	FailNode      string `json:"FailNode"`
+
+	// failure scenario of timed events
+	Events []FailureEvent `json:"Events"`
+}
+
+const (
+	FailureActionFail    = "fail"
+	FailureActionRecover = "recover"
+	FailureActionRestart = "restart"
+)
+
+// FailureEvent is a failure or a recovery injected at a time of the experiment, possibly repeated periodically
+type FailureEvent struct {
+	// seconds since the beginning of the experiment
+	At int `json:"At"`
+	// fail, recover or restart, fail if empty
+	Action string `json:"Action"`
+	// control_plane, data_plane or worker_node, ignored if Command is set
+	Component string `json:"Component"`
+	// nodes the command is run on through SSH, locally if empty
+	Nodes []string `json:"Nodes"`
+	// custom command replacing the one of the component
+	Command []string `json:"Command"`
+	// period in seconds at which the event is repeated, and number of repetitions after the first injection
+	RepeatEverySeconds int `json:"RepeatEverySeconds"`
+	RepeatCount        int `json:"RepeatCount"`
 }
 
 type LoaderConfiguration struct {
@@ -55,6 +82,18 @@ type LoaderConfiguration struct {
 	RpsRuntimeMs                int     `json:"RpsRuntimeMs"`
 	RpsMemoryMB                 int     `json:"RpsMemoryMB"`
 	RpsIterationMultiplier      int     `json:"RpsIterationMultiplier"`
+	// keep-alive of the platform assumed when forcing cold starts, RpsCooldownSeconds if not set
+	RpsKeepAlive *KeepAliveModel `json:"RpsKeepAlive"`
+
+	// time-varying target RPS, constant by default
+	RpsProfile       string  `json:"RpsProfile"`
+	RpsStartTarget   float64 `json:"RpsStartTarget"`
+	RpsStepMinutes   int     `json:"RpsStepMinutes"`
+	RpsAmplitude     float64 `json:"RpsAmplitude"`
+	RpsPeriodMinutes float64 `json:"RpsPeriodMinutes"`
+	RpsProfilePath   string  `json:"RpsProfilePath"`
+	// heterogeneous RPS mode - the function classes are read from the file instead of the Rps* parameters above
+	RpsSpecificationPath string `json:"RpsSpecificationPath"`
 
 	TracePath          string `json:"TracePath"`
 	Granularity        string `json:"Granularity"`
@@ -78,8 +117,126 @@ type LoaderConfiguration struct {
 	Depth                        int  `json:"Depth"`
 	VSwarm                       bool `json:"VSwarm"`
 
+	// explicit DAG topologies in JSON or DOT, replacing Width, Depth and EnableDAGDataset
+	DAGTopologyPath string `json:"DAGTopologyPath"`
+	// pass the size of the response of a node as the request payload size to its children
+	DAGDataPassing bool `json:"DAGDataPassing"`
+	// write the DAGs of the experiment to a JSON or DOT file that can be replayed through DAGTopologyPath
+	DAGExportPath string `json:"DAGExportPath"`
+
 	// used only if platform is dirigent
 	DirigentConfigPath string `json:"DirigentConfigPath"`
+
+	// used only for HTTP invocations
+	RequestTemplatePath string `json:"RequestTemplatePath"`
+
+	PayloadDistributionPath string `json:"PayloadDistributionPath"`
+	// used only if platform is knative
+	AutoscalingConfigPath string `json:"AutoscalingConfigPath"`
+
+	SLOs []SLO `json:"SLOs"`
+	// search of the maximum RpsTarget at which the SLOs are met, used only in RPS mode
+	SaturationSearch *SaturationSearch `json:"SaturationSearch"`
+	// verification of the deployed functions before the experiment starts, skipped if not set
+	ReadinessProbe *ReadinessProbe `json:"ReadinessProbe"`
+	// reuse the functions deployed by a previous run and keep them after the experiment, Knative only
+	ReuseDeployment bool `json:"ReuseDeployment"`
+	// records of the functions deployed by each run on Dirigent, OpenWhisk and AWS Lambda, "runs" next to the outputs
+	// if empty
+	RunRecordDirectory string `json:"RunRecordDirectory"`
+	// used only if platform is awslambda
+	AWSLambda *AWSLambdaConfig `json:"AWSLambda"`
+	// completions of asynchronous invocations pushed by the platform instead of polled, used only for Dirigent async
+	// mode and AWS Lambda Event invocations
+	CompletionCallback *CompletionCallbackConfig `json:"CompletionCallback"`
+}
+
+const (
+	SLOMetricSlowdown     = "slowdown"
+	SLOMetricResponseTime = "response_time"
+	SLOMetricFailureRate  = "failure_rate"
+)
+
+// SLO is an objective evaluated at the end of the experiment over the invocations of the execution phase
+type SLO struct {
+	Name string `json:"Name"`
+	// slowdown (response time / requested duration), response_time [ms] or failure_rate [%]
+	Metric string `json:"Metric"`
+	// percentile of the slowdown or response time, ignored for the failure rate
+	Percentile float64 `json:"Percentile"`
+	// the objective is met if the value of the metric is lower than or equal to the threshold
+	Threshold float64 `json:"Threshold"`
+	// names or trace hashes of the functions the objective applies to, all functions if empty
+	Functions []string `json:"Functions"`
+	// evaluate the objective for each function separately instead of over all the selected functions
+	PerFunction bool `json:"PerFunction"`
+}
+
+// SaturationSearch configures the binary search of the maximum sustainable RPS, where each step is a short RPS-mode
+// trial judged by the SLOs
+type SaturationSearch struct {
+	MinRps float64 `json:"MinRps"`
+	MaxRps float64 `json:"MaxRps"`
+	// the search stops once the sustainable RPS is known within this precision
+	Precision float64 `json:"Precision"`
+	// upper bound of the number of trials, including the ones at MinRps and MaxRps
+	MaxTrials int `json:"MaxTrials"`
+	// duration of each trial in minutes, ExperimentDuration if 0
+	TrialDuration int `json:"TrialDuration"`
+}
+
+const (
+	ReadinessPolicyAbort   = "abort"
+	ReadinessPolicyDrop    = "drop"
+	ReadinessPolicyProceed = "proceed"
+)
+
+// ReadinessProbe configures the health invocations probing every function after the deployment, retried with an
+// exponential backoff
+type ReadinessProbe struct {
+	// number of health invocations per function before it is considered unusable, 5 if 0
+	Attempts int `json:"Attempts"`
+	// backoff after the first failed attempt, doubled after every further one up to MaxBackoffMs, 1000 if 0
+	InitialBackoffMs int `json:"InitialBackoffMs"`
+	// 30000 if 0
+	MaxBackoffMs int `json:"MaxBackoffMs"`
+	// abort the run, drop the unusable functions from the run, or proceed with all functions, abort if empty
+	Policy string `json:"Policy"`
+}
+
+const (
+	AWSInvocationTypeRequestResponse = "RequestResponse"
+	AWSInvocationTypeEvent           = "Event"
+)
+
+// AWSLambdaConfig configures the deployment and the invocation of the functions through the AWS SDK. The credentials are
+// read from the environment or the shared configuration of the AWS CLI.
+type AWSLambdaConfig struct {
+	// the region of the environment or the shared configuration, us-east-1 if empty
+	Region string `json:"Region"`
+	// endpoint of a Lambda-compatible API replacing the one of the region, e.g., a local emulator
+	EndpointURL string `json:"EndpointURL"`
+	// container image of the functions, pushed to an ECR repository of the account
+	ImageURI string `json:"ImageURI"`
+	// execution role of the functions
+	RoleARN string `json:"RoleARN"`
+	// memory of every function, 1024 if 0
+	MemoryMB int `json:"MemoryMB"`
+	// 900 if 0
+	TimeoutSeconds int `json:"TimeoutSeconds"`
+	// RequestResponse waits for the response of the function, Event only queues the invocation, RequestResponse if empty
+	InvocationType string `json:"InvocationType"`
+}
+
+// CompletionCallbackConfig configures the HTTP server of the loader receiving the completions of asynchronous
+// invocations
+type CompletionCallbackConfig struct {
+	// :9000 if empty
+	ListenAddress string `json:"ListenAddress"`
+	// /completion if empty
+	Path string `json:"Path"`
+	// maximum time after the experiment to wait for the completions still pending
+	WaitToCollectMin int `json:"WaitToCollectMin"`
 }
 
 type WorkflowFunction struct {
@@ -104,9 +261,13 @@ type DirigentConfig struct {
 	BusyLoopOnSandboxStartup bool   `json:"BusyLoopOnSandboxStartup"`
 	PrepullMode              string `json:"PrepullMode"`
 
-	AsyncMode             bool   `json:"AsyncMode"`
-	AsyncResponseURL      string `json:"AsyncResponseURL"`
-	AsyncWaitToCollectMin int    `json:"AsyncWaitToCollectMin"`
+	AsyncMode        bool   `json:"AsyncMode"`
+	AsyncResponseURL string `json:"AsyncResponseURL"`
+	// maximum time after the experiment to collect the responses still pending
+	AsyncWaitToCollectMin int `json:"AsyncWaitToCollectMin"`
+	// backoff between the polls of a pending response, doubled after each poll up to the maximum
+	AsyncPollInitialBackoffMs int `json:"AsyncPollInitialBackoffMs"`
+	AsyncPollMaxBackoffMs     int `json:"AsyncPollMaxBackoffMs"`
 
 	RpsImage        string  `json:"RpsImage"`
 	RpsRequestedGpu int     `json:"RpsRequestedGpu"`
@@ -117,6 +278,81 @@ type DirigentConfig struct {
 	WorkflowConfigPath string `json:"WorkflowConfigPath"`
 }
 
+// PayloadSizeDistribution is a uniform distribution of request body sizes in KB.
+type PayloadSizeDistribution struct {
+	MinKB float64 `json:"MinKB"`
+	MaxKB float64 `json:"MaxKB"`
+}
+
+type RequestTemplate struct {
+	Method  string            `json:"Method"`
+	Path    string            `json:"Path"`
+	Headers map[string]string `json:"Headers"`
+
+	// BodyType is one of 'template' (default), 'random' or 'file'
+	BodyType      string                   `json:"BodyType"`
+	Body          string                   `json:"Body"`
+	BodyFile      string                   `json:"BodyFile"`
+	PayloadSizeKB *PayloadSizeDistribution `json:"PayloadSizeKB"`
+}
+
+type RequestTemplateConfig struct {
+	Default *RequestTemplate `json:"Default"`
+	// Functions overrides the default template, keyed by function name or trace hash
+	Functions map[string]*RequestTemplate `json:"Functions"`
+}
+
+type PayloadDistributionConfig struct {
+	Default *common.PayloadDistribution `json:"Default"`
+	// Functions overrides the default distribution, keyed by function name or trace hash
+	Functions map[string]*common.PayloadDistribution `json:"Functions"`
+}
+
+type AutoscalingConfig struct {
+	Default *common.AutoscalingSettings `json:"Default"`
+	// Classes override the default for the functions whose name starts with the key followed by a hyphen, e.g., the
+	// classes of the RPS specification or the proxy functions of vSwarm
+	Classes map[string]*common.AutoscalingSettings `json:"Classes"`
+	// Functions override the classes, keyed by function name or trace hash
+	Functions map[string]*common.AutoscalingSettings `json:"Functions"`
+}
+
+// KeepAliveModel describes for how long the platform keeps an idle function instance alive
+type KeepAliveModel struct {
+	// fixed or knative
+	Model string `json:"Model"`
+	// fixed - idle time after which an instance is removed, the cooldown of the functions if 0
+	Seconds float64 `json:"Seconds"`
+	// knative - autoscaler settings of the cluster, the Knative defaults if 0
+	StableWindowSeconds            float64 `json:"StableWindowSeconds"`
+	ScaleToZeroGracePeriodSeconds  float64 `json:"ScaleToZeroGracePeriodSeconds"`
+	ScaleToZeroPodRetentionSeconds float64 `json:"ScaleToZeroPodRetentionSeconds"`
+	// safety margin added to the keep-alive of the model
+	MarginPercentage float64 `json:"MarginPercentage"`
+}
+
+// RPSFunctionClass describes a group of identical functions of the RPS mode - one warm function and as many cold
+// functions as needed to sustain the cold start rate
+type RPSFunctionClass struct {
+	// used as the prefix of the names of the functions
+	Name                     string  `json:"Name"`
+	RpsTarget                float64 `json:"RpsTarget"`
+	ColdStartRatioPercentage float64 `json:"ColdStartRatioPercentage"`
+	CooldownSeconds          int     `json:"CooldownSeconds"`
+	// constant, uniform in [RuntimeMs, RuntimeMaxMs] or exponential with mean RuntimeMs
+	RuntimeDistribution string `json:"RuntimeDistribution"`
+	RuntimeMs           int    `json:"RuntimeMs"`
+	RuntimeMaxMs        int    `json:"RuntimeMaxMs"`
+	MemoryMB            int    `json:"MemoryMB"`
+	IterationMultiplier int    `json:"IterationMultiplier"`
+	// Dirigent only, RpsImage of the Dirigent configuration if empty
+	Image string `json:"Image"`
+}
+
+type RPSSpecification struct {
+	Classes []RPSFunctionClass `json:"Classes"`
+}
+
 func ReadConfigurationFile(path string) LoaderConfiguration {
 	byteValue, err := os.ReadFile(path)
 	if err != nil {
@@ -132,6 +368,17 @@ func ReadConfigurationFile(path string) LoaderConfiguration {
 	// set to lower in order to always match constants
 	config.Platform = strings.ToLower(config.Platform)
 
+	// validated before the deployment, which the readiness probe follows
+	if config.ReadinessProbe != nil {
+		switch config.ReadinessProbe.Policy {
+		case "":
+			config.ReadinessProbe.Policy = ReadinessPolicyAbort
+		case ReadinessPolicyAbort, ReadinessPolicyDrop, ReadinessPolicyProceed:
+		default:
+			log.Fatalf("Invalid readiness policy '%s'.", config.ReadinessProbe.Policy)
+		}
+	}
+
 	return config
 }
 
@@ -168,6 +415,82 @@ func ReadWorkflowConfig(path string) WorkflowConfig {
 	return config
 }
 
+func ReadRequestTemplateConfig(path string) *RequestTemplateConfig {
+	if path == "" {
+		return nil
+	}
+
+	byteValue, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatalf("Failed to read request template configuration: %v", err)
+	}
+
+	var config RequestTemplateConfig
+	err = json.Unmarshal(byteValue, &config)
+	if err != nil {
+		log.Fatalf("Failed to unmarshal request template configuration json: %v", err)
+	}
+
+	return &config
+}
+
+func ReadPayloadDistributionConfig(path string) *PayloadDistributionConfig {
+	if path == "" {
+		return nil
+	}
+
+	byteValue, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatalf("Failed to read payload distribution configuration: %v", err)
+	}
+
+	var config PayloadDistributionConfig
+	err = json.Unmarshal(byteValue, &config)
+	if err != nil {
+		log.Fatalf("Failed to unmarshal payload distribution configuration json: %v", err)
+	}
+
+	return &config
+}
+
+func ReadAutoscalingConfig(path string) *AutoscalingConfig {
+	if path == "" {
+		return nil
+	}
+
+	byteValue, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatalf("Failed to read autoscaling configuration: %v", err)
+	}
+
+	var config AutoscalingConfig
+	err = json.Unmarshal(byteValue, &config)
+	if err != nil {
+		log.Fatalf("Failed to unmarshal autoscaling configuration json: %v", err)
+	}
+
+	return &config
+}
+
+func ReadRPSSpecification(path string) *RPSSpecification {
+	if path == "" {
+		return nil
+	}
+
+	byteValue, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatalf("Failed to read RPS specification: %v", err)
+	}
+
+	var config RPSSpecification
+	err = json.Unmarshal(byteValue, &config)
+	if err != nil {
+		log.Fatalf("Failed to unmarshal RPS specification json: %v", err)
+	}
+
+	return &config
+}
+
 func ReadDirigentConfig(cfg *LoaderConfiguration) *DirigentConfig {
 	if cfg.Platform != common.PlatformDirigent {
 		return nil
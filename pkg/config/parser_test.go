@@ -28,6 +28,7 @@ import (
 	"fmt"
 	"github.com/vhive-serverless/loader/pkg/common"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -71,3 +72,15 @@ func TestConfigParser(t *testing.T) {
 		t.Error("Unexpected configuration read.")
 	}
 }
+
+func TestReadinessProbeDefaultPolicy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"Platform": "Knative", "ReadinessProbe": {"Attempts": 3}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config := ReadConfigurationFile(path)
+	if config.ReadinessProbe == nil || config.ReadinessProbe.Policy != ReadinessPolicyAbort {
+		t.Errorf("Expected the readiness probe to abort by default, got %+v.", config.ReadinessProbe)
+	}
+}
@@ -0,0 +1,288 @@
+package driver
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	"github.com/vhive-serverless/loader/pkg/driver/clients"
+	"github.com/vhive-serverless/loader/pkg/metric"
+)
+
+const (
+	defaultCompletionCallbackAddress = ":9000"
+	defaultCompletionCallbackPath    = "/completion"
+
+	// header carrying the ID of the invocation in the completions sent by Dirigent
+	invocationIDHeader = "X-Invocation-ID"
+
+	// lower bound of how long a completion is kept waiting for the submission of its invocation
+	minCompletionRetention = time.Minute
+)
+
+// asyncCompletion is a completion notification received before or after the invocation has been submitted
+type asyncCompletion struct {
+	receivedAt time.Time
+	// completes the record of the invocation and returns whether it succeeded
+	apply func(record *metric.ExecutionRecord) bool
+}
+
+// asyncCompletionReceiver serves the completion callbacks of the asynchronous invocations, matches them by invocation
+// ID to the submitted invocations and writes the completed records to the log
+type asyncCompletionReceiver struct {
+	platform string
+	listener net.Listener
+	server   *http.Server
+
+	submitted *common.LockFreeQueue[*metric.ExecutionRecord]
+	logCh     chan *metric.ExecutionRecord
+
+	mutex       sync.Mutex
+	completions map[string]*asyncCompletion
+	// invocations whose completion has not been received yet
+	pending map[string]*metric.ExecutionRecord
+
+	// completions not matching any invocation, e.g., of the readiness probes or of another run, are discarded after
+	// the retention or once all the invocations have been submitted
+	retention time.Duration
+	discarded int
+
+	deadline chan time.Time
+	done     chan struct{}
+}
+
+func newAsyncCompletionReceiver(cfg *config.CompletionCallbackConfig, platform string, submitted *common.LockFreeQueue[*metric.ExecutionRecord], logCh chan *metric.ExecutionRecord) *asyncCompletionReceiver {
+	if platform != common.PlatformDirigent && platform != common.PlatformAWSLambda {
+		log.Fatalf("Completion callbacks are not supported on platform %s.", platform)
+	}
+
+	address := cfg.ListenAddress
+	if address == "" {
+		address = defaultCompletionCallbackAddress
+	}
+	path := cfg.Path
+	if path == "" {
+		path = defaultCompletionCallbackPath
+	}
+
+	// bound before the experiment starts, so that no completion is missed
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		log.Fatalf("Failed to listen for completion callbacks on %s - %v", address, err)
+	}
+
+	r := &asyncCompletionReceiver{
+		platform: platform,
+		listener: listener,
+
+		submitted: submitted,
+		logCh:     logCh,
+
+		completions: make(map[string]*asyncCompletion),
+		pending:     make(map[string]*metric.ExecutionRecord),
+
+		retention: max(time.Duration(cfg.WaitToCollectMin)*time.Minute, minCompletionRetention),
+
+		deadline: make(chan time.Time, 1),
+		done:     make(chan struct{}),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(path, r.handleCompletion)
+	r.server = &http.Server{Handler: mux}
+
+	log.Infof("Receiving completion callbacks on %s%s", listener.Addr(), path)
+
+	return r
+}
+
+// Finish waits until the completions of all the submitted invocations are received, or until the timeout passes, after
+// which the invocations without a completion are recorded as timed out. It must be called once all the invocations have
+// been submitted.
+func (r *asyncCompletionReceiver) Finish(timeout time.Duration) {
+	r.deadline <- time.Now().Add(timeout)
+	<-r.done
+}
+
+func (r *asyncCompletionReceiver) Run() {
+	defer close(r.done)
+	defer func() {
+		if r.discarded > 0 {
+			log.Warnf("Discarded %d completions not matching any invocation.", r.discarded)
+		}
+	}()
+
+	go func() {
+		if err := r.server.Serve(r.listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Errorf("Completion callback server failed - %v", err)
+		}
+	}()
+	defer r.server.Close()
+
+	ticker := time.NewTicker(asyncCollectorTick)
+	defer ticker.Stop()
+
+	var deadline time.Time
+	finishing := false
+
+	for {
+		started := false
+		if !finishing {
+			select {
+			case deadline = <-r.deadline:
+				finishing, started = true, true
+			default:
+			}
+		}
+
+		// taken after checking the deadline, so that all the invocations are pending once finishing
+		for r.submitted.Length() > 0 {
+			record := r.submitted.Dequeue()
+
+			r.mutex.Lock()
+			r.pending[record.AsyncResponseID] = record
+			r.mutex.Unlock()
+		}
+		r.matchCompletions(finishing)
+
+		if started {
+			log.Infof("Waiting for the completions of %d invocations...", r.pendingCount())
+		}
+		if finishing && r.pendingCount() == 0 {
+			log.Infof("Received the completions of all invocations")
+			return
+		}
+
+		if finishing && !time.Now().Before(deadline) {
+			r.mutex.Lock()
+			expired := r.pending
+			r.pending = make(map[string]*metric.ExecutionRecord)
+			r.mutex.Unlock()
+
+			for id, record := range expired {
+				log.Errorf("No completion of invocation %s received before the deadline.", id)
+				record.FunctionTimeout = true
+				record.AsyncResponseID = ""
+				r.logCh <- record
+			}
+
+			return
+		}
+
+		<-ticker.C
+	}
+}
+
+func (r *asyncCompletionReceiver) pendingCount() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return len(r.pending)
+}
+
+// matchCompletions completes the records of the submitted invocations whose completion has been received. The
+// unmatched completions older than the retention, or all of them if no invocation will be submitted anymore, are
+// discarded in the same step, so that a completion received in between is never discarded while its invocation is
+// pending.
+func (r *asyncCompletionReceiver) matchCompletions(finishing bool) {
+	var completed []*metric.ExecutionRecord
+	now := time.Now()
+
+	r.mutex.Lock()
+	for id, completion := range r.completions {
+		record, ok := r.pending[id]
+		if !ok {
+			if finishing || now.Sub(completion.receivedAt) > r.retention {
+				delete(r.completions, id)
+				r.discarded++
+			}
+			continue
+		}
+		delete(r.pending, id)
+		delete(r.completions, id)
+
+		record.ResponseAvailableTime = completion.receivedAt.UnixMicro()
+		record.AsyncLatency = record.ResponseAvailableTime - record.StartTime
+		if !completion.apply(record) {
+			record.FunctionTimeout = true
+		}
+		completed = append(completed, record)
+	}
+	r.mutex.Unlock()
+
+	for _, record := range completed {
+		r.logCh <- record
+	}
+}
+
+func (r *asyncCompletionReceiver) handleCompletion(w http.ResponseWriter, req *http.Request) {
+	receivedAt := time.Now()
+
+	if req.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	id, apply, err := r.parseCompletion(req, body)
+	if err != nil {
+		log.Warnf("Invalid completion callback - %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	r.mutex.Lock()
+	if _, ok := r.completions[id]; !ok {
+		r.completions[id] = &asyncCompletion{receivedAt: receivedAt, apply: apply}
+	}
+	r.mutex.Unlock()
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// parseCompletion returns the ID of the invocation and how its record is completed. Dirigent sends the response of the
+// function with the ID in a header or in the query, while Lambda sends the destination record of the invocation.
+func (r *asyncCompletionReceiver) parseCompletion(req *http.Request, body []byte) (string, func(*metric.ExecutionRecord) bool, error) {
+	switch r.platform {
+	case common.PlatformAWSLambda:
+		destinationRecord, err := clients.ParseLambdaDestinationRecord(body)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid destination record - %v", err)
+		}
+
+		return destinationRecord.RequestContext.RequestID, destinationRecord.SetResponse, nil
+	default:
+		id := req.Header.Get(invocationIDHeader)
+		if id == "" {
+			id = req.URL.Query().Get("id")
+		}
+		if id == "" {
+			return "", nil, fmt.Errorf("missing invocation ID")
+		}
+
+		e2e, _ := strconv.Atoi(req.Header.Get("Duration-Microseconds"))
+
+		return id, func(record *metric.ExecutionRecord) bool {
+			if err := clients.DeserializeDirigentResponse(body, record); err != nil {
+				log.Errorf("Failed to deserialize Dirigent response - %v - %v", string(body), err)
+				return false
+			}
+			record.UserCodeExecutionMs = int64(e2e)
+
+			return true
+		}, nil
+	}
+}
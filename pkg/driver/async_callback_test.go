@@ -0,0 +1,183 @@
+package driver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	"github.com/vhive-serverless/loader/pkg/driver/clients"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+func postCompletion(t *testing.T, url string, id string, body []byte) int {
+	req, _ := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if id != "" {
+		req.Header.Set(invocationIDHeader, id)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	return resp.StatusCode
+}
+
+func TestAsyncCompletionReceiverDirigent(t *testing.T) {
+	submitted := common.NewLockFreeQueue[*mc.ExecutionRecord]()
+	logCh := make(chan *mc.ExecutionRecord, 3)
+	receiver := newAsyncCompletionReceiver(&config.CompletionCallbackConfig{ListenAddress: "127.0.0.1:0"},
+		common.PlatformDirigent, submitted, logCh)
+	url := fmt.Sprintf("http://%s%s", receiver.listener.Addr(), defaultCompletionCallbackPath)
+
+	go receiver.Run()
+
+	response, _ := json.Marshal(clients.FunctionResponse{Function: "f", ExecutionTime: 1500})
+
+	// the completion of a short invocation may be received before the invocation is submitted
+	start := time.Now().UnixMicro()
+	if status := postCompletion(t, url, "early", response); status != http.StatusOK {
+		t.Fatalf("Expected the completion to be accepted, got %d.", status)
+	}
+	submitted.Enqueue(&mc.ExecutionRecord{ExecutionRecordBase: mc.ExecutionRecordBase{StartTime: start}, AsyncResponseID: "early"})
+	submitted.Enqueue(&mc.ExecutionRecord{ExecutionRecordBase: mc.ExecutionRecordBase{StartTime: start}, AsyncResponseID: "late"})
+	submitted.Enqueue(&mc.ExecutionRecord{ExecutionRecordBase: mc.ExecutionRecordBase{StartTime: start}, AsyncResponseID: "lost"})
+
+	early := <-logCh
+	if early.AsyncResponseID != "early" || early.FunctionTimeout || early.ActualDuration != 1500 || early.AsyncLatency < 0 {
+		t.Errorf("Unexpected record of the early invocation %+v.", early)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	if status := postCompletion(t, url+"?id=late", "", response); status != http.StatusOK {
+		t.Fatalf("Expected the completion to be accepted, got %d.", status)
+	}
+	if status := postCompletion(t, url, "", response); status != http.StatusBadRequest {
+		t.Errorf("Expected the completion without an ID to be rejected, got %d.", status)
+	}
+	// e.g., of a readiness probe
+	if status := postCompletion(t, url, "stray", response); status != http.StatusOK {
+		t.Fatalf("Expected the completion to be accepted, got %d.", status)
+	}
+
+	late := <-logCh
+	if late.AsyncResponseID != "late" || late.FunctionTimeout || late.AsyncLatency < 10000 ||
+		late.ResponseAvailableTime != late.StartTime+late.AsyncLatency {
+
+		t.Errorf("Unexpected record of the late invocation %+v.", late)
+	}
+
+	receiver.Finish(100 * time.Millisecond)
+
+	lost := <-logCh
+	if !lost.FunctionTimeout || lost.ResponseAvailableTime != 0 {
+		t.Errorf("Expected the invocation without a completion to time out, got %+v.", lost)
+	}
+	if len(receiver.completions) != 0 || receiver.discarded != 1 {
+		t.Errorf("Expected the unmatched completion to be discarded, got %d left and %d discarded.", len(receiver.completions), receiver.discarded)
+	}
+}
+
+func TestAsyncCompletionReceiverRetention(t *testing.T) {
+	receiver := newAsyncCompletionReceiver(&config.CompletionCallbackConfig{ListenAddress: "127.0.0.1:0"},
+		common.PlatformDirigent, common.NewLockFreeQueue[*mc.ExecutionRecord](), make(chan *mc.ExecutionRecord))
+	defer receiver.listener.Close()
+
+	if receiver.retention != minCompletionRetention {
+		t.Errorf("Expected the minimum retention, got %v.", receiver.retention)
+	}
+
+	receiver.completions["old"] = &asyncCompletion{receivedAt: time.Now().Add(-2 * minCompletionRetention)}
+	receiver.completions["recent"] = &asyncCompletion{receivedAt: time.Now()}
+
+	receiver.matchCompletions(false)
+	if _, ok := receiver.completions["recent"]; !ok || len(receiver.completions) != 1 || receiver.discarded != 1 {
+		t.Errorf("Expected only the completion older than the retention to be discarded, got %v.", receiver.completions)
+	}
+}
+
+func TestAsyncCompletionReceiverFinishing(t *testing.T) {
+	submitted := common.NewLockFreeQueue[*mc.ExecutionRecord]()
+	logCh := make(chan *mc.ExecutionRecord, 1)
+	receiver := newAsyncCompletionReceiver(&config.CompletionCallbackConfig{ListenAddress: "127.0.0.1:0"},
+		common.PlatformDirigent, submitted, logCh)
+	url := fmt.Sprintf("http://%s%s", receiver.listener.Addr(), defaultCompletionCallbackPath)
+
+	go receiver.Run()
+
+	submitted.Enqueue(&mc.ExecutionRecord{AsyncResponseID: "slow"})
+	finished := make(chan struct{})
+	go func() {
+		receiver.Finish(time.Minute)
+		close(finished)
+	}()
+
+	// received while finishing, when all the unmatched completions are discarded
+	time.Sleep(3 * asyncCollectorTick)
+	response, _ := json.Marshal(clients.FunctionResponse{Function: "f", ExecutionTime: 1500})
+	if status := postCompletion(t, url, "slow", response); status != http.StatusOK {
+		t.Fatalf("Expected the completion to be accepted, got %d.", status)
+	}
+
+	select {
+	case <-finished:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Expected the receiver to finish once the completion is received.")
+	}
+
+	if record := <-logCh; record.FunctionTimeout || record.ActualDuration != 1500 {
+		t.Errorf("Expected the invocation to complete, got %+v.", record)
+	}
+	if receiver.discarded != 0 {
+		t.Errorf("Expected no completion to be discarded, got %d.", receiver.discarded)
+	}
+}
+
+func TestAsyncCompletionReceiverAWSLambda(t *testing.T) {
+	submitted := common.NewLockFreeQueue[*mc.ExecutionRecord]()
+	logCh := make(chan *mc.ExecutionRecord, 2)
+	receiver := newAsyncCompletionReceiver(&config.CompletionCallbackConfig{ListenAddress: "127.0.0.1:0", Path: "/lambda"},
+		common.PlatformAWSLambda, submitted, logCh)
+	url := fmt.Sprintf("http://%s/lambda", receiver.listener.Addr())
+
+	go receiver.Run()
+
+	submitted.Enqueue(&mc.ExecutionRecord{AsyncResponseID: "request-1"})
+	submitted.Enqueue(&mc.ExecutionRecord{AsyncResponseID: "request-2"})
+
+	body, _ := json.Marshal(clients.HTTPResBody{DurationInMicroSec: 301000, MemoryUsageInKb: 2048})
+	payload, _ := json.Marshal(map[string]interface{}{"statusCode": http.StatusOK, "body": string(body)})
+
+	// delivered as the detail of an EventBridge event
+	succeeded := fmt.Sprintf(`{"detail-type": "Lambda Function Invocation Result - Success", "detail": {"requestContext": {"requestId": "request-1", "condition": "Success"}, "responsePayload": %s}}`, payload)
+	failed := `{"requestContext": {"requestId": "request-2", "condition": "RetriesExhausted"}, "responsePayload": {"errorMessage": "panic"}}`
+
+	for _, completion := range []string{succeeded, failed} {
+		if status := postCompletion(t, url, "", []byte(completion)); status != http.StatusOK {
+			t.Fatalf("Expected the destination record to be accepted, got %d.", status)
+		}
+	}
+	if status := postCompletion(t, url, "", []byte(`{"requestContext": {}}`)); status != http.StatusBadRequest {
+		t.Errorf("Expected the destination record without a request ID to be rejected, got %d.", status)
+	}
+
+	receiver.Finish(time.Minute)
+
+	records := map[string]*mc.ExecutionRecord{}
+	for len(logCh) > 0 {
+		record := <-logCh
+		records[record.AsyncResponseID] = record
+	}
+	if record := records["request-1"]; record == nil || record.FunctionTimeout || record.ActualDuration != 301000 {
+		t.Errorf("Unexpected record of the successful invocation %+v.", record)
+	}
+	if record := records["request-2"]; record == nil || !record.FunctionTimeout {
+		t.Errorf("Expected the failed invocation to be recorded as failed, got %+v.", record)
+	}
+}
@@ -3,10 +3,11 @@ package driver
 import (
 	"bytes"
 	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
 	"github.com/vhive-serverless/loader/pkg/driver/clients"
 	"github.com/vhive-serverless/loader/pkg/metric"
 	"io"
-	"math"
 	"net"
 	"net/http"
 	"strconv"
@@ -14,80 +15,212 @@ import (
 	"time"
 )
 
-func (d *Driver) writeAsyncRecordsToLog(logCh chan *metric.ExecutionRecord) {
-	const batchSize = 50
-
-	client := &http.Client{
-		Timeout: 5 * time.Second,
-		Transport: &http.Transport{
-			DialContext: (&net.Dialer{
-				Timeout: 2 * time.Second,
-			}).DialContext,
-			IdleConnTimeout:     time.Second,
-			MaxIdleConns:        batchSize,
-			MaxIdleConnsPerHost: batchSize,
+const (
+	defaultAsyncPollInitialBackoffMs = 500
+	defaultAsyncPollMaxBackoffMs     = 10000
+
+	// number of responses fetched in parallel
+	asyncPollBatchSize = 50
+	// period at which the collector takes the submitted invocations and polls the responses that are due
+	asyncCollectorTick = 100 * time.Millisecond
+)
+
+// asyncCollector completes the records of the asynchronous invocations submitted during the experiment
+type asyncCollector interface {
+	Run()
+	Finish(timeout time.Duration)
+}
+
+// pendingAsyncResponse is a submitted invocation whose response has not been fetched yet
+type pendingAsyncResponse struct {
+	record   *metric.ExecutionRecord
+	backoff  time.Duration
+	nextPoll time.Time
+}
+
+// asyncResponseCollector polls the responses of the asynchronous invocations while the experiment runs, with an
+// exponential backoff per invocation, and writes the completed records to the log
+type asyncResponseCollector struct {
+	client         *http.Client
+	endpoint       string
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+
+	submitted *common.LockFreeQueue[*metric.ExecutionRecord]
+	logCh     chan *metric.ExecutionRecord
+
+	deadline chan time.Time
+	done     chan struct{}
+}
+
+func newAsyncResponseCollector(cfg *config.DirigentConfig, submitted *common.LockFreeQueue[*metric.ExecutionRecord], logCh chan *metric.ExecutionRecord) *asyncResponseCollector {
+	initialBackoffMs := cfg.AsyncPollInitialBackoffMs
+	if initialBackoffMs <= 0 {
+		initialBackoffMs = defaultAsyncPollInitialBackoffMs
+	}
+	maxBackoffMs := cfg.AsyncPollMaxBackoffMs
+	if maxBackoffMs <= 0 {
+		maxBackoffMs = defaultAsyncPollMaxBackoffMs
+	}
+	if maxBackoffMs < initialBackoffMs {
+		maxBackoffMs = initialBackoffMs
+	}
+
+	return &asyncResponseCollector{
+		client: &http.Client{
+			Timeout: 5 * time.Second,
+			Transport: &http.Transport{
+				DialContext: (&net.Dialer{
+					Timeout: 2 * time.Second,
+				}).DialContext,
+				IdleConnTimeout:     time.Second,
+				MaxIdleConns:        asyncPollBatchSize,
+				MaxIdleConnsPerHost: asyncPollBatchSize,
+			},
 		},
+		endpoint:       cfg.AsyncResponseURL,
+		initialBackoff: time.Duration(initialBackoffMs) * time.Millisecond,
+		maxBackoff:     time.Duration(maxBackoffMs) * time.Millisecond,
+
+		submitted: submitted,
+		logCh:     logCh,
+
+		deadline: make(chan time.Time, 1),
+		done:     make(chan struct{}),
 	}
+}
 
-	currentBatch := 0
-	totalBatches := int(math.Ceil(float64(d.AsyncRecords.Length()) / float64(batchSize)))
+// Finish waits until all the submitted invocations are collected, or until the timeout passes, after which the pending
+// responses are polled a last time and the invocations still without a response are recorded as timed out. It must be
+// called once all the invocations have been submitted.
+func (c *asyncResponseCollector) Finish(timeout time.Duration) {
+	c.deadline <- time.Now().Add(timeout)
+	<-c.done
+}
+
+func (c *asyncResponseCollector) Run() {
+	defer close(c.done)
+
+	ticker := time.NewTicker(asyncCollectorTick)
+	defer ticker.Stop()
 
-	log.Infof("Gathering functions responses...")
-	for d.AsyncRecords.Length() > 0 {
-		currentBatch++
+	var pending []*pendingAsyncResponse
+	var deadline time.Time
+	finishing := false
 
-		toProcess := batchSize
-		if d.AsyncRecords.Length() < batchSize {
-			toProcess = d.AsyncRecords.Length()
+	for {
+		now := time.Now()
+		for c.submitted.Length() > 0 {
+			pending = append(pending, &pendingAsyncResponse{
+				record:   c.submitted.Dequeue(),
+				backoff:  c.initialBackoff,
+				nextPoll: now.Add(c.initialBackoff),
+			})
 		}
 
-		wg := sync.WaitGroup{}
-		wg.Add(toProcess)
-
-		for i := 0; i < toProcess; i++ {
-			go func() {
-				defer wg.Done()
-
-				start := time.Now()
-
-				record := d.AsyncRecords.Dequeue()
-				response, e2e := d.getAsyncResponseData(
-					client,
-					d.Configuration.DirigentConfiguration.AsyncResponseURL,
-					record.AsyncResponseID,
-				)
-
-				if string(response) != "" {
-					err := clients.DeserializeDirigentResponse(response, record)
-					if err != nil {
-						log.Errorf("Failed to deserialize Dirigent response - %v - %v", string(response), err)
-					}
-				} else {
-					record.FunctionTimeout = true
-					record.AsyncResponseID = ""
-					log.Errorf("Failed to fetch response. The function has probably not yet completed.")
-				}
-
-				// loader send request + request e2e + loader get response
-				timeToFetchResponse := time.Since(start).Microseconds()
-				record.UserCodeExecutionMs = int64(e2e)
-				record.TimeToGetResponseMs = timeToFetchResponse
-				record.ResponseTime += int64(e2e)
-				record.ResponseTime += timeToFetchResponse
-
-				logCh <- record
-			}()
+		if !finishing {
+			select {
+			case deadline = <-c.deadline:
+				finishing = true
+				log.Infof("Gathering the %d pending function responses...", len(pending))
+			default:
+			}
+		}
+		// the submission queue is only complete once finishing
+		if finishing && len(pending) == 0 && c.submitted.Length() == 0 {
+			log.Infof("Finished gathering async response answers")
+			return
 		}
 
-		wg.Wait()
+		expired := finishing && !now.Before(deadline)
+		pending = c.poll(pending, now, expired)
 
-		log.Infof("Processed %d/%d batches of async response gatherings", currentBatch, totalBatches)
+		if expired {
+			for _, p := range pending {
+				log.Errorf("Failed to fetch response %s before the deadline. The function has probably not yet completed.", p.record.AsyncResponseID)
+				p.record.FunctionTimeout = true
+				p.record.AsyncResponseID = ""
+				c.logCh <- p.record
+			}
+			log.Infof("Finished gathering async response answers")
+			return
+		}
+
+		<-ticker.C
+	}
+}
+
+// poll fetches the responses that are due, or all of them if forced, and returns the invocations still pending
+func (c *asyncResponseCollector) poll(pending []*pendingAsyncResponse, now time.Time, force bool) []*pendingAsyncResponse {
+	var due, remaining []*pendingAsyncResponse
+	for _, p := range pending {
+		if force || !now.Before(p.nextPoll) {
+			due = append(due, p)
+		} else {
+			remaining = append(remaining, p)
+		}
+	}
+	if len(due) == 0 {
+		return pending
+	}
+
+	var mutex sync.Mutex
+	queue := make(chan struct{}, asyncPollBatchSize)
+	wg := sync.WaitGroup{}
+	for _, p := range due {
+		wg.Add(1)
+		go func() {
+			queue <- struct{}{}
+
+			defer wg.Done()
+			defer func() { <-queue }()
+
+			if c.fetch(p.record) {
+				c.logCh <- p.record
+				return
+			}
+
+			p.backoff = min(2*p.backoff, c.maxBackoff)
+			p.nextPoll = time.Now().Add(p.backoff)
+
+			mutex.Lock()
+			remaining = append(remaining, p)
+			mutex.Unlock()
+		}()
 	}
+	wg.Wait()
 
-	log.Infof("Finished gathering async reponse answers")
+	return remaining
 }
 
-func (d *Driver) getAsyncResponseData(client *http.Client, endpoint string, guid string) ([]byte, int) {
+// fetch polls the response of the invocation and completes its record, returning false if it is not available yet
+func (c *asyncResponseCollector) fetch(record *metric.ExecutionRecord) bool {
+	start := time.Now()
+
+	record.AsyncPolls++
+	response, e2e := getAsyncResponseData(c.client, c.endpoint, record.AsyncResponseID)
+	if string(response) == "" {
+		return false
+	}
+
+	record.ResponseAvailableTime = start.UnixMicro()
+	record.AsyncLatency = record.ResponseAvailableTime - record.StartTime
+	err := clients.DeserializeDirigentResponse(response, record)
+	if err != nil {
+		log.Errorf("Failed to deserialize Dirigent response - %v - %v", string(response), err)
+	}
+
+	// loader send request + request e2e + loader get response
+	timeToFetchResponse := time.Since(start).Microseconds()
+	record.UserCodeExecutionMs = int64(e2e)
+	record.TimeToGetResponseMs = timeToFetchResponse
+	record.ResponseTime += int64(e2e)
+	record.ResponseTime += timeToFetchResponse
+
+	return true
+}
+
+func getAsyncResponseData(client *http.Client, endpoint string, guid string) ([]byte, int) {
 	req, err := http.NewRequest("GET", "http://"+endpoint, bytes.NewReader([]byte(guid)))
 	if err != nil {
 		log.Errorf("Failed to retrieve Dirigent response for %s - %v", guid, err)
@@ -99,7 +232,7 @@ func (d *Driver) getAsyncResponseData(client *http.Client, endpoint string, guid
 
 	resp, err := client.Do(req)
 	if err != nil {
-		log.Errorf("Failed to retrieve Dirigent response for %s - %v", guid, err)
+		log.Debugf("Failed to retrieve Dirigent response for %s - %v", guid, err)
 		return []byte{}, 0
 	}
 
@@ -0,0 +1,143 @@
+package driver
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	"github.com/vhive-serverless/loader/pkg/driver/clients"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+func TestAsyncResponseCollector(t *testing.T) {
+	// number of polls before the response of each invocation is available, where 'lost' never completes
+	completesAfter := map[string]int{"fast": 1, "slow": 3, "lost": 1000}
+
+	var mutex sync.Mutex
+	polls := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		guid := string(body)
+
+		mutex.Lock()
+		polls[guid]++
+		completed := polls[guid] >= completesAfter[guid]
+		mutex.Unlock()
+
+		if completed {
+			w.Header().Set("Duration-Microseconds", "2000")
+			_ = json.NewEncoder(w).Encode(clients.FunctionResponse{Function: guid, ExecutionTime: 1500})
+		}
+	}))
+	defer server.Close()
+
+	submitted := common.NewLockFreeQueue[*mc.ExecutionRecord]()
+	logCh := make(chan *mc.ExecutionRecord, 3)
+	collector := newAsyncResponseCollector(&config.DirigentConfig{
+		AsyncResponseURL:          strings.TrimPrefix(server.URL, "http://"),
+		AsyncPollInitialBackoffMs: 10,
+		AsyncPollMaxBackoffMs:     40,
+	}, submitted, logCh)
+
+	go collector.Run()
+
+	start := time.Now()
+	for _, guid := range []string{"fast", "slow", "lost"} {
+		record := &mc.ExecutionRecord{AsyncResponseID: guid}
+		record.ResponseTime = 100
+		submitted.Enqueue(record)
+	}
+
+	// the responses are collected while the experiment is running
+	fast := <-logCh
+	if fast.AsyncResponseID != "fast" || fast.FunctionTimeout || fast.AsyncPolls != 1 || fast.ActualDuration != 1500 ||
+		fast.UserCodeExecutionMs != 2000 || fast.ResponseTime < 2100 || fast.ResponseAvailableTime < start.UnixMicro() {
+
+		t.Errorf("Unexpected record of the fast invocation %+v.", fast)
+	}
+	slow := <-logCh
+	if slow.AsyncResponseID != "slow" || slow.FunctionTimeout || slow.AsyncPolls != 3 || slow.ResponseAvailableTime <= fast.ResponseAvailableTime {
+		t.Errorf("Unexpected record of the slow invocation %+v.", slow)
+	}
+
+	collector.Finish(200 * time.Millisecond)
+
+	lost := <-logCh
+	if !lost.FunctionTimeout || lost.ResponseAvailableTime != 0 || lost.AsyncPolls < 3 {
+		t.Errorf("Expected the invocation without a response to time out, got %+v.", lost)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected the collection to end at the deadline, took %v.", elapsed)
+	}
+}
+
+func TestAsyncResponseCollectorFinishesEarly(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(clients.FunctionResponse{Function: "f", ExecutionTime: 1})
+	}))
+	defer server.Close()
+
+	submitted := common.NewLockFreeQueue[*mc.ExecutionRecord]()
+	logCh := make(chan *mc.ExecutionRecord, 10)
+	collector := newAsyncResponseCollector(&config.DirigentConfig{
+		AsyncResponseURL:          strings.TrimPrefix(server.URL, "http://"),
+		AsyncPollInitialBackoffMs: 10,
+	}, submitted, logCh)
+
+	go collector.Run()
+
+	for i := 0; i < 10; i++ {
+		submitted.Enqueue(&mc.ExecutionRecord{AsyncResponseID: "f"})
+	}
+
+	start := time.Now()
+	collector.Finish(time.Minute)
+
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected the collection to end once all responses are fetched, took %v.", elapsed)
+	}
+	if len(logCh) != 10 {
+		t.Errorf("Expected 10 collected records, got %d.", len(logCh))
+	}
+}
+
+func TestAsyncRecordsSLOEvaluation(t *testing.T) {
+	d := &Driver{sloEvaluator: mc.NewSLOEvaluator([]config.SLO{
+		{Name: "latency", Metric: config.SLOMetricResponseTime, Percentile: 100, Threshold: 100},
+		{Name: "failures", Metric: config.SLOMetricFailureRate, Threshold: 10},
+	})}
+	function := &common.Function{Name: "f"}
+
+	// submitted within 5 ms, but completed after 50 ms or timed out
+	completed := &mc.ExecutionRecord{ExecutionRecordBase: mc.ExecutionRecordBase{ResponseTime: 5000}, AsyncLatency: 50000}
+	timedOut := &mc.ExecutionRecord{ExecutionRecordBase: mc.ExecutionRecordBase{ResponseTime: 5000, FunctionTimeout: true}}
+	unknown := &mc.ExecutionRecord{}
+	d.asyncFunctions.Store(completed, function)
+	d.asyncFunctions.Store(timedOut, function)
+
+	completedCh := make(chan *mc.ExecutionRecord)
+	logCh := make(chan *mc.ExecutionRecord, 3)
+	go func() {
+		for _, record := range []*mc.ExecutionRecord{completed, timedOut, unknown} {
+			completedCh <- record
+		}
+		close(completedCh)
+	}()
+	d.completeAsyncRecords(completedCh, logCh)
+
+	if len(logCh) != 3 {
+		t.Errorf("Expected all the records to be written, got %d.", len(logCh))
+	}
+
+	verdicts := d.sloEvaluator.Evaluate()
+	if len(verdicts) != 2 || verdicts[0].Value != 50 || verdicts[0].Samples != 1 || verdicts[1].Value != 50 || verdicts[1].Samples != 2 {
+		t.Errorf("Expected the completion latency and the timed out invocation to be evaluated, got %+v.", verdicts)
+	}
+}
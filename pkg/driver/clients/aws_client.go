@@ -1,58 +1,302 @@
 package clients
 
 import (
+	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
 	log "github.com/sirupsen/logrus"
 	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
 	mc "github.com/vhive-serverless/loader/pkg/metric"
-	"io"
-	"sync"
 )
 
+const defaultAWSRegion = "us-east-1"
+
+type HTTPResBody struct {
+	DurationInMicroSec uint32 `json:"DurationInMicroSec"`
+	MemoryUsageInKb    uint32 `json:"MemoryUsageInKb"`
+}
+
+// lambdaProxyResponse is the response of the trace function, which is written for function URLs
+type lambdaProxyResponse struct {
+	StatusCode int    `json:"statusCode"`
+	Body       string `json:"body"`
+}
+
+// lambdaReport holds the measurements of the REPORT line Lambda appends to the log of every invocation
+type lambdaReport struct {
+	BilledDurationMs float64
+	InitDurationMs   float64
+	MaxMemoryUsedMB  uint32
+}
+
+// NewAWSLambdaClient creates a Lambda client with the credentials of the environment or the shared configuration of
+// the AWS CLI
+func NewAWSLambdaClient(cfg *config.AWSLambdaConfig) *lambda.Client {
+	if cfg == nil {
+		log.Fatal("Missing AWSLambda in loader configuration!")
+	}
+
+	httpClient := awshttp.NewBuildableClient().WithTransportOptions(func(transport *http.Transport) {
+		transport.MaxIdleConnsPerHost = 100
+	})
+	awsConfig, err := awsconfig.LoadDefaultConfig(context.Background(), awsconfig.WithHTTPClient(httpClient))
+	if err != nil {
+		log.Fatalf("Failed to load the AWS configuration - %v", err)
+	}
+
+	if cfg.Region != "" {
+		awsConfig.Region = cfg.Region
+	} else if awsConfig.Region == "" {
+		awsConfig.Region = defaultAWSRegion
+	}
+
+	return lambda.NewFromConfig(awsConfig, func(options *lambda.Options) {
+		if cfg.EndpointURL != "" {
+			options.BaseEndpoint = aws.String(cfg.EndpointURL)
+		}
+	})
+}
+
 type awsLambdaInvoker struct {
-	announceDoneExe *sync.WaitGroup
+	client         *lambda.Client
+	invocationType types.InvocationType
+
+	recordsMutex sync.Mutex
+	records      []*mc.ExecutionRecordAWSLambda
 }
 
-func newAWSLambdaInvoker(announceDoneExe *sync.WaitGroup) *awsLambdaInvoker {
+func newAWSLambdaInvoker(client *lambda.Client, cfg *config.AWSLambdaConfig) *awsLambdaInvoker {
+	invocationType := types.InvocationTypeRequestResponse
+	switch cfg.InvocationType {
+	case "", config.AWSInvocationTypeRequestResponse:
+	case config.AWSInvocationTypeEvent:
+		invocationType = types.InvocationTypeEvent
+	default:
+		log.Fatalf("Invalid AWS Lambda invocation type '%s'.", cfg.InvocationType)
+	}
+
 	return &awsLambdaInvoker{
-		announceDoneExe: announceDoneExe,
+		client:         client,
+		invocationType: invocationType,
 	}
 }
 
 func (i *awsLambdaInvoker) Invoke(function *common.Function, runtimeSpec *common.RuntimeSpecification) (bool, *mc.ExecutionRecord) {
 	log.Tracef("(Invoke)\t %s: %d[ms], %d[MiB]", function.Name, runtimeSpec.Runtime, runtimeSpec.Memory)
 
+	// the trace function expects the event of a function URL
 	dataString := fmt.Sprintf(`{"RuntimeInMilliSec": %d, "MemoryInMebiBytes": %d}`, runtimeSpec.Runtime, runtimeSpec.Memory)
-	success, executionRecordBase, res := httpInvocation(dataString, function, i.announceDoneExe, false)
+	payload, _ := json.Marshal(map[string]string{"body": dataString})
 
-	executionRecordBase.RequestedDuration = uint32(runtimeSpec.Runtime * 1e3)
-	record := &mc.ExecutionRecord{ExecutionRecordBase: *executionRecordBase}
+	input := &lambda.InvokeInput{
+		FunctionName:   aws.String(function.Name),
+		InvocationType: i.invocationType,
+		Payload:        payload,
+	}
+	if i.invocationType == types.InvocationTypeRequestResponse {
+		input.LogType = types.LogTypeTail
+	}
 
-	if !success {
-		return false, record
+	start := time.Now()
+	record := &mc.ExecutionRecord{
+		ExecutionRecordBase: mc.ExecutionRecordBase{
+			Instance:          function.Name,
+			StartTime:         start.UnixMicro(),
+			RequestedDuration: uint32(runtimeSpec.Runtime * 1e3),
+		},
 	}
 
-	// Read the response body
-	responseBody, err := io.ReadAll(res.Body)
+	// retries would be accounted to the response time
+	output, err := i.client.Invoke(context.Background(), input, func(options *lambda.Options) {
+		options.Retryer = aws.NopRetryer{}
+	})
+	record.ResponseTime = time.Since(start).Microseconds()
 	if err != nil {
-		log.Debugf("Error reading response body:%s", err)
+		log.Debugf("AWS Lambda invocation of function %s failed - %v", function.Name, err)
+		record.ConnectionTimeout = true
+
 		return false, record
 	}
 
-	// Create a variable to store the JSON data
-	var httpResBody HTTPResBody
+	lambdaRecord := &mc.ExecutionRecordAWSLambda{
+		InvocationType: string(i.invocationType),
+		StatusCode:     int(output.StatusCode),
+		StartType:      mc.Hot,
+	}
+	lambdaRecord.RequestID, _ = awsmiddleware.GetRequestIDMetadata(output.ResultMetadata)
+	if i.invocationType == types.InvocationTypeEvent {
+		// the completion is matched by the request ID of the destination record
+		record.AsyncResponseID = lambdaRecord.RequestID
+	}
+	if output.LogResult != nil {
+		if report, ok := parseLambdaLogTail(*output.LogResult); ok {
+			lambdaRecord.BilledDuration = int64(report.BilledDurationMs * 1000)
+			lambdaRecord.InitDuration = int64(report.InitDurationMs * 1000)
+			lambdaRecord.MaxMemoryUsed = report.MaxMemoryUsedMB
+			if report.InitDurationMs > 0 {
+				lambdaRecord.StartType = mc.Cold
+				record.ColdStart = true
+			}
+			record.StartTypeReported = true
+		}
+	}
 
-	// Unmarshal the response body into the JSON object
-	if err := json.Unmarshal(responseBody, &httpResBody); err != nil {
-		log.Debugf("Error unmarshaling JSON:%s", err)
-		return false, record
+	success := true
+	if output.FunctionError != nil {
+		log.Debugf("AWS Lambda function %s failed - %s", function.Name, *output.FunctionError)
+		lambdaRecord.FunctionError = *output.FunctionError
+		success = false
+	} else if i.invocationType == types.InvocationTypeRequestResponse {
+		success = setLambdaResponse(record, output.Payload)
+	}
+	if !success {
+		record.FunctionTimeout = true
+	}
+
+	lambdaRecord.ExecutionRecordBase = record.ExecutionRecordBase
+	i.recordsMutex.Lock()
+	i.records = append(i.records, lambdaRecord)
+	i.recordsMutex.Unlock()
+
+	log.Tracef("(Replied)\t %s: %d[ms]", function.Name, record.ActualDuration)
+	log.Tracef("(E2E Latency) %s: %.2f[ms]\n", function.Name, float64(record.ResponseTime)/1e3)
+
+	return success, record
+}
+
+// setLambdaResponse records the execution time and memory usage measured by the function
+func setLambdaResponse(record *mc.ExecutionRecord, payload []byte) bool {
+	var response lambdaProxyResponse
+	if err := json.Unmarshal(payload, &response); err != nil {
+		log.Debugf("Error unmarshaling the response of %s - %v", record.Instance, err)
+		return false
+	}
+	if response.StatusCode != http.StatusOK {
+		log.Debugf("Function %s responded with status code %d", record.Instance, response.StatusCode)
+		return false
+	}
+
+	var httpResBody HTTPResBody
+	if err := json.Unmarshal([]byte(response.Body), &httpResBody); err != nil {
+		log.Debugf("Error unmarshaling the body of the response of %s - %v", record.Instance, err)
+		return false
 	}
 
 	record.ActualDuration = httpResBody.DurationInMicroSec
 	record.ActualMemoryUsage = common.Kib2Mib(httpResBody.MemoryUsageInKb)
 
-	logInvocationSummary(function, &record.ExecutionRecordBase, res)
+	return true
+}
+
+// LambdaDestinationRecord is the record Lambda sends to the destinations of asynchronous invocations
+type LambdaDestinationRecord struct {
+	RequestContext struct {
+		RequestID string `json:"requestId"`
+		// Success, RetriesExhausted or EventAgeExceeded
+		Condition string `json:"condition"`
+	} `json:"requestContext"`
+	ResponsePayload json.RawMessage `json:"responsePayload"`
+}
+
+// ParseLambdaDestinationRecord parses the destination record, sent as is or as the detail of an EventBridge event
+func ParseLambdaDestinationRecord(body []byte) (*LambdaDestinationRecord, error) {
+	var envelope struct {
+		LambdaDestinationRecord
+		Detail *LambdaDestinationRecord `json:"detail"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, err
+	}
+
+	destinationRecord := &envelope.LambdaDestinationRecord
+	if envelope.Detail != nil {
+		destinationRecord = envelope.Detail
+	}
+	if destinationRecord.RequestContext.RequestID == "" {
+		return nil, fmt.Errorf("missing request ID")
+	}
+
+	return destinationRecord, nil
+}
+
+// SetResponse records the execution time and memory usage measured by the function, and returns whether the
+// invocation succeeded
+func (r *LambdaDestinationRecord) SetResponse(record *mc.ExecutionRecord) bool {
+	if r.RequestContext.Condition != "Success" {
+		log.Debugf("Asynchronous invocation %s of %s failed - %s", r.RequestContext.RequestID, record.Instance, r.RequestContext.Condition)
+		return false
+	}
+
+	return setLambdaResponse(record, r.ResponsePayload)
+}
+
+// parseLambdaLogTail extracts the measurements from the base64-encoded tail of the log of an invocation, e.g.,
+// REPORT RequestId: <id>	Duration: 1.51 ms	Billed Duration: 2 ms	Memory Size: 128 MB	Max Memory Used: 35 MB	Init Duration: 120.33 ms
+func parseLambdaLogTail(logResult string) (lambdaReport, bool) {
+	var report lambdaReport
+
+	logTail, err := base64.StdEncoding.DecodeString(logResult)
+	if err != nil {
+		return report, false
+	}
+
+	for _, line := range strings.Split(string(logTail), "\n") {
+		if !strings.HasPrefix(line, "REPORT ") {
+			continue
+		}
+
+		for _, field := range strings.Split(line, "\t") {
+			key, value, found := strings.Cut(strings.TrimSpace(field), ": ")
+			if !found {
+				continue
+			}
+
+			fields := strings.Fields(value)
+			if len(fields) == 0 {
+				continue
+			}
+			number, err := strconv.ParseFloat(fields[0], 64)
+			if err != nil {
+				continue
+			}
+
+			switch key {
+			case "Billed Duration":
+				report.BilledDurationMs = number
+			case "Init Duration":
+				report.InitDurationMs = number
+			case "Max Memory Used":
+				report.MaxMemoryUsedMB = uint32(number)
+			}
+		}
+
+		return report, true
+	}
+
+	return report, false
+}
+
+func (i *awsLambdaInvoker) PlatformRecordsName() string {
+	return "lambda"
+}
+
+func (i *awsLambdaInvoker) WritePlatformRecords(filename string) {
+	i.recordsMutex.Lock()
+	defer i.recordsMutex.Unlock()
 
-	return true, record
+	mc.WriteAWSLambdaInvocationRecords(filename, i.records)
 }
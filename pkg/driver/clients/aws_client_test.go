@@ -0,0 +1,176 @@
+package clients
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gocarina/gocsv"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+const lambdaColdReport = "START RequestId: cold-1 Version: $LATEST\n" +
+	"END RequestId: cold-1\n" +
+	"REPORT RequestId: cold-1\tDuration: 301.51 ms\tBilled Duration: 302 ms\tMemory Size: 1024 MB\tMax Memory Used: 35 MB\tInit Duration: 120.33 ms\t\n"
+
+const lambdaWarmReport = "REPORT RequestId: warm-1\tDuration: 1.51 ms\tBilled Duration: 2 ms\tMemory Size: 128 MB\tMax Memory Used: 30 MB\t"
+
+// createFakeLambdaServer serves the Invoke API of Lambda
+func createFakeLambdaServer(t *testing.T) *httptest.Server {
+	body, _ := json.Marshal(HTTPResBody{DurationInMicroSec: 301000, MemoryUsageInKb: 2048})
+	response, _ := json.Marshal(lambdaProxyResponse{StatusCode: http.StatusOK, Body: string(body)})
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name, found := strings.CutPrefix(r.URL.Path, "/2015-03-31/functions/")
+		name, found = strings.CutSuffix(name, "/invocations")
+		if r.Method != http.MethodPost || !found {
+			w.Header().Set("X-Amzn-ErrorType", "ResourceNotFoundException")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"Type": "User", "message": "Function not found"}`))
+			return
+		}
+
+		var payload map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || !strings.Contains(payload["body"], `"RuntimeInMilliSec": 300`) {
+			t.Errorf("Unexpected payload %v.", payload)
+		}
+
+		w.Header().Set("X-Amzn-RequestId", name+"-1")
+		if r.Header.Get("X-Amz-Invocation-Type") == "Event" {
+			if r.Header.Get("X-Amz-Log-Type") != "" {
+				t.Error("Expected no log tail to be requested for asynchronous invocations.")
+			}
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+
+		if r.Header.Get("X-Amz-Log-Type") != "Tail" {
+			t.Error("Expected the log tail to be requested.")
+		}
+		switch name {
+		case "cold":
+			w.Header().Set("X-Amz-Log-Result", base64.StdEncoding.EncodeToString([]byte(lambdaColdReport)))
+			_, _ = w.Write(response)
+		case "broken":
+			w.Header().Set("X-Amz-Function-Error", "Unhandled")
+			_, _ = w.Write([]byte(`{"errorMessage": "panic"}`))
+		case "warm":
+			w.Header().Set("X-Amz-Log-Result", base64.StdEncoding.EncodeToString([]byte(lambdaWarmReport)))
+			_, _ = w.Write(response)
+		default:
+			_, _ = w.Write(response)
+		}
+	}))
+}
+
+func newTestAWSLambdaConfig(t *testing.T, endpointURL string, invocationType string) *config.AWSLambdaConfig {
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(t.TempDir(), "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "credentials"))
+
+	return &config.AWSLambdaConfig{Region: "eu-west-1", EndpointURL: endpointURL, InvocationType: invocationType}
+}
+
+func TestAWSLambdaInvoker(t *testing.T) {
+	server := createFakeLambdaServer(t)
+	defer server.Close()
+
+	cfg := newTestAWSLambdaConfig(t, server.URL, config.AWSInvocationTypeRequestResponse)
+	client := NewAWSLambdaClient(cfg)
+	if client.Options().Region != "eu-west-1" {
+		t.Errorf("Expected the configured region, got %s.", client.Options().Region)
+	}
+
+	invoker := newAWSLambdaInvoker(client, cfg)
+	specification := &common.RuntimeSpecification{Runtime: 300, Memory: 128}
+
+	success, record := invoker.Invoke(&common.Function{Name: "cold"}, specification)
+	if !success || !record.ColdStart || !record.StartTypeReported || record.ActualDuration != 301000 || record.ActualMemoryUsage != 2 {
+		t.Errorf("Unexpected record of the cold invocation %+v.", record)
+	}
+	if success, record = invoker.Invoke(&common.Function{Name: "warm"}, specification); !success || record.ColdStart || !record.StartTypeReported {
+		t.Errorf("Unexpected record of the warm invocation %+v.", record)
+	}
+	if success, record = invoker.Invoke(&common.Function{Name: "broken"}, specification); success || !record.FunctionTimeout {
+		t.Error("Expected the failed invocation to fail.")
+	}
+
+	filename := filepath.Join(t.TempDir(), "lambda.csv")
+	invoker.WritePlatformRecords(filename)
+
+	file, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var records []mc.ExecutionRecordAWSLambda
+	if err = gocsv.UnmarshalFile(file, &records); err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("Expected 3 invocation records, got %d.", len(records))
+	}
+
+	cold := records[0]
+	if cold.RequestID != "cold-1" || cold.StartType != mc.Cold || cold.BilledDuration != 302000 ||
+		cold.InitDuration != 120330 || cold.MaxMemoryUsed != 35 || cold.InvocationType != "RequestResponse" {
+
+		t.Errorf("Unexpected record of the cold invocation %+v.", cold)
+	}
+	if records[1].StartType != mc.Hot || records[1].InitDuration != 0 {
+		t.Errorf("Unexpected record of the warm invocation %+v.", records[1])
+	}
+	if records[2].FunctionError != "Unhandled" {
+		t.Errorf("Expected the function error to be recorded, got %+v.", records[2])
+	}
+}
+
+func TestAWSLambdaInvokerEvent(t *testing.T) {
+	server := createFakeLambdaServer(t)
+	defer server.Close()
+
+	cfg := newTestAWSLambdaConfig(t, server.URL, config.AWSInvocationTypeEvent)
+	invoker := newAWSLambdaInvoker(NewAWSLambdaClient(cfg), cfg)
+	specification := &common.RuntimeSpecification{Runtime: 300, Memory: 128}
+
+	success, record := invoker.Invoke(&common.Function{Name: "queued"}, specification)
+	if !success || record.ActualDuration != 0 || record.ResponseTime <= 0 {
+		t.Errorf("Unexpected record of the queued invocation %+v.", record)
+	}
+	if record.StartTypeReported {
+		t.Error("Expected no start type to be reported for the queued invocation.")
+	}
+	if record.AsyncResponseID != "queued-1" {
+		t.Errorf("Expected the request ID to identify the completion, got %s.", record.AsyncResponseID)
+	}
+	if invoker.records[0].StatusCode != http.StatusAccepted || invoker.records[0].InvocationType != "Event" {
+		t.Errorf("Unexpected record of the queued invocation %+v.", invoker.records[0])
+	}
+}
+
+func TestParseLambdaLogTail(t *testing.T) {
+	report, ok := parseLambdaLogTail(base64.StdEncoding.EncodeToString([]byte(lambdaColdReport)))
+	if !ok || report.BilledDurationMs != 302 || report.InitDurationMs != 120.33 || report.MaxMemoryUsedMB != 35 {
+		t.Errorf("Unexpected report %+v.", report)
+	}
+
+	if report, ok = parseLambdaLogTail(base64.StdEncoding.EncodeToString([]byte(lambdaWarmReport))); !ok || report.InitDurationMs != 0 || report.BilledDurationMs != 2 {
+		t.Errorf("Unexpected report of the warm invocation %+v.", report)
+	}
+
+	if _, ok = parseLambdaLogTail(base64.StdEncoding.EncodeToString([]byte("START RequestId: x\n"))); ok {
+		t.Error("Expected no report without a REPORT line.")
+	}
+	if _, ok = parseLambdaLogTail("not base64!"); ok {
+		t.Error("Expected no report of an invalid log tail.")
+	}
+}
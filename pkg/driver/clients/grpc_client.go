@@ -26,6 +26,7 @@ package clients
 
 import (
 	"context"
+	protobuf "github.com/golang/protobuf/proto"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"github.com/vhive-serverless/loader/pkg/common"
@@ -34,8 +35,13 @@ import (
 	helloworld "github.com/vhive-serverless/vSwarm/utils/protobuf/helloworld"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	mc "github.com/vhive-serverless/loader/pkg/metric"
@@ -48,14 +54,47 @@ type invoker interface {
 type ExecutorRPC struct {
 }
 
+// names of functions that implement only the original Executor service
+var executorV1Functions sync.Map
+
+// Invoke uses the v2 Executor service and falls back to the original one for functions that do not implement it.
 func (i ExecutorRPC) Invoke(function *common.Function, runtimeSpec *common.RuntimeSpecification, conn *grpc.ClientConn, record *mc.ExecutionRecord, executionCxt context.Context) bool {
+	if _, ok := executorV1Functions.Load(function.Name); !ok {
+		success, err := i.invokeV2(function, runtimeSpec, conn, record, executionCxt)
+		if status.Code(err) != codes.Unimplemented {
+			return success
+		}
+
+		logrus.Debugf("Function %s does not implement the v2 Executor service. Falling back to v1.", function.Name)
+		executorV1Functions.Store(function.Name, struct{}{})
+	}
+
+	return i.invokeV1(function, runtimeSpec, conn, record, executionCxt)
+}
+
+func requestMessage(runtimeSpec *common.RuntimeSpecification) string {
+	if runtimeSpec.RequestPayloadBytes > 0 {
+		return string(randomPayload(runtimeSpec.RequestPayloadBytes))
+	}
+
+	return "nothing"
+}
+
+func (i ExecutorRPC) invokeV1(function *common.Function, runtimeSpec *common.RuntimeSpecification, conn *grpc.ClientConn, record *mc.ExecutionRecord, executionCxt context.Context) bool {
 	grpcClient := proto.NewExecutorClient(conn)
 
-	response, err := grpcClient.Execute(executionCxt, &proto.FaasRequest{
-		Message:           "nothing",
+	if runtimeSpec.ResponsePayloadBytes > 0 {
+		executionCxt = metadata.AppendToOutgoingContext(executionCxt, common.ResponsePayloadKey, strconv.Itoa(runtimeSpec.ResponsePayloadBytes))
+	}
+
+	request := &proto.FaasRequest{
+		Message:           requestMessage(runtimeSpec),
 		RuntimeInMilliSec: uint32(runtimeSpec.Runtime),
 		MemoryInMebiBytes: uint32(runtimeSpec.Memory),
-	})
+	}
+	record.BytesSent = int64(protobuf.Size(request))
+
+	response, err := grpcClient.Execute(executionCxt, request)
 
 	if err != nil {
 		logrus.Debugf("gRPC timeout exceeded for function %s - %s", function.Name, err)
@@ -66,7 +105,8 @@ func (i ExecutorRPC) Invoke(function *common.Function, runtimeSpec *common.Runti
 		return false
 	}
 
-	record.Instance = extractInstanceName(response.GetMessage())
+	record.BytesReceived = int64(protobuf.Size(response))
+	record.Instance = extractInstanceName(trimResponsePayload(response.GetMessage(), runtimeSpec.ResponsePayloadBytes))
 	record.ActualDuration = response.DurationInMicroSec
 
 	if strings.HasPrefix(response.GetMessage(), "FAILURE - mem_alloc") {
@@ -81,6 +121,65 @@ func (i ExecutorRPC) Invoke(function *common.Function, runtimeSpec *common.Runti
 	return true
 }
 
+func (i ExecutorRPC) invokeV2(function *common.Function, runtimeSpec *common.RuntimeSpecification, conn *grpc.ClientConn, record *mc.ExecutionRecord, executionCxt context.Context) (bool, error) {
+	grpcClient := proto.NewExecutorV2Client(conn)
+
+	request := &proto.FaasRequestV2{
+		Message:              requestMessage(runtimeSpec),
+		RuntimeInMilliSec:    uint32(runtimeSpec.Runtime),
+		MemoryInMebiBytes:    uint32(runtimeSpec.Memory),
+		InvocationID:         uuid.New().String(),
+		RequestPayloadBytes:  uint32(runtimeSpec.RequestPayloadBytes),
+		ResponsePayloadBytes: uint32(runtimeSpec.ResponsePayloadBytes),
+	}
+	if function.DirigentMetadata != nil {
+		request.IoPercentage = uint32(function.DirigentMetadata.IOPercentage)
+	}
+	record.RequestID = request.InvocationID
+	record.BytesSent = int64(protobuf.Size(request))
+
+	response, err := grpcClient.Execute(executionCxt, request)
+
+	if err != nil {
+		if status.Code(err) == codes.Unimplemented {
+			// the invocation is retried through v1, which has no invocation ID
+			record.RequestID = ""
+			return false, err
+		}
+
+		logrus.Debugf("gRPC timeout exceeded for function %s - %s", function.Name, err)
+
+		record.ConnectionTimeout = true // WithBlock deprecated in new gRPC interface
+		record.FunctionTimeout = true
+
+		return false, err
+	}
+
+	record.BytesReceived = int64(protobuf.Size(response))
+	record.ActualDuration = response.DurationInMicroSec
+	record.ColdStart = response.ColdStart
+	record.StartTypeReported = true
+	record.ContainerStartTime = response.ContainerStartTimeUnixMicro
+	record.CPUTime = int64(response.CpuTimeInMicroSec)
+	record.PeakMemoryUsage = common.Kib2Mib(uint32(response.PeakRssInKb))
+
+	record.Instance = response.InstanceID
+	if record.Instance == "" {
+		record.Instance = extractInstanceName(trimResponsePayload(response.GetMessage(), runtimeSpec.ResponsePayloadBytes))
+	}
+
+	if strings.HasPrefix(response.GetMessage(), "FAILURE - mem_alloc") {
+		record.MemoryAllocationTimeout = true
+	} else {
+		record.ActualMemoryUsage = common.Kib2Mib(response.MemoryUsageInKb)
+	}
+
+	logrus.Tracef("(Replied)\t %s: %s, %.2f[ms], %d[MiB], %.2f[ms CPU], cold start: %t", function.Name, response.InvocationID,
+		float64(response.DurationInMicroSec)/1e3, common.Kib2Mib(response.MemoryUsageInKb), float64(response.CpuTimeInMicroSec)/1e3, response.ColdStart)
+
+	return true, nil
+}
+
 type SayHelloRPC struct {
 }
 
@@ -166,6 +265,14 @@ func (i *grpcInvoker) Invoke(function *common.Function, runtimeSpec *common.Runt
 	return success, record
 }
 
+func trimResponsePayload(data string, payloadBytes int) string {
+	if payloadBytes <= 0 || payloadBytes > len(data) {
+		return data
+	}
+
+	return data[:len(data)-payloadBytes]
+}
+
 func extractInstanceName(data string) string {
 	indexOfHyphen := strings.LastIndex(data, common.FunctionNamePrefix)
 	if indexOfHyphen == -1 {
@@ -25,7 +25,9 @@
 package clients
 
 import (
+	"context"
 	"fmt"
+	"net"
 	"os"
 	"testing"
 	"time"
@@ -33,8 +35,10 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/vhive-serverless/loader/pkg/common"
 	"github.com/vhive-serverless/loader/pkg/config"
+	"github.com/vhive-serverless/loader/pkg/workload/proto"
 	"github.com/vhive-serverless/loader/pkg/workload/standard"
 	"github.com/vhive-serverless/loader/pkg/workload/vswarm"
+	"google.golang.org/grpc"
 )
 
 func createFakeLoaderConfiguration() *config.LoaderConfiguration {
@@ -72,7 +76,7 @@ func TestGRPCClientWithServerUnreachable(t *testing.T) {
 	cfg := createFakeLoaderConfiguration()
 	cfg.EnableZipkinTracing = true
 
-	invoker := CreateInvoker(&config.Configuration{LoaderConfiguration: cfg}, nil, nil)
+	invoker := CreateInvoker(&config.Configuration{LoaderConfiguration: cfg})
 	success, record := invoker.Invoke(&testFunction, &testRuntimeSpecs)
 
 	if record.Instance != "" ||
@@ -89,7 +93,7 @@ func TestGRPCClientWithServerUnreachable(t *testing.T) {
 func TestVSwarmClientUnreachable(t *testing.T) {
 	cfgSwarm := createFakeVSwarmLoaderConfiguration()
 
-	vSwarmInvoker := CreateInvoker(&config.Configuration{LoaderConfiguration: cfgSwarm}, nil, nil)
+	vSwarmInvoker := CreateInvoker(&config.Configuration{LoaderConfiguration: cfgSwarm})
 	success, record := vSwarmInvoker.Invoke(&testFunction, &testRuntimeSpecs)
 
 	if record.Instance != "" ||
@@ -113,7 +117,7 @@ func TestGRPCClientWithServerReachable(t *testing.T) {
 	time.Sleep(2 * time.Second)
 
 	cfg := createFakeLoaderConfiguration()
-	invoker := CreateInvoker(&config.Configuration{LoaderConfiguration: cfg}, nil, nil)
+	invoker := CreateInvoker(&config.Configuration{LoaderConfiguration: cfg})
 
 	start := time.Now()
 	success, record := invoker.Invoke(&testFunction, &testRuntimeSpecs)
@@ -125,10 +129,68 @@ func TestGRPCClientWithServerReachable(t *testing.T) {
 		record.FunctionTimeout != false ||
 		record.ResponseTime == 0 ||
 		record.ActualDuration == 0 ||
-		record.ActualMemoryUsage == 0 {
+		record.ActualMemoryUsage == 0 ||
+		record.RequestID == "" ||
+		record.Instance == "" ||
+		record.ContainerStartTime == 0 ||
+		record.PeakMemoryUsage == 0 ||
+		!record.ColdStart {
 
 		t.Error("Failed gRPC invocations for trace function.")
 	}
+
+	success, record = invoker.Invoke(&testFunction, &testRuntimeSpecs)
+	if !success || record.ColdStart {
+		t.Error("Second invocation of the function should be warm.")
+	}
+}
+
+type executorV1Server struct {
+	proto.UnimplementedExecutorServer
+}
+
+func (s *executorV1Server) Execute(_ context.Context, req *proto.FaasRequest) (*proto.FaasReply, error) {
+	return &proto.FaasReply{
+		Message:            "v1 - " + common.FunctionNamePrefix + "0",
+		DurationInMicroSec: req.RuntimeInMilliSec * 1000,
+		MemoryUsageInKb:    req.MemoryInMebiBytes * 1024,
+	}, nil
+}
+
+func TestGRPCClientExecutorV1Fallback(t *testing.T) {
+	address, port := "localhost", 18083
+
+	lis, err := net.Listen("tcp", fmt.Sprintf("%s:%d", address, port))
+	if err != nil {
+		t.Fatalf("Failed to listen - %v", err)
+	}
+	server := grpc.NewServer()
+	proto.RegisterExecutorServer(server, &executorV1Server{})
+	go server.Serve(lis)
+	defer server.Stop()
+
+	function := &common.Function{
+		Name:     "test-function-v1",
+		Endpoint: fmt.Sprintf("%s:%d", address, port),
+	}
+
+	invoker := CreateInvoker(&config.Configuration{LoaderConfiguration: createFakeLoaderConfiguration()})
+	for i := 0; i < 2; i++ {
+		success, record := invoker.Invoke(function, &testRuntimeSpecs)
+
+		if !success ||
+			record.FunctionTimeout ||
+			record.ActualDuration != uint32(testRuntimeSpecs.Runtime*1000) ||
+			record.Instance != common.FunctionNamePrefix+"0" ||
+			record.RequestID != "" {
+
+			t.Errorf("Failed gRPC invocation of a function implementing only the v1 Executor service: %+v", record)
+		}
+	}
+
+	if _, ok := executorV1Functions.Load(function.Name); !ok {
+		t.Error("Function should have been marked as implementing only the v1 Executor service.")
+	}
 }
 
 func TestVSwarmClientWithServerReachable(t *testing.T) {
@@ -139,7 +201,7 @@ func TestVSwarmClientWithServerReachable(t *testing.T) {
 	time.Sleep(2 * time.Second)
 
 	cfgSwarm := createFakeVSwarmLoaderConfiguration()
-	vSwarmInvoker := CreateInvoker(&config.Configuration{LoaderConfiguration: cfgSwarm}, nil, nil)
+	vSwarmInvoker := CreateInvoker(&config.Configuration{LoaderConfiguration: cfgSwarm})
 
 	start := time.Now()
 	success, record := vSwarmInvoker.Invoke(&testFunction, &testRuntimeSpecs)
@@ -171,7 +233,7 @@ func TestGRPCClientWithServerBatchWorkload(t *testing.T) {
 
 	cfg := createFakeLoaderConfiguration()
 
-	invoker := CreateInvoker(&config.Configuration{LoaderConfiguration: cfg}, nil, nil)
+	invoker := CreateInvoker(&config.Configuration{LoaderConfiguration: cfg})
 
 	for i := 0; i < 50; i++ {
 		success, record := invoker.Invoke(&testFunction, &testRuntimeSpecs)
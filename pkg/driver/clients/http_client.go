@@ -2,7 +2,6 @@ package clients
 
 import (
 	"bytes"
-	"crypto/rand"
 	"encoding/json"
 	"fmt"
 	log "github.com/sirupsen/logrus"
@@ -23,6 +22,8 @@ type FunctionResponse struct {
 	Function      string `json:"Function"`
 	MachineName   string `json:"MachineName"`
 	ExecutionTime int64  `json:"ExecutionTime"`
+	// optional, reported by the functions that know whether they served the request from a fresh instance
+	ColdStart *bool `json:"ColdStart,omitempty"`
 }
 
 type httpInvoker struct {
@@ -33,13 +34,19 @@ type httpInvoker struct {
 	isKnative   bool
 	isDandelion bool
 	isWorkflow  bool
+
+	templates *requestTemplates
 }
 
 func newHTTPInvoker(cfg *config.Configuration) *httpInvoker {
 	lcfg := cfg.LoaderConfiguration
 	dcfg := cfg.DirigentConfiguration
+	if dcfg == nil {
+		// platforms other than Dirigent have no Dirigent-specific invocation settings
+		dcfg = &config.DirigentConfig{}
+	}
 
-	return &httpInvoker{
+	invoker := &httpInvoker{
 		client:      CreateHTTPClient(lcfg.GRPCFunctionTimeoutSeconds, lcfg.InvokeProtocol),
 		loaderCfg:   lcfg,
 		dirigentCfg: dcfg,
@@ -48,23 +55,26 @@ func newHTTPInvoker(cfg *config.Configuration) *httpInvoker {
 		isDandelion: strings.Contains(strings.ToLower(dcfg.Backend), common.BackendDandelion),
 		isWorkflow:  dcfg.Workflow,
 	}
+
+	if cfg.RequestTemplates != nil {
+		templates, err := newRequestTemplates(cfg.RequestTemplates)
+		if err != nil {
+			log.Fatalf("Failed to parse request templates - %v", err)
+		}
+
+		invoker.templates = templates
+	}
+
+	return invoker
 }
 
 var payload []byte = nil
 var contentType string = "application/octet-stream"
 
 func CreateRandomPayload(sizeInMB float64) *bytes.Buffer {
-	if payload == nil {
-		byteCount := int(sizeInMB * 1024.0 * 1024.0) // MB -> B
-		payload = make([]byte, byteCount)
-
-		n, err := rand.Read(payload)
-		if err != nil || n != byteCount {
-			log.Errorf("Failed to generate random %d bytes.", byteCount)
-		}
-	}
+	byteCount := int(sizeInMB * 1024.0 * 1024.0) // MB -> B
 
-	return bytes.NewBuffer(payload)
+	return bytes.NewBuffer(randomPayload(byteCount))
 }
 
 func CreateFilePayload(filePath string) *bytes.Buffer {
@@ -97,11 +107,21 @@ func CreateFilePayload(filePath string) *bytes.Buffer {
 }
 
 func (i *httpInvoker) functionInvocationRequest(function *common.Function, runtimeSpec *common.RuntimeSpecification) *http.Request {
+	if i.templates != nil {
+		if t := i.templates.lookup(function); t != nil {
+			return i.templatedInvocationRequest(t, function, runtimeSpec)
+		}
+	}
+
 	requestBody := &bytes.Buffer{}
 	if body := composeBusyLoopBody(function.Name, function.DirigentMetadata.Image, runtimeSpec.Runtime, function.DirigentMetadata.IterationMultiplier); i.isDandelion && body != nil {
 		requestBody = body
 	}
 
+	if !i.isDandelion && runtimeSpec.RequestPayloadBytes > 0 {
+		requestBody = bytes.NewBuffer(randomPayload(runtimeSpec.RequestPayloadBytes))
+	}
+
 	// gpu rps requests
 	if i.dirigentCfg.RpsRequestedGpu > 0 {
 		ts := time.Now()
@@ -131,6 +151,9 @@ func (i *httpInvoker) functionInvocationRequest(function *common.Function, runti
 	req.Header.Set("requested_memory", strconv.Itoa(runtimeSpec.Memory))
 	req.Header.Set("multiplier", strconv.Itoa(function.DirigentMetadata.IterationMultiplier))
 	req.Header.Set("io_percentage", strconv.Itoa(function.DirigentMetadata.IOPercentage))
+	if runtimeSpec.ResponsePayloadBytes > 0 {
+		req.Header.Set(common.ResponsePayloadKey, strconv.Itoa(runtimeSpec.ResponsePayloadBytes))
+	}
 	if i.dirigentCfg.RpsRequestedGpu > 0 {
 		req.Header.Add("Content-Type", contentType)
 	}
@@ -142,6 +165,21 @@ func (i *httpInvoker) functionInvocationRequest(function *common.Function, runti
 	return req
 }
 
+func (i *httpInvoker) templatedInvocationRequest(t *requestTemplate, function *common.Function, runtimeSpec *common.RuntimeSpecification) *http.Request {
+	host := ""
+	if !i.isKnative {
+		host = function.Name
+	}
+
+	req, err := t.newRequest(function, runtimeSpec, host)
+	if err != nil {
+		log.Errorf("Failed to create a HTTP request from template - %v\n", err)
+		return nil
+	}
+
+	return req
+}
+
 func (i *httpInvoker) workflowInvocationRequest(wf *common.Function) *http.Request {
 	if wf.WorkflowMetadata == nil {
 		log.Fatal("Failed to create workflow invocation request: workflow metadata is nil")
@@ -200,9 +238,13 @@ func (i *httpInvoker) Invoke(function *common.Function, runtimeSpec *common.Runt
 	}
 
 	record.GRPCConnectionEstablishTime = time.Since(start).Microseconds()
+	if req.ContentLength > 0 {
+		record.BytesSent = req.ContentLength
+	}
 
 	defer HandleBodyClosing(resp)
 	body, err := io.ReadAll(resp.Body)
+	record.BytesReceived = int64(len(body))
 
 	if err != nil || resp.StatusCode != http.StatusOK || len(body) == 0 {
 		if err != nil {
@@ -256,6 +298,10 @@ func DeserializeDirigentResponse(body []byte, record *mc.ExecutionRecord) error
 
 	record.Instance = deserializedResponse.Function
 	record.ActualDuration = uint32(deserializedResponse.ExecutionTime)
+	if deserializedResponse.ColdStart != nil {
+		record.ColdStart = *deserializedResponse.ColdStart
+		record.StartTypeReported = true
+	}
 
 	return nil
 }
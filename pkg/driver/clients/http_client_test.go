@@ -0,0 +1,49 @@
+package clients
+
+import (
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+func TestDeserializeDirigentResponse(t *testing.T) {
+	record := &mc.ExecutionRecord{}
+	if err := DeserializeDirigentResponse([]byte(`{"Function": "f", "ExecutionTime": 1500}`), record); err != nil {
+		t.Fatal(err)
+	}
+	if record.Instance != "f" || record.ActualDuration != 1500 || record.StartTypeReported {
+		t.Errorf("Unexpected record of a response without the start type %+v.", record)
+	}
+
+	record = &mc.ExecutionRecord{}
+	if err := DeserializeDirigentResponse([]byte(`{"Function": "f", "ExecutionTime": 1500, "ColdStart": true}`), record); err != nil {
+		t.Fatal(err)
+	}
+	if !record.ColdStart || !record.StartTypeReported {
+		t.Errorf("Expected the reported cold start to be recorded, got %+v.", record)
+	}
+
+	if err := DeserializeDirigentResponse([]byte("not json"), &mc.ExecutionRecord{}); err == nil {
+		t.Error("Expected an invalid response to fail.")
+	}
+}
+
+func TestCreateProbeInvokerDirigentAsync(t *testing.T) {
+	cfg := &config.Configuration{
+		LoaderConfiguration:   &config.LoaderConfiguration{Platform: common.PlatformDirigent, InvokeProtocol: "http1"},
+		DirigentConfiguration: &config.DirigentConfig{AsyncMode: true},
+	}
+
+	invoker, ok := CreateProbeInvoker(cfg).(*httpInvoker)
+	if !ok {
+		t.Fatal("Expected an HTTP invoker.")
+	}
+	if invoker.dirigentCfg.AsyncMode {
+		t.Error("Expected the readiness probes to invoke synchronously.")
+	}
+	if !cfg.DirigentConfiguration.AsyncMode {
+		t.Error("Expected the configuration of the experiment to remain asynchronous.")
+	}
+}
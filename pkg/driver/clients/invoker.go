@@ -2,7 +2,6 @@ package clients
 
 import (
 	"strings"
-	"sync"
 
 	"github.com/sirupsen/logrus"
 	"github.com/vhive-serverless/loader/pkg/common"
@@ -14,10 +13,18 @@ type Invoker interface {
 	Invoke(*common.Function, *common.RuntimeSpecification) (bool, *metric.ExecutionRecord)
 }
 
-func CreateInvoker(cfg *config.Configuration, announceDoneExe *sync.WaitGroup, readOpenWhiskMetadata *sync.Mutex) Invoker {
+// PlatformRecorder is implemented by the invokers collecting the records the platform keeps of the invocations, which
+// are completed and written once the experiment is over
+type PlatformRecorder interface {
+	// PlatformRecordsName is the name of the output file of the records
+	PlatformRecordsName() string
+	WritePlatformRecords(filename string)
+}
+
+func CreateInvoker(cfg *config.Configuration) Invoker {
 	switch strings.ToLower(cfg.LoaderConfiguration.Platform) {
 	case common.PlatformAWSLambda:
-		return newAWSLambdaInvoker(announceDoneExe)
+		return newAWSLambdaInvoker(NewAWSLambdaClient(cfg.LoaderConfiguration.AWSLambda), cfg.LoaderConfiguration.AWSLambda)
 	case common.PlatformDirigent:
 		if cfg.DirigentConfiguration == nil {
 			logrus.Fatal("Failed to create invoker: dirigent configuration is required for platform 'dirigent'")
@@ -38,10 +45,32 @@ func CreateInvoker(cfg *config.Configuration, announceDoneExe *sync.WaitGroup, r
 			return newHTTPInvoker(cfg)
 		}
 	case common.PlatformOpenWhisk:
-		return newOpenWhiskInvoker(announceDoneExe, readOpenWhiskMetadata)
+		return newOpenWhiskInvoker(NewOpenWhiskClient())
 	default:
 		logrus.Fatal("Unsupported platform.")
 	}
 
 	return nil
 }
+
+// CreateProbeInvoker creates the invoker of the readiness probes, which is separate from the one of the experiment so
+// that the platform records do not include the probes. It invokes synchronously even in the asynchronous modes, so that
+// a probe only succeeds once the function has executed rather than once the invocation has been accepted.
+func CreateProbeInvoker(cfg *config.Configuration) Invoker {
+	probeCfg := *cfg
+	loaderCfg := *cfg.LoaderConfiguration
+	probeCfg.LoaderConfiguration = &loaderCfg
+
+	if loaderCfg.AWSLambda != nil {
+		awsCfg := *loaderCfg.AWSLambda
+		awsCfg.InvocationType = config.AWSInvocationTypeRequestResponse
+		loaderCfg.AWSLambda = &awsCfg
+	}
+	if cfg.DirigentConfiguration != nil {
+		dirigentCfg := *cfg.DirigentConfiguration
+		dirigentCfg.AsyncMode = false
+		probeCfg.DirigentConfiguration = &dirigentCfg
+	}
+
+	return CreateInvoker(&probeCfg)
+}
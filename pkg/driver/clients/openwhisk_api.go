@@ -0,0 +1,281 @@
+package clients
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	openWhiskDefaultNamespace = "_"
+	// maximum number of activations OpenWhisk returns per list request
+	openWhiskActivationPageSize = 200
+)
+
+// OpenWhiskClient calls the REST API of OpenWhisk with the credentials of the wsk CLI
+type OpenWhiskClient struct {
+	apiHost   string
+	namespace string
+	auth      string
+	client    *http.Client
+}
+
+// OpenWhiskAnnotation is a key-value pair attached to actions and activations
+type OpenWhiskAnnotation struct {
+	Key   string      `json:"key"`
+	Value interface{} `json:"value"`
+}
+
+// OpenWhiskActivation is the record OpenWhisk keeps of an invocation, with times in milliseconds
+type OpenWhiskActivation struct {
+	ActivationID string `json:"activationId"`
+	Name         string `json:"name"`
+	Start        int64  `json:"start"`
+	End          int64  `json:"end"`
+	Duration     int64  `json:"duration"`
+	Response     struct {
+		Status  string          `json:"status"`
+		Success bool            `json:"success"`
+		Result  json.RawMessage `json:"result"`
+	} `json:"response"`
+	Annotations []OpenWhiskAnnotation `json:"annotations"`
+}
+
+// NewOpenWhiskClient reads the API host, the credentials and the namespace from the properties of the wsk CLI, i.e.,
+// $WSK_CONFIG_FILE or ~/.wskprops. As with 'wsk -i', the certificate of the API host is not verified.
+func NewOpenWhiskClient() *OpenWhiskClient {
+	path := os.Getenv("WSK_CONFIG_FILE")
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			log.Fatalf("Unable to locate the OpenWhisk properties - %v", err)
+		}
+		path = filepath.Join(home, ".wskprops")
+	}
+
+	properties, err := readWskProperties(path)
+	if err != nil {
+		log.Fatalf("Unable to read the OpenWhisk properties - %v", err)
+	}
+	if properties["APIHOST"] == "" || properties["AUTH"] == "" {
+		log.Fatalf("APIHOST and AUTH must be set in %s.", path)
+	}
+
+	return newOpenWhiskClient(properties["APIHOST"], properties["NAMESPACE"], properties["AUTH"])
+}
+
+func newOpenWhiskClient(apiHost string, namespace string, auth string) *OpenWhiskClient {
+	if !strings.HasPrefix(apiHost, "http://") && !strings.HasPrefix(apiHost, "https://") {
+		apiHost = "https://" + apiHost
+	}
+	if namespace == "" {
+		namespace = openWhiskDefaultNamespace
+	}
+
+	return &OpenWhiskClient{
+		apiHost:   strings.TrimSuffix(apiHost, "/"),
+		namespace: namespace,
+		auth:      auth,
+		client: &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig:     &tls.Config{InsecureSkipVerify: true},
+				MaxIdleConnsPerHost: 100,
+			},
+		},
+	}
+}
+
+// readWskProperties parses the KEY=VALUE lines of the wsk CLI properties
+func readWskProperties(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	properties := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if key, value, found := strings.Cut(strings.TrimSpace(scanner.Text()), "="); found {
+			properties[key] = value
+		}
+	}
+
+	return properties, scanner.Err()
+}
+
+// ActionURL returns the URL of the action in the REST API
+func (c *OpenWhiskClient) ActionURL(name string) string {
+	return fmt.Sprintf("%s/api/v1/namespaces/%s/actions/%s", c.apiHost, url.PathEscape(c.namespace), url.PathEscape(name))
+}
+
+func (c *OpenWhiskClient) request(method string, requestURL string, body interface{}) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, requestURL, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if user, password, found := strings.Cut(c.auth, ":"); found {
+		req.SetBasicAuth(user, password)
+	}
+
+	return c.client.Do(req)
+}
+
+func readOpenWhiskError(resp *http.Response) error {
+	body, _ := io.ReadAll(resp.Body)
+	return fmt.Errorf("status code %d - %s", resp.StatusCode, strings.TrimSpace(string(body)))
+}
+
+// CreateAction creates the action from its source code, or updates it if it already exists. The action is exported as
+// a web action, as 'wsk action create --web true' would.
+func (c *OpenWhiskClient) CreateAction(name string, kind string, code string) error {
+	action := map[string]interface{}{
+		"exec": map[string]string{"kind": kind, "code": code},
+		"annotations": []OpenWhiskAnnotation{
+			{Key: "web-export", Value: true},
+			{Key: "raw-http", Value: false},
+			{Key: "final", Value: true},
+		},
+	}
+
+	resp, err := c.request(http.MethodPut, c.ActionURL(name)+"?overwrite=true", action)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return readOpenWhiskError(resp)
+	}
+
+	return nil
+}
+
+// DeleteAction deletes the action, which is not an error if it does not exist
+func (c *OpenWhiskClient) DeleteAction(name string) error {
+	resp, err := c.request(http.MethodDelete, c.ActionURL(name), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return readOpenWhiskError(resp)
+	}
+
+	return nil
+}
+
+// InvokeAction invokes the action and waits for the activation to complete. OpenWhisk returns the activation record
+// with status code 200 if the action succeeded and 502 if it failed, or only the activation ID with status code 202 if
+// the action has not completed within the blocking timeout of the platform.
+func (c *OpenWhiskClient) InvokeAction(name string, parameters map[string]string) (*OpenWhiskActivation, int, error) {
+	resp, err := c.request(http.MethodPost, c.ActionURL(name)+"?blocking=true", parameters)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusAccepted, http.StatusBadGateway:
+		activation := &OpenWhiskActivation{}
+		if err = json.NewDecoder(resp.Body).Decode(activation); err != nil {
+			return nil, resp.StatusCode, fmt.Errorf("invalid activation record - %v", err)
+		}
+
+		return activation, resp.StatusCode, nil
+	default:
+		return nil, resp.StatusCode, readOpenWhiskError(resp)
+	}
+}
+
+// ListActivations returns the records of the activations started since the given Unix time in milliseconds, the most
+// recent first
+func (c *OpenWhiskClient) ListActivations(since int64, skip int, limit int) ([]OpenWhiskActivation, error) {
+	query := url.Values{
+		"docs":  {"true"},
+		"since": {fmt.Sprint(since)},
+		"skip":  {fmt.Sprint(skip)},
+		"limit": {fmt.Sprint(limit)},
+	}
+	requestURL := fmt.Sprintf("%s/api/v1/namespaces/%s/activations?%s", c.apiHost, url.PathEscape(c.namespace), query.Encode())
+
+	resp, err := c.request(http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, readOpenWhiskError(resp)
+	}
+
+	var activations []OpenWhiskActivation
+	if err = json.NewDecoder(resp.Body).Decode(&activations); err != nil {
+		return nil, fmt.Errorf("invalid activation records - %v", err)
+	}
+
+	return activations, nil
+}
+
+// FetchActivations returns the records of the given activations started since the given time, fetched in pages
+// instead of one request per activation
+func (c *OpenWhiskClient) FetchActivations(activationIDs []string, since time.Time) (map[string]*OpenWhiskActivation, error) {
+	wanted := make(map[string]bool, len(activationIDs))
+	for _, id := range activationIDs {
+		wanted[id] = true
+	}
+
+	result := make(map[string]*OpenWhiskActivation)
+	for skip := 0; len(result) < len(wanted); skip += openWhiskActivationPageSize {
+		page, err := c.ListActivations(since.UnixMilli(), skip, openWhiskActivationPageSize)
+		if err != nil {
+			return result, err
+		}
+
+		for i := range page {
+			if wanted[page[i].ActivationID] {
+				result[page[i].ActivationID] = &page[i]
+			}
+		}
+
+		if len(page) < openWhiskActivationPageSize {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// annotation returns the numeric value of the annotation of the activation
+func (a *OpenWhiskActivation) annotation(key string) (int64, bool) {
+	for _, annotation := range a.Annotations {
+		if annotation.Key == key {
+			value, ok := annotation.Value.(float64)
+			return int64(value), ok
+		}
+	}
+
+	return 0, false
+}
@@ -1,15 +1,9 @@
 package clients
 
 import (
-	"bytes"
-	"crypto/tls"
-	"encoding/base64"
 	"encoding/json"
-	"fmt"
-	"io"
 	"net/http"
-	"os/exec"
-	"strings"
+	"strconv"
 	"sync"
 	"time"
 
@@ -25,184 +19,158 @@ type ActivationMetadata struct {
 	InitTime  int64 //ms
 }
 
-type HTTPResBody struct {
-	DurationInMicroSec uint32 `json:"DurationInMicroSec"`
-	MemoryUsageInKb    uint32 `json:"MemoryUsageInKb"`
-}
-
 type openWhiskInvoker struct {
-	announceDoneExe       *sync.WaitGroup
-	readOpenWhiskMetadata *sync.Mutex
+	client *OpenWhiskClient
+	// beginning of the experiment, from which the activations still running at the end of the blocking invocation are
+	// searched for
+	since time.Time
+
+	activationsMutex sync.Mutex
+	activations      []*mc.ExecutionRecordOpenWhisk
+	// activations whose metadata is fetched after the experiment, keyed by activation ID
+	pending map[string]*mc.ExecutionRecordOpenWhisk
 }
 
-func newOpenWhiskInvoker(announceDoneExe *sync.WaitGroup, readOpenWhiskMetadata *sync.Mutex) *openWhiskInvoker {
+func newOpenWhiskInvoker(client *OpenWhiskClient) *openWhiskInvoker {
 	return &openWhiskInvoker{
-		announceDoneExe:       announceDoneExe,
-		readOpenWhiskMetadata: readOpenWhiskMetadata,
+		client:  client,
+		since:   time.Now(),
+		pending: make(map[string]*mc.ExecutionRecordOpenWhisk),
 	}
 }
 
 func (i *openWhiskInvoker) Invoke(function *common.Function, runtimeSpec *common.RuntimeSpecification) (bool, *mc.ExecutionRecord) {
 	log.Tracef("(Invoke)\t %s: %d[ms], %d[MiB]", function.Name, runtimeSpec.Runtime, runtimeSpec.Memory)
 
-	qs := fmt.Sprintf("cpu=%d", runtimeSpec.Runtime)
+	start := time.Now()
+	record := &mc.ExecutionRecord{
+		ExecutionRecordBase: mc.ExecutionRecordBase{
+			Instance:          function.Name,
+			StartTime:         start.UnixMicro(),
+			RequestedDuration: uint32(runtimeSpec.Runtime * 1e3),
+		},
+	}
 
-	success, executionRecordBase, res := httpInvocation(qs, function, i.announceDoneExe, true)
-	i.announceDoneExe.Wait() // To postpone querying OpenWhisk during the experiment for performance reasons (Issue 329: https://github.com/vhive-serverless/invitro/issues/329)
+	activation, statusCode, err := i.client.InvokeAction(function.Name, map[string]string{"cpu": strconv.Itoa(runtimeSpec.Runtime)})
+	record.ResponseTime = time.Since(start).Microseconds()
+	if err != nil {
+		log.Debugf("OpenWhisk invocation of function %s failed - %v", function.Name, err)
+		record.ConnectionTimeout = true
 
-	executionRecordBase.RequestedDuration = uint32(runtimeSpec.Runtime * 1e3)
-	record := &mc.ExecutionRecord{ExecutionRecordBase: *executionRecordBase}
-	if !success {
 		return false, record
 	}
 
-	activationID := res.Header.Get("X-Openwhisk-Activation-Id")
-	i.readOpenWhiskMetadata.Lock()
-
-	//read data from OpenWhisk based on the activation ID
-	cmd := exec.Command("wsk", "-i", "activation", "get", activationID)
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	err := cmd.Run()
-	if err != nil {
-		log.Debugf("error reading activation information from OpenWhisk %s - %s", function.Name, err)
-		i.readOpenWhiskMetadata.Unlock()
-		return false, record
+	activationRecord := &mc.ExecutionRecordOpenWhisk{
+		ExecutionRecordBase: record.ExecutionRecordBase,
+		ActivationID:        activation.ActivationID,
+		HttpStatusCode:      statusCode,
 	}
+	i.activationsMutex.Lock()
+	i.activations = append(i.activations, activationRecord)
+	if statusCode == http.StatusAccepted {
+		i.pending[activation.ActivationID] = activationRecord
+	}
+	i.activationsMutex.Unlock()
+
+	switch statusCode {
+	case http.StatusAccepted:
+		log.Debugf("Activation %s of function %s is still running, its metadata will be fetched after the experiment.", activation.ActivationID, function.Name)
+		return true, record
+	case http.StatusBadGateway:
+		log.Debugf("Activation %s of function %s failed - %s", activation.ActivationID, function.Name, activation.Response.Status)
+		record.FunctionTimeout = true
+		activationRecord.FunctionTimeout = true
+		setActivationMetadata(activationRecord, activation)
 
-	i.readOpenWhiskMetadata.Unlock()
-	err, activationMetadata := parseActivationMetadata(out.String())
-	if err != nil {
-		log.Debugf("error parsing activation metadata %s - %s", function.Name, err)
 		return false, record
 	}
 
-	record.ActualDuration = activationMetadata.Duration * 1000 //ms to micro sec
-	/*record.StartType = activationMetadata.StartType
-	record.InitTime = activationMetadata.InitTime * 1000 //ms to micro sec
-	record.WaitTime = activationMetadata.WaitTime * 1000 //ms to micro sec*/
+	setActivationMetadata(activationRecord, activation)
+	record.ActualDuration = activationRecord.ActualDuration
+	record.ColdStart = activationRecord.StartType == mc.Cold
+	record.StartTypeReported = true
+	if executionTime, ok := activationExecutionTime(activation); ok {
+		record.ActualDuration = executionTime
+	}
 
-	logInvocationSummary(function, &record.ExecutionRecordBase, res)
+	log.Tracef("(Replied)\t %s: %d[ms]", function.Name, record.ActualDuration)
+	log.Tracef("(E2E Latency) %s: %.2f[ms]\n", function.Name, float64(record.ResponseTime)/1e3)
 
 	return true, record
 }
 
-func parseActivationMetadata(response string) (error, ActivationMetadata) {
-	var result ActivationMetadata
-	var jsonMap map[string]interface{}
-
-	ind := strings.Index(response, "{")
-	err := json.Unmarshal([]byte(response[ind:]), &jsonMap)
-	if err != nil {
-		return err, result
-	}
-
-	result.Duration = uint32(jsonMap["duration"].(float64))
-	result.StartType = mc.Hot
-	result.InitTime = 0
-	annotations := jsonMap["annotations"].([]interface{})
-	for i := 0; i < len(annotations); i++ {
-		annotation := annotations[i].(map[string]interface{})
-
-		if annotation["key"] == "waitTime" {
-			result.WaitTime = int64(annotation["value"].(float64))
-		} else if annotation["key"] == "initTime" {
-			result.StartType = mc.Cold
-			result.InitTime = int64(annotation["value"].(float64))
-		}
-	}
-
-	return nil, result
+func (i *openWhiskInvoker) PlatformRecordsName() string {
+	return "activations"
 }
 
-func httpInvocation(dataString string, function *common.Function, AnnounceDoneExe *sync.WaitGroup, tlsSkipVerify bool) (bool, *mc.ExecutionRecordBase, *http.Response) {
-	defer AnnounceDoneExe.Done()
+// WritePlatformRecords fetches the metadata of the activations that were still running at the end of their blocking
+// invocation and writes the records of all activations
+func (i *openWhiskInvoker) WritePlatformRecords(filename string) {
+	i.activationsMutex.Lock()
+	defer i.activationsMutex.Unlock()
 
-	record := &mc.ExecutionRecordBase{}
-
-	start := time.Now()
-	record.StartTime = start.UnixMicro()
-	record.Instance = function.Name
-	requestURL := function.Endpoint
-	if tlsSkipVerify {
-		http.DefaultTransport.(*http.Transport).TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-	}
-
-	if dataString != "" {
-		requestURL += "?" + dataString
-	}
-	req, err := http.NewRequest(http.MethodGet, requestURL, bytes.NewBuffer([]byte("")))
-	if err != nil {
-		log.Warnf("http request creation failed for function %s - %s", function.Name, err)
+	if len(i.pending) > 0 {
+		activationIDs := make([]string, 0, len(i.pending))
+		for id := range i.pending {
+			activationIDs = append(activationIDs, id)
+		}
 
-		record.ResponseTime = time.Since(start).Microseconds()
-		record.ConnectionTimeout = true
+		activations, err := i.client.FetchActivations(activationIDs, i.since)
+		if err != nil {
+			log.Errorf("Failed to fetch OpenWhisk activations - %v", err)
+		}
+		for id, activation := range activations {
+			setActivationMetadata(i.pending[id], activation)
+			delete(i.pending, id)
+		}
 
-		return false, record, nil
+		if len(i.pending) > 0 {
+			log.Warnf("Metadata of %d OpenWhisk activations could not be fetched.", len(i.pending))
+		}
 	}
 
-	req.Header.Set("Content-Type", "application/json") // To avoid data being base64encoded
+	mc.WriteOpenWhiskActivationRecords(filename, i.activations)
+}
 
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		log.Debugf("http request for function %s failed - %s", function.Name, err)
+// setActivationMetadata fills the record with the metadata OpenWhisk keeps of the activation
+func setActivationMetadata(record *mc.ExecutionRecordOpenWhisk, activation *OpenWhiskActivation) {
+	metadata := parseActivationMetadata(activation)
 
-		record.ResponseTime = time.Since(start).Microseconds()
-		record.ConnectionTimeout = true
+	record.ActualDuration = metadata.Duration * 1000 //ms to micro sec
+	record.StartType = metadata.StartType
+	record.InitTime = metadata.InitTime * 1000 //ms to micro sec
+	record.WaitTime = metadata.WaitTime * 1000 //ms to micro sec
+}
 
-		return false, record, resp
+func parseActivationMetadata(activation *OpenWhiskActivation) ActivationMetadata {
+	result := ActivationMetadata{
+		Duration:  uint32(activation.Duration),
+		StartType: mc.Hot,
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		log.Debugf("http request for function %s failed - error code: %s", function.Name, resp.Status)
-
-		record.ResponseTime = time.Since(start).Microseconds()
-		record.ConnectionTimeout = true
-
-		return false, record, resp
+	result.WaitTime, _ = activation.annotation("waitTime")
+	if initTime, ok := activation.annotation("initTime"); ok {
+		result.StartType = mc.Cold
+		result.InitTime = initTime
 	}
 
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		log.Warnf("Failed to read output %s - %v", function.Name, err)
-
-		record.ResponseTime = time.Since(start).Microseconds()
-		record.FunctionTimeout = true
+	return result
+}
 
-		return false, record, resp
+// activationExecutionTime returns the execution time in microseconds measured by the function, whose response is
+// returned as the base64-encoded body of the result
+func activationExecutionTime(activation *OpenWhiskActivation) (uint32, bool) {
+	var result struct {
+		Body []byte `json:"body"`
 	}
-
-	rawJson, err := base64.StdEncoding.DecodeString(string(bodyBytes))
-	if err != nil {
-		log.Warnf("Failed to decode base64 output %s - %v", function.Name, err)
-
-		record.ResponseTime = time.Since(start).Microseconds()
-		record.FunctionTimeout = true
-
-		return false, record, resp
+	if err := json.Unmarshal(activation.Response.Result, &result); err != nil {
+		return 0, false
 	}
 
-	var deserializedResponse FunctionResponse
-	err = json.Unmarshal(rawJson, &deserializedResponse)
-	if err != nil {
-		log.Warnf("Failed to deserialize response %s - %v", function.Name, err)
-
-		record.ResponseTime = time.Since(start).Microseconds()
-		record.FunctionTimeout = true
-
-		return false, record, resp
+	var response FunctionResponse
+	if err := json.Unmarshal(result.Body, &response); err != nil {
+		return 0, false
 	}
 
-	record.Instance = deserializedResponse.Function
-	record.ResponseTime = time.Since(start).Microseconds()
-	record.ActualDuration = uint32(deserializedResponse.ExecutionTime)
-
-	return true, record, resp
-}
-
-func logInvocationSummary(function *common.Function, record *mc.ExecutionRecordBase, res *http.Response) {
-	log.Tracef("(Replied)\t %s: %d[ms]", function.Name, record.ActualDuration)
-	log.Tracef("(E2E Latency) %s: %.2f[ms]\n", function.Name, float64(record.ResponseTime)/1e3)
-	log.Tracef("(Client status code) %s: %d", function.Name, res.StatusCode)
+	return uint32(response.ExecutionTime), true
 }
@@ -0,0 +1,130 @@
+package clients
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gocarina/gocsv"
+	"github.com/vhive-serverless/loader/pkg/common"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+func createFakeOpenWhiskServer(t *testing.T) *httptest.Server {
+	body, _ := json.Marshal(FunctionResponse{Status: "OK", ExecutionTime: 350000})
+	result, _ := json.Marshal(map[string][]byte{"body": body})
+
+	activation := func(id string, duration int64, success bool, annotations ...OpenWhiskAnnotation) OpenWhiskActivation {
+		a := OpenWhiskActivation{ActivationID: id, Duration: duration, Annotations: annotations}
+		a.Response.Success = success
+		a.Response.Result = result
+		return a
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, password, ok := r.BasicAuth(); !ok || user != "user" || password != "key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/namespaces/_/actions/cold":
+			var parameters map[string]string
+			if err := json.NewDecoder(r.Body).Decode(&parameters); err != nil || parameters["cpu"] != "300" {
+				t.Errorf("Unexpected parameters %v.", parameters)
+			}
+			_ = json.NewEncoder(w).Encode(activation("cold-1", 400, true,
+				OpenWhiskAnnotation{Key: "waitTime", Value: 5}, OpenWhiskAnnotation{Key: "initTime", Value: 50}))
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/namespaces/_/actions/slow":
+			w.WriteHeader(http.StatusAccepted)
+			_ = json.NewEncoder(w).Encode(map[string]string{"activationId": "slow-1"})
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/namespaces/_/actions/broken":
+			w.WriteHeader(http.StatusBadGateway)
+			_ = json.NewEncoder(w).Encode(activation("broken-1", 10, false, OpenWhiskAnnotation{Key: "waitTime", Value: 2}))
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/namespaces/_/activations":
+			if r.URL.Query().Get("docs") != "true" {
+				t.Error("Expected the full activation records to be requested.")
+			}
+			_ = json.NewEncoder(w).Encode([]OpenWhiskActivation{
+				activation("other-1", 1, true),
+				activation("slow-1", 90000, true, OpenWhiskAnnotation{Key: "waitTime", Value: 7}),
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestOpenWhiskInvoker(t *testing.T) {
+	server := createFakeOpenWhiskServer(t)
+	defer server.Close()
+
+	invoker := newOpenWhiskInvoker(newOpenWhiskClient(server.URL, "", "user:key"))
+	specification := &common.RuntimeSpecification{Runtime: 300, Memory: 128}
+
+	success, record := invoker.Invoke(&common.Function{Name: "cold"}, specification)
+	if !success || !record.ColdStart || !record.StartTypeReported || record.ActualDuration != 350000 || record.RequestedDuration != 300000 {
+		t.Errorf("Unexpected record of the cold activation %+v.", record)
+	}
+	if success, _ = invoker.Invoke(&common.Function{Name: "slow"}, specification); !success {
+		t.Error("Expected the activation still running to be successful.")
+	}
+	if success, record = invoker.Invoke(&common.Function{Name: "broken"}, specification); success || !record.FunctionTimeout {
+		t.Error("Expected the failed activation to fail.")
+	}
+	if success, record = invoker.Invoke(&common.Function{Name: "missing"}, specification); success || !record.ConnectionTimeout {
+		t.Error("Expected the invocation of a missing action to fail.")
+	}
+
+	filename := filepath.Join(t.TempDir(), "activations.csv")
+	invoker.WritePlatformRecords(filename)
+
+	file, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var records []mc.ExecutionRecordOpenWhisk
+	if err = gocsv.UnmarshalFile(file, &records); err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("Expected 3 activation records, got %d.", len(records))
+	}
+
+	expected := []mc.ExecutionRecordOpenWhisk{
+		{ActivationID: "cold-1", StartType: mc.Cold, HttpStatusCode: http.StatusOK, WaitTime: 5000, InitTime: 50000},
+		{ActivationID: "slow-1", StartType: mc.Hot, HttpStatusCode: http.StatusAccepted, WaitTime: 7000},
+		{ActivationID: "broken-1", StartType: mc.Hot, HttpStatusCode: http.StatusBadGateway, WaitTime: 2000},
+	}
+	for i, record := range records {
+		if record.ActivationID != expected[i].ActivationID || record.StartType != expected[i].StartType ||
+			record.HttpStatusCode != expected[i].HttpStatusCode || record.WaitTime != expected[i].WaitTime ||
+			record.InitTime != expected[i].InitTime {
+
+			t.Errorf("Expected activation record %+v, got %+v.", expected[i], record)
+		}
+	}
+	if records[1].ActualDuration != 90000000 {
+		t.Errorf("Expected the duration of the fetched activation, got %d.", records[1].ActualDuration)
+	}
+}
+
+func TestReadWskProperties(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".wskprops")
+	if err := os.WriteFile(path, []byte("APIHOST=10.0.0.1:31001\nAUTH=user:key\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("WSK_CONFIG_FILE", path)
+	client := NewOpenWhiskClient()
+
+	if client.ActionURL("trace-func-0") != "https://10.0.0.1:31001/api/v1/namespaces/_/actions/trace-func-0" || !strings.Contains(client.auth, ":") {
+		t.Errorf("Unexpected client %+v.", client)
+	}
+}
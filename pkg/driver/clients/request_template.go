@@ -0,0 +1,272 @@
+package clients
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"text/template"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+const (
+	BodyTypeTemplate = "template"
+	BodyTypeRandom   = "random"
+	BodyTypeFile     = "file"
+)
+
+// RequestTemplateData is the data every field of a request template is rendered with.
+type RequestTemplateData struct {
+	Name         string
+	Endpoint     string
+	HashOwner    string
+	HashApp      string
+	HashFunction string
+
+	Image               string
+	IterationMultiplier int
+	IOPercentage        int
+
+	Runtime              int
+	Memory               int
+	PayloadSizeBytes     int
+	ResponsePayloadBytes int
+}
+
+type requestTemplate struct {
+	method  *template.Template
+	path    *template.Template
+	headers map[string]*template.Template
+	body    *template.Template
+
+	bodyType      string
+	fileBody      []byte
+	payloadSizeKB *config.PayloadSizeDistribution
+}
+
+type requestTemplates struct {
+	defaultTemplate *requestTemplate
+	perFunction     map[string]*requestTemplate
+}
+
+func newRequestTemplates(cfg *config.RequestTemplateConfig) (*requestTemplates, error) {
+	result := &requestTemplates{
+		perFunction: make(map[string]*requestTemplate),
+	}
+
+	var err error
+	if cfg.Default != nil {
+		result.defaultTemplate, err = compileRequestTemplate("default", cfg.Default)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for key, t := range cfg.Functions {
+		result.perFunction[key], err = compileRequestTemplate(key, t)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+func compileRequestTemplate(name string, t *config.RequestTemplate) (*requestTemplate, error) {
+	var err error
+	result := &requestTemplate{
+		headers:       make(map[string]*template.Template),
+		bodyType:      strings.ToLower(t.BodyType),
+		payloadSizeKB: t.PayloadSizeKB,
+	}
+
+	method := t.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+	if result.method, err = template.New(name + ".method").Parse(method); err != nil {
+		return nil, fmt.Errorf("invalid method template of '%s' - %v", name, err)
+	}
+	if result.path, err = template.New(name + ".path").Parse(t.Path); err != nil {
+		return nil, fmt.Errorf("invalid path template of '%s' - %v", name, err)
+	}
+
+	for header, value := range t.Headers {
+		if result.headers[header], err = template.New(name + "." + header).Parse(value); err != nil {
+			return nil, fmt.Errorf("invalid template of header '%s' in '%s' - %v", header, name, err)
+		}
+	}
+
+	switch result.bodyType {
+	case "", BodyTypeTemplate:
+		result.bodyType = BodyTypeTemplate
+		if result.body, err = template.New(name + ".body").Parse(t.Body); err != nil {
+			return nil, fmt.Errorf("invalid body template of '%s' - %v", name, err)
+		}
+	case BodyTypeRandom:
+		// without a distribution in the template, the payload size is taken from the runtime specification
+		if t.PayloadSizeKB != nil && (t.PayloadSizeKB.MinKB < 0 || t.PayloadSizeKB.MaxKB < t.PayloadSizeKB.MinKB) {
+			return nil, fmt.Errorf("invalid payload size distribution of '%s'", name)
+		}
+	case BodyTypeFile:
+		if result.fileBody, err = os.ReadFile(t.BodyFile); err != nil {
+			return nil, fmt.Errorf("failed to read body file of '%s' - %v", name, err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported body type '%s' of '%s'", t.BodyType, name)
+	}
+
+	return result, nil
+}
+
+// lookup returns the template of the given function. Templates keyed by function name take precedence over
+// the ones keyed by the trace hash, which take precedence over the default template.
+func (r *requestTemplates) lookup(function *common.Function) *requestTemplate {
+	if t, ok := r.perFunction[function.Name]; ok {
+		return t
+	}
+	if function.InvocationStats != nil {
+		if t, ok := r.perFunction[function.InvocationStats.HashFunction]; ok {
+			return t
+		}
+	}
+
+	return r.defaultTemplate
+}
+
+func newRequestTemplateData(function *common.Function, runtimeSpec *common.RuntimeSpecification) *RequestTemplateData {
+	data := &RequestTemplateData{
+		Name:     function.Name,
+		Endpoint: function.Endpoint,
+		Runtime:  runtimeSpec.Runtime,
+		Memory:   runtimeSpec.Memory,
+
+		PayloadSizeBytes:     runtimeSpec.RequestPayloadBytes,
+		ResponsePayloadBytes: runtimeSpec.ResponsePayloadBytes,
+	}
+
+	if function.InvocationStats != nil {
+		data.HashOwner = function.InvocationStats.HashOwner
+		data.HashApp = function.InvocationStats.HashApp
+		data.HashFunction = function.InvocationStats.HashFunction
+	}
+	if function.DirigentMetadata != nil {
+		data.Image = function.DirigentMetadata.Image
+		data.IterationMultiplier = function.DirigentMetadata.IterationMultiplier
+		data.IOPercentage = function.DirigentMetadata.IOPercentage
+	}
+
+	return data
+}
+
+func (t *requestTemplate) payloadSize(runtimeSpec *common.RuntimeSpecification) int {
+	if t.payloadSizeKB == nil {
+		return runtimeSpec.RequestPayloadBytes
+	}
+
+	minBytes := int(t.payloadSizeKB.MinKB * 1024.0)
+	maxBytes := int(t.payloadSizeKB.MaxKB * 1024.0)
+
+	return common.RandIntBetween(minBytes, maxBytes)
+}
+
+func render(t *template.Template, data *RequestTemplateData) (string, error) {
+	var buffer bytes.Buffer
+	if err := t.Execute(&buffer, data); err != nil {
+		return "", err
+	}
+
+	return buffer.String(), nil
+}
+
+// newRequest renders the template into a request. If host is not empty, it is used as the request host unless
+// the template specifies a Host header.
+func (t *requestTemplate) newRequest(function *common.Function, runtimeSpec *common.RuntimeSpecification, host string) (*http.Request, error) {
+	data := newRequestTemplateData(function, runtimeSpec)
+	data.PayloadSizeBytes = t.payloadSize(runtimeSpec)
+
+	method, err := render(t.method, data)
+	if err != nil {
+		return nil, err
+	}
+	path, err := render(t.path, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var body *bytes.Buffer
+	switch t.bodyType {
+	case BodyTypeTemplate:
+		rendered, err := render(t.body, data)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewBufferString(rendered)
+	case BodyTypeRandom:
+		body = bytes.NewBuffer(randomPayload(data.PayloadSizeBytes))
+	case BodyTypeFile:
+		body = bytes.NewBuffer(t.fileBody)
+	}
+
+	if path != "" && !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	req, err := http.NewRequest(strings.ToUpper(method), fmt.Sprintf("http://%s%s", function.Endpoint, path), body)
+	if err != nil {
+		return nil, err
+	}
+	if host != "" {
+		req.Host = host
+	}
+
+	for header, value := range t.headers {
+		rendered, err := render(value, data)
+		if err != nil {
+			return nil, err
+		}
+
+		if strings.EqualFold(header, "Host") {
+			req.Host = rendered
+		} else {
+			req.Header.Set(header, rendered)
+		}
+	}
+
+	return req, nil
+}
+
+var (
+	randomPayloadBuffer []byte
+	randomPayloadMutex  sync.Mutex
+)
+
+const randomPayloadAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// randomPayload returns a slice of random alphanumeric bytes of the given size, so that the payload can also be sent
+// as a protobuf string. The bytes are generated once, grown when a larger payload is requested, and shared across
+// invocations, as generating them on each invocation would slow down the loader considerably.
+func randomPayload(size int) []byte {
+	randomPayloadMutex.Lock()
+	defer randomPayloadMutex.Unlock()
+
+	if len(randomPayloadBuffer) < size {
+		buffer := make([]byte, size)
+		if _, err := rand.Read(buffer); err != nil {
+			log.Errorf("Failed to generate random %d bytes.", size)
+		}
+		for i := range buffer {
+			buffer[i] = randomPayloadAlphabet[int(buffer[i])%len(randomPayloadAlphabet)]
+		}
+
+		randomPayloadBuffer = buffer
+	}
+
+	return randomPayloadBuffer[:size]
+}
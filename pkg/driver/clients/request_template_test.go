@@ -0,0 +1,82 @@
+package clients
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+func TestRequestTemplate(t *testing.T) {
+	templates, err := newRequestTemplates(&config.RequestTemplateConfig{
+		Default: &config.RequestTemplate{
+			Method: "PUT",
+			Path:   "invoke/{{.Name}}",
+			Headers: map[string]string{
+				"runtime": "{{.Runtime}}",
+				"Host":    "{{.HashFunction}}.example.com",
+			},
+			Body: `{"memory": {{.Memory}}, "image": "{{.Image}}"}`,
+		},
+		Functions: map[string]*config.RequestTemplate{
+			"hash-random": {
+				BodyType:      BodyTypeRandom,
+				PayloadSizeKB: &config.PayloadSizeDistribution{MinKB: 2, MaxKB: 2},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to compile request templates - %v", err)
+	}
+
+	function := &common.Function{
+		Name:             "test-function",
+		Endpoint:         "localhost:8080",
+		InvocationStats:  &common.FunctionInvocationStats{HashFunction: "hash"},
+		DirigentMetadata: &common.DirigentMetadata{Image: "image"},
+	}
+	runtimeSpec := &common.RuntimeSpecification{Runtime: 10, Memory: 128}
+
+	req, err := templates.lookup(function).newRequest(function, runtimeSpec, function.Name)
+	if err != nil {
+		t.Fatalf("Failed to create request - %v", err)
+	}
+
+	body, _ := io.ReadAll(req.Body)
+	if req.Method != http.MethodPut ||
+		req.URL.String() != "http://localhost:8080/invoke/test-function" ||
+		req.Header.Get("runtime") != "10" ||
+		req.Host != "hash.example.com" ||
+		string(body) != `{"memory": 128, "image": "image"}` {
+
+		t.Errorf("Unexpected request created from the default template: %s %s %v %s", req.Method, req.URL, req.Header, body)
+	}
+
+	function.InvocationStats.HashFunction = "hash-random"
+	req, err = templates.lookup(function).newRequest(function, runtimeSpec, function.Name)
+	if err != nil {
+		t.Fatalf("Failed to create request - %v", err)
+	}
+
+	body, _ = io.ReadAll(req.Body)
+	if req.Method != http.MethodPost || req.Host != function.Name || len(body) != 2048 {
+		t.Errorf("Unexpected request created from the per-function template: %s %s %d", req.Method, req.Host, len(body))
+	}
+}
+
+func TestInvalidRequestTemplate(t *testing.T) {
+	invalid := []*config.RequestTemplate{
+		{Path: "{{.Name"},
+		{BodyType: "unknown"},
+		{BodyType: BodyTypeRandom, PayloadSizeKB: &config.PayloadSizeDistribution{MinKB: 2, MaxKB: 1}},
+		{BodyType: BodyTypeFile, BodyFile: "non-existing-file"},
+	}
+
+	for _, template := range invalid {
+		if _, err := newRequestTemplates(&config.RequestTemplateConfig{Default: template}); err == nil {
+			t.Errorf("Expected an error while compiling %+v", template)
+		}
+	}
+}
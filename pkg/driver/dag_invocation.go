@@ -0,0 +1,181 @@
+package driver
+
+import (
+	"container/list"
+	"hash/fnv"
+	"math/rand"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+// DAGInvocation tracks a single invocation of a DAG workflow across the goroutines invoking its branches
+type DAGInvocation struct {
+	record *mc.DAGExecutionRecord
+	start  time.Time
+
+	// goroutines still invoking branches of the DAG
+	pending     atomic.Int64
+	invocations atomic.Int64
+	failed      atomic.Bool
+
+	mutex sync.Mutex
+	// number of parents of each join node that have completed and the sum of their output payload sizes
+	arrivals map[*common.Node]int
+	inputs   map[*common.Node]int
+
+	// edges taken by the invocation, drawn upfront so that the path does not depend on the scheduling of the branches
+	taken map[dagEdge]bool
+}
+
+type dagEdge struct {
+	parent, child *common.Node
+}
+
+// NewDAGInvocation creates the invocation of the DAG and draws the path it takes through the conditional branches. The
+// path depends only on the seed, the DAG and the invocation ID.
+func NewDAGInvocation(rootFunction *list.List, phase common.ExperimentPhase, invocationID string, seed int64) *DAGInvocation {
+	start := time.Now()
+	dag := strings.TrimSuffix(rootFunction.Front().Value.(*common.Node).DAG, ",")
+
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(dag + "/" + invocationID))
+
+	i := &DAGInvocation{
+		record: &mc.DAGExecutionRecord{
+			Phase:        int(phase),
+			DAG:          dag,
+			InvocationID: invocationID,
+			StartTime:    start.UnixMicro(),
+		},
+		start:    start,
+		arrivals: make(map[*common.Node]int),
+		inputs:   make(map[*common.Node]int),
+		taken:    make(map[dagEdge]bool),
+	}
+
+	var path []string
+	i.planBranch(rootFunction, rand.New(rand.NewSource(seed^int64(hash.Sum64()))), make(map[*common.Node]int), &path)
+	i.record.Path = strings.Join(path, " ")
+
+	return i
+}
+
+// planBranch draws the children of each node of the branch and follows the taken edges depth-first, appending the
+// nodes to be invoked to the path. A join node is only invoked if the edges from all its parents are taken.
+func (i *DAGInvocation) planBranch(branch *list.List, random *rand.Rand, arrivals map[*common.Node]int, path *[]string) {
+	for element := branch.Front(); element != nil; element = element.Next() {
+		node := element.Value.(*common.Node)
+		if node.Parents > 1 {
+			if arrivals[node]++; arrivals[node] < node.Parents {
+				return
+			}
+		}
+
+		if node.ID != "" {
+			*path = append(*path, node.ID)
+		} else {
+			*path = append(*path, node.Function.Name)
+		}
+
+		var children []*common.Node
+		if element.Next() != nil {
+			children = append(children, element.Next().Value.(*common.Node))
+		}
+		for _, child := range node.Branches {
+			children = append(children, child.Front().Value.(*common.Node))
+		}
+		for _, child := range drawChildren(node, children, random) {
+			i.taken[dagEdge{parent: node, child: child}] = true
+		}
+
+		for _, child := range node.Branches {
+			if i.isTaken(node, child.Front().Value.(*common.Node)) {
+				i.planBranch(child, random, arrivals, path)
+			}
+		}
+
+		if element.Next() == nil || !i.isTaken(node, element.Next().Value.(*common.Node)) {
+			return
+		}
+	}
+}
+
+// drawChildren returns the children invoked after the node, i.e., exactly one child drawn by the weights if the node is
+// a choice, and each child with its probability otherwise
+func drawChildren(node *common.Node, children []*common.Node, random *rand.Rand) []*common.Node {
+	probability := func(child *common.Node) float64 {
+		if child.Probability == 0 {
+			return 1
+		}
+		return child.Probability
+	}
+
+	if !node.Choice {
+		var result []*common.Node
+		for _, child := range children {
+			if p := probability(child); p >= 1 || random.Float64() < p {
+				result = append(result, child)
+			}
+		}
+
+		return result
+	}
+
+	if len(children) == 0 {
+		return nil
+	}
+
+	var total float64
+	for _, child := range children {
+		total += probability(child)
+	}
+
+	draw := random.Float64() * total
+	for _, child := range children {
+		if draw -= probability(child); draw < 0 {
+			return []*common.Node{child}
+		}
+	}
+
+	return []*common.Node{children[len(children)-1]}
+}
+
+// isTaken returns whether the invocation continues from the parent to the child
+func (i *DAGInvocation) isTaken(parent *common.Node, child *common.Node) bool {
+	return i == nil || i.taken[dagEdge{parent: parent, child: child}]
+}
+
+// join accounts the completion of a parent of the join node and returns whether all its parents have completed,
+// along with the sum of their output payload sizes
+func (i *DAGInvocation) join(node *common.Node, inputPayloadBytes int) (bool, int) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	i.arrivals[node]++
+	i.inputs[node] += inputPayloadBytes
+
+	return i.arrivals[node] == node.Parents, i.inputs[node]
+}
+
+func (i *DAGInvocation) branchStarted() {
+	i.pending.Add(1)
+}
+
+// branchCompleted returns the end-to-end record of the DAG invocation once its last branch has completed, nil
+// otherwise
+func (i *DAGInvocation) branchCompleted() *mc.DAGExecutionRecord {
+	if i.pending.Add(-1) > 0 {
+		return nil
+	}
+
+	i.record.ResponseTime = time.Since(i.start).Microseconds()
+	i.record.Invocations = i.invocations.Load()
+	i.record.Failed = i.failed.Load()
+
+	return i.record
+}
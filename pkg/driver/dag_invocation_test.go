@@ -0,0 +1,77 @@
+package driver
+
+import (
+	"container/list"
+	"fmt"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+func createTestBranch(nodes ...*common.Node) *list.List {
+	branch := list.New()
+	for _, node := range nodes {
+		branch.PushBack(node)
+	}
+
+	return branch
+}
+
+func TestDAGInvocationChoice(t *testing.T) {
+	// a chooses between b (weight 0.2) and c (weight 0.8), b -> d
+	a := &common.Node{ID: "a", DAG: "DAG choice,", Choice: true}
+	b := &common.Node{ID: "b", Probability: 0.2}
+	c := &common.Node{ID: "c", Probability: 0.8}
+	d := &common.Node{ID: "d"}
+	a.Branches = []*list.List{createTestBranch(c)}
+	rootFunction := createTestBranch(a, b, d)
+
+	paths := make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		invocationID := composeInvocationID(common.MinuteGranularity, 0, i)
+		invocation := NewDAGInvocation(rootFunction, common.ExecutionPhase, invocationID, 42)
+		paths[invocation.record.Path]++
+
+		if again := NewDAGInvocation(rootFunction, common.ExecutionPhase, invocationID, 42); again.record.Path != invocation.record.Path {
+			t.Fatalf("Expected the same path for the same seed, got %s and %s.", invocation.record.Path, again.record.Path)
+		}
+	}
+
+	if len(paths) != 2 || paths["a b d"]+paths["a c"] != 1000 {
+		t.Fatalf("Expected exactly one child of the choice to be taken, got %v.", paths)
+	}
+	if paths["a c"] < 750 || paths["a c"] > 850 {
+		t.Errorf("Expected c to be chosen about 800 times, got %d.", paths["a c"])
+	}
+}
+
+func TestDAGInvocationProbability(t *testing.T) {
+	// a -> b with the probability of 0.5, a -> c -> d where d joins b and c
+	a := &common.Node{ID: "a", DAG: "DAG probability,"}
+	b := &common.Node{ID: "b", Probability: 0.5}
+	c := &common.Node{ID: "c"}
+	d := &common.Node{ID: "d", Parents: 2}
+	join := createTestBranch(d)
+	b.Branches = []*list.List{join}
+	c.Branches = []*list.List{join}
+	a.Branches = []*list.List{createTestBranch(c)}
+	rootFunction := createTestBranch(a, b)
+
+	paths := make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		invocation := NewDAGInvocation(rootFunction, common.ExecutionPhase, fmt.Sprintf("%d", i), 42)
+		paths[invocation.record.Path]++
+
+		if !invocation.isTaken(a, c) {
+			t.Fatalf("Expected the unconditional edge to be taken.")
+		}
+	}
+
+	// the join is not invoked if b is skipped
+	if len(paths) != 2 || paths["a c b d"]+paths["a c"] != 1000 {
+		t.Fatalf("Unexpected paths %v.", paths)
+	}
+	if paths["a c b d"] < 450 || paths["a c b d"] > 550 {
+		t.Errorf("Expected b to be taken about 500 times, got %d.", paths["a c b d"])
+	}
+}
@@ -0,0 +1,186 @@
+package deployment
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+// fakeLambdaControlPlane serves the function management API of Lambda
+type fakeLambdaControlPlane struct {
+	mutex     sync.Mutex
+	functions map[string]map[string]interface{}
+	updated   map[string]bool
+	// tags by function ARN
+	tags map[string]map[string]string
+}
+
+func (cp *fakeLambdaControlPlane) writeError(w http.ResponseWriter, status int, errorType string) {
+	w.Header().Set("X-Amzn-ErrorType", errorType)
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(`{"Type": "User", "message": "` + errorType + `"}`))
+}
+
+func (cp *fakeLambdaControlPlane) configuration(name string) map[string]interface{} {
+	return map[string]interface{}{
+		"FunctionName":     name,
+		"FunctionArn":      "arn:aws:lambda:eu-west-1:123456789012:function:" + name,
+		"State":            "Active",
+		"LastUpdateStatus": "Successful",
+	}
+}
+
+func (cp *fakeLambdaControlPlane) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cp.mutex.Lock()
+	defer cp.mutex.Unlock()
+
+	if arn, ok := strings.CutPrefix(r.URL.Path, "/2017-03-31/tags/"); ok && r.Method == http.MethodPost {
+		var input struct{ Tags map[string]string }
+		_ = json.NewDecoder(r.Body).Decode(&input)
+
+		cp.tags[arn] = input.Tags
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/2015-03-31/functions")
+	name, suffix, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+
+	switch {
+	case r.Method == http.MethodPost && path == "":
+		var input map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&input)
+
+		name = input["FunctionName"].(string)
+		if _, exists := cp.functions[name]; exists {
+			cp.writeError(w, http.StatusConflict, "ResourceConflictException")
+			return
+		}
+		cp.functions[name] = input
+
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(cp.configuration(name))
+	case r.Method == http.MethodGet && suffix == "":
+		if _, exists := cp.functions[name]; !exists {
+			cp.writeError(w, http.StatusNotFound, "ResourceNotFoundException")
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"Configuration": cp.configuration(name)})
+	case r.Method == http.MethodPut && (suffix == "code" || suffix == "configuration"):
+		cp.updated[name+"/"+suffix] = true
+		_ = json.NewEncoder(w).Encode(cp.configuration(name))
+	case r.Method == http.MethodDelete && suffix == "":
+		if _, exists := cp.functions[name]; !exists {
+			cp.writeError(w, http.StatusNotFound, "ResourceNotFoundException")
+			return
+		}
+		delete(cp.functions, name)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		cp.writeError(w, http.StatusBadRequest, "InvalidRequestContentException")
+	}
+}
+
+func newTestAWSLambdaConfiguration(t *testing.T, endpointURL string, functions []*common.Function) *config.Configuration {
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(t.TempDir(), "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "credentials"))
+
+	return &config.Configuration{
+		LoaderConfiguration: &config.LoaderConfiguration{
+			Platform:         common.PlatformAWSLambda,
+			OutputPathPrefix: filepath.Join(t.TempDir(), "experiment"),
+			AWSLambda: &config.AWSLambdaConfig{
+				Region:      "eu-west-1",
+				EndpointURL: endpointURL,
+				ImageURI:    "123456789012.dkr.ecr.eu-west-1.amazonaws.com/invitro_trace_function_aws:latest",
+				RoleARN:     "arn:aws:iam::123456789012:role/lambda",
+				MemoryMB:    512,
+			},
+		},
+		Functions: functions,
+		RunID:     "run-a",
+	}
+}
+
+func TestAWSLambdaDeployAndClean(t *testing.T) {
+	controlPlane := &fakeLambdaControlPlane{
+		functions: map[string]map[string]interface{}{"trace-func-1": {}},
+		updated:   make(map[string]bool),
+		tags:      make(map[string]map[string]string),
+	}
+	server := httptest.NewServer(controlPlane)
+	defer server.Close()
+
+	functions := []*common.Function{{Name: "trace-func-0"}, {Name: "trace-func-1"}}
+	cfg := newTestAWSLambdaConfiguration(t, server.URL, functions)
+	// next to the outputs by default
+	runRecords := filepath.Join(filepath.Dir(cfg.LoaderConfiguration.OutputPathPrefix), "runs")
+
+	deployer := newAWSLambdaDeployer()
+	deployer.Deploy(cfg)
+
+	created := controlPlane.functions["trace-func-0"]
+	if created["PackageType"] != "Image" || created["MemorySize"] != float64(512) || created["Timeout"] != float64(900) ||
+		created["Tags"].(map[string]interface{})[runIDLabel] != "run-a" {
+
+		t.Errorf("Unexpected function %v.", created)
+	}
+	if !controlPlane.updated["trace-func-1/code"] || !controlPlane.updated["trace-func-1/configuration"] {
+		t.Error("Expected the existing function to be updated.")
+	}
+	if tags := controlPlane.tags[controlPlane.configuration("trace-func-1")["FunctionArn"].(string)]; tags[runIDLabel] != "run-a" {
+		t.Errorf("Expected the updated function to be tagged with the run ID, got %v.", tags)
+	}
+	for _, function := range functions {
+		if !strings.HasSuffix(function.Endpoint, ":function:"+function.Name) {
+			t.Errorf("Expected the ARN as the endpoint of %s, got %s.", function.Name, function.Endpoint)
+		}
+	}
+	if _, err := os.Stat(runRecordPath(runRecords, "run-a")); err != nil {
+		t.Error("Expected the run to be recorded.")
+	}
+
+	deployer.Clean()
+
+	if len(controlPlane.functions) != 0 {
+		t.Errorf("Expected all functions to be deleted, got %v.", controlPlane.functions)
+	}
+	if _, err := os.Stat(runRecordPath(runRecords, "run-a")); !os.IsNotExist(err) {
+		t.Error("Expected the record of the cleaned run to be removed.")
+	}
+}
+
+func TestAWSLambdaCleanRun(t *testing.T) {
+	controlPlane := &fakeLambdaControlPlane{
+		functions: map[string]map[string]interface{}{"trace-func-0": {}, "trace-func-2": {}},
+		updated:   make(map[string]bool),
+		tags:      make(map[string]map[string]string),
+	}
+	server := httptest.NewServer(controlPlane)
+	defer server.Close()
+
+	cfg := newTestAWSLambdaConfiguration(t, server.URL, nil)
+	runRecords := runRecordDirectory(cfg.LoaderConfiguration)
+
+	// trace-func-1 has already been deleted
+	writeRunRecord(runRecords, runRecord{RunID: "run-a", Platform: common.PlatformAWSLambda, Functions: []string{"trace-func-0", "trace-func-1"}})
+
+	newAWSLambdaDeployer().CleanRun(cfg, "run-a")
+
+	if _, exists := controlPlane.functions["trace-func-0"]; exists || len(controlPlane.functions) != 1 {
+		t.Errorf("Expected only the functions of the run to be deleted, got %v.", controlPlane.functions)
+	}
+	if _, err := os.Stat(runRecordPath(runRecords, "run-a")); !os.IsNotExist(err) {
+		t.Error("Expected the record of the cleaned run to be removed.")
+	}
+}
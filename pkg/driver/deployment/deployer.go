@@ -8,10 +8,17 @@ import (
 
 type FunctionDeployer interface {
 	Deploy(cfg *config.Configuration)
+	// Clean removes the resources created by Deploy
 	Clean()
+	// CleanRun removes the resources left by the run with the given ID, e.g., by a crashed run
+	CleanRun(cfg *config.Configuration, runID string)
 }
 
 func CreateDeployer(cfg *config.Configuration) FunctionDeployer {
+	if cfg.LoaderConfiguration.ReuseDeployment && cfg.LoaderConfiguration.Platform != common.PlatformKnative {
+		logrus.Warnf("Reusing the deployment is not supported on %s, deploying all functions.", cfg.LoaderConfiguration.Platform)
+	}
+
 	switch cfg.LoaderConfiguration.Platform {
 	case common.PlatformAWSLambda:
 		return newAWSLambdaDeployer()
@@ -15,10 +15,17 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
-type dirigentDeployer struct{}
+type dirigentDeployer struct {
+	controlPlaneAddress string
+	runID               string
+	functions           []string
+	workflows           []string
+	runRecords          string
+}
 
 type dirigentDeploymentConfiguration struct {
 	RegistrationServer string
@@ -38,6 +45,9 @@ func newDirigentDeployerConfiguration(cfg *config.Configuration) dirigentDeploym
 
 func (d *dirigentDeployer) Deploy(cfg *config.Configuration) {
 	dirigentDeployerConfig := newDirigentDeployerConfiguration(cfg)
+	d.controlPlaneAddress = dirigentDeployerConfig.RegistrationServer
+	d.runID = cfg.RunID
+	d.runRecords = runRecordDirectory(cfg.LoaderConfiguration)
 
 	endpoint := ""
 
@@ -48,6 +58,12 @@ func (d *dirigentDeployer) Deploy(cfg *config.Configuration) {
 		}
 		wfConfig := config.ReadWorkflowConfig(wfConfigPath)
 
+		for _, wfFunc := range wfConfig.Functions {
+			d.functions = append(d.functions, wfFunc.FunctionName)
+		}
+		d.workflows = []string{cfg.Functions[0].Name}
+		writeRunRecord(d.runRecords, runRecord{RunID: d.runID, Platform: common.PlatformDirigent, Functions: d.functions, Workflows: d.workflows})
+
 		dMetadata := cfg.Functions[0].DirigentMetadata
 		if dMetadata == nil {
 			log.Fatalf("No Dirigent metadata for workflow %s", cfg.Functions[0].Name)
@@ -100,6 +116,11 @@ func (d *dirigentDeployer) Deploy(cfg *config.Configuration) {
 		cfg.Functions = newFunctions
 
 	} else {
+		for _, function := range cfg.Functions {
+			d.functions = append(d.functions, function.Name)
+		}
+		writeRunRecord(d.runRecords, runRecord{RunID: d.runID, Platform: common.PlatformDirigent, Functions: d.functions})
+
 		wg := &sync.WaitGroup{}
 		wg.Add(len(cfg.Functions))
 
@@ -125,7 +146,79 @@ func (d *dirigentDeployer) Deploy(cfg *config.Configuration) {
 	}
 }
 
-func (*dirigentDeployer) Clean() {}
+func (d *dirigentDeployer) Clean() {
+	if deregisterDirigentRun(d.controlPlaneAddress, d.workflows, d.functions) {
+		removeRunRecord(d.runRecords, d.runID)
+	}
+}
+
+// CleanRun deregisters the workflows and the functions recorded for the run from the control plane
+func (d *dirigentDeployer) CleanRun(cfg *config.Configuration, runID string) {
+	directory := runRecordDirectory(cfg.LoaderConfiguration)
+	record := readRunRecord(directory, runID, common.PlatformDirigent)
+	if deregisterDirigentRun(newDirigentDeployerConfiguration(cfg).RegistrationServer, record.Workflows, record.Functions) {
+		removeRunRecord(directory, runID)
+	}
+}
+
+// deregisterDirigentRun deregisters the workflows before the functions they are composed of and returns whether all of
+// them have been deregistered
+func deregisterDirigentRun(controlPlaneAddress string, workflows []string, functions []string) bool {
+	deregistered := true
+	for _, name := range workflows {
+		deregistered = deregisterDirigent(controlPlaneAddress, "workflow/deregistration", name) && deregistered
+	}
+
+	return deregisterDirigentFunctions(controlPlaneAddress, functions) && deregistered
+}
+
+// deregisterDirigentFunctions returns whether all the functions have been deregistered
+func deregisterDirigentFunctions(controlPlaneAddress string, functions []string) bool {
+	var failed atomic.Int64
+
+	wg := &sync.WaitGroup{}
+	for _, name := range functions {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if !deregisterDirigent(controlPlaneAddress, "deregistration", name) {
+				failed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if failed.Load() > 0 {
+		log.Errorf("Failed to deregister %d out of %d functions from Dirigent", failed.Load(), len(functions))
+		return false
+	}
+
+	log.Debugf("Deregistered %d functions from Dirigent", len(functions))
+	return true
+}
+
+// deregisterDirigent removes the function or the workflow through the given endpoint of the control plane
+func deregisterDirigent(controlPlaneAddress string, endpoint string, name string) bool {
+	resp, err := registrationClient.PostForm(fmt.Sprintf("http://%s/%s", controlPlaneAddress, endpoint), url.Values{"name": {name}})
+	if err != nil {
+		log.Errorf("Failed to deregister %s from the control plane - %v", name, err)
+		return false
+	}
+	defer resp.Body.Close()
+
+	// the service has already been removed
+	if resp.StatusCode == http.StatusNotFound {
+		return true
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		log.Errorf("Got status code %d while deregistering %s. Body: %s", resp.StatusCode, name, body)
+		return false
+	}
+
+	return true
+}
 
 var registrationClient = &http.Client{
 	Timeout: 300 * time.Second, // time for a request to timeout
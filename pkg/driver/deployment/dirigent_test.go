@@ -0,0 +1,62 @@
+package deployment
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+func TestDirigentCleanRun(t *testing.T) {
+	runRecords := t.TempDir()
+
+	var mutex sync.Mutex
+	deregistered := make(map[string]bool)
+	var workflows []string
+	controlPlane := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		switch r.URL.Path {
+		case "/deregistration":
+			if len(workflows) == 0 {
+				// the workflow is deregistered before its functions
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			deregistered[r.FormValue("name")] = true
+		case "/workflow/deregistration":
+			workflows = append(workflows, r.FormValue("name"))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer controlPlane.Close()
+
+	writeRunRecord(runRecords, runRecord{RunID: "run-a", Platform: common.PlatformDirigent, Functions: []string{"trace-func-0", "trace-func-1"}, Workflows: []string{"workflow"}})
+	writeRunRecord(runRecords, runRecord{RunID: "run-b", Platform: common.PlatformDirigent, Functions: []string{"trace-func-2"}})
+
+	cfg := &config.Configuration{
+		LoaderConfiguration:   &config.LoaderConfiguration{Platform: common.PlatformDirigent, RunRecordDirectory: runRecords},
+		DirigentConfiguration: &config.DirigentConfig{DirigentControlPlaneIP: strings.TrimPrefix(controlPlane.URL, "http://")},
+	}
+	newDirigentDeployer().CleanRun(cfg, "run-a")
+
+	if len(deregistered) != 2 || !deregistered["trace-func-0"] || !deregistered["trace-func-1"] {
+		t.Errorf("Expected only the functions of the run to be deregistered, got %v.", deregistered)
+	}
+	if len(workflows) != 1 || workflows[0] != "workflow" {
+		t.Errorf("Expected the workflow of the run to be deregistered, got %v.", workflows)
+	}
+	if _, err := os.Stat(runRecordPath(runRecords, "run-a")); !os.IsNotExist(err) {
+		t.Error("Expected the record of the cleaned run to be removed.")
+	}
+	if _, err := os.Stat(runRecordPath(runRecords, "run-b")); err != nil {
+		t.Error("Expected the record of the other run to remain.")
+	}
+}
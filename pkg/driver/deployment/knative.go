@@ -1,36 +1,70 @@
 package deployment
 
 import (
-	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"math"
-	"os/exec"
-	"regexp"
+	"os"
 	"runtime"
 	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
-	"github.com/go-cmd/cmd"
 	log "github.com/sirupsen/logrus"
 	"github.com/vhive-serverless/loader/pkg/common"
 	"github.com/vhive-serverless/loader/pkg/config"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/yaml"
 )
 
 const (
 	bareMetalLbGateway = "10.200.3.4.sslip.io" // Address of the bare-metal load balancer.
 	namespace          = "default"
-)
 
-var (
-	urlRegex = regexp.MustCompile("at URL:\nhttp://([^\n]+)")
+	knativeReadyPollInterval = time.Second
+	knativeReadyTimeout      = 30 * time.Minute
 )
 
-type knativeDeployer struct{}
+// knativeSpecHashAnnotation is the hash of the specification a service has been deployed from, which tells whether the
+// service can be reused by another run
+const knativeSpecHashAnnotation = "loader.vhive.io/spec-hash"
+
+var knativeServiceResource = schema.GroupVersionResource{Group: "serving.knative.dev", Version: "v1", Resource: "services"}
+
+// knativeCleanupResources are the kinds of resources removed by the cleanup, i.e., the Knative services and the
+// resources of the predeployment YAMLs
+var knativeCleanupResources = []schema.GroupVersionResource{
+	knativeServiceResource,
+	{Group: "apps", Version: "v1", Resource: "deployments"},
+	{Version: "v1", Resource: "services"},
+	{Version: "v1", Resource: "pods"},
+}
+
+type knativeDeployer struct {
+	// created from the kubeconfig on the first deployment if nil
+	client dynamic.Interface
+	runID  string
+	reused atomic.Int64
+}
 
 type knativeDeploymentConfiguration struct {
 	IsPartiallyPanic  bool
 	EndpointPort      int
 	AutoscalingMetric string
+	ReuseDeployment   bool
 }
 
 func newKnativeDeployer() *knativeDeployer {
@@ -42,11 +76,44 @@ func newKnativeDeployerConfiguration(cfg *config.Configuration) knativeDeploymen
 		IsPartiallyPanic:  cfg.LoaderConfiguration.IsPartiallyPanic,
 		EndpointPort:      cfg.LoaderConfiguration.EndpointPort,
 		AutoscalingMetric: cfg.LoaderConfiguration.AutoscalingMetric,
+		ReuseDeployment:   cfg.LoaderConfiguration.ReuseDeployment,
 	}
 }
 
-func (*knativeDeployer) Deploy(cfg *config.Configuration) {
+// newKubernetesDynamicClient creates a client from the kubeconfig ($KUBECONFIG or ~/.kube/config), or from the
+// service account if the loader runs inside the cluster
+func newKubernetesDynamicClient() (dynamic.Interface, error) {
+	restConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		log.Debugf("Failed to load kubeconfig, falling back to the in-cluster configuration - %v", err)
+
+		restConfig, err = rest.InClusterConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure the Kubernetes client - %v", err)
+		}
+	}
+
+	client, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the Kubernetes client - %v", err)
+	}
+
+	return client, nil
+}
+
+func (d *knativeDeployer) Deploy(cfg *config.Configuration) {
 	knativeConfig := newKnativeDeployerConfiguration(cfg)
+	d.runID = cfg.RunID
+	if d.client == nil {
+		client, err := newKubernetesDynamicClient()
+		if err != nil {
+			// not fatal, so that the loader can be run locally without a cluster
+			log.Warnf("Skipping the deployment of Knative services: %v", err)
+			return
+		}
+		d.client = client
+	}
 
 	queue := make(chan struct{}, runtime.NumCPU()) // message queue as a sync method
 	deployed := sync.WaitGroup{}
@@ -59,99 +126,377 @@ func (*knativeDeployer) Deploy(cfg *config.Configuration) {
 			defer deployed.Done()
 			defer func() { <-queue }()
 
-			knativeDeploySingleFunction(
-				cfg.Functions[i],
-				cfg.Functions[i].YAMLPath,
-				knativeConfig.IsPartiallyPanic,
-				knativeConfig.EndpointPort,
-				knativeConfig.AutoscalingMetric,
-			)
+			d.deploySingleFunction(cfg.Functions[i], cfg.Functions[i].YAMLPath, knativeConfig)
 		}()
 	}
 
 	deployed.Wait()
+
+	if knativeConfig.ReuseDeployment {
+		log.Infof("Reused %d out of %d deployed functions.", d.reused.Load(), len(cfg.Functions))
+	}
 }
 
-func (*knativeDeployer) Clean() {
-	cmd := exec.Command("kn", "service", "delete", "--all")
+func (d *knativeDeployer) Clean() {
+	d.CleanRun(nil, d.runID)
+}
 
-	var out bytes.Buffer
-	cmd.Stdout = &out
+// CleanRun deletes the resources labelled with the run ID, leaving the rest of the namespace intact
+func (d *knativeDeployer) CleanRun(_ *config.Configuration, runID string) {
+	if runID == "" {
+		log.Warn("No run ID set, skipping the cleanup of Knative services.")
+		return
+	}
+	if d.client == nil {
+		client, err := newKubernetesDynamicClient()
+		if err != nil {
+			log.Errorf("Unable to clean up the Knative services of run %s: %v", runID, err)
+			return
+		}
+		d.client = client
+	}
 
-	if err := cmd.Run(); err != nil {
-		log.Errorf("Unable to delete Knative services - %s", err)
+	ctx := context.Background()
+	selector := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", runIDLabel, runID)}
+	for _, resource := range knativeCleanupResources {
+		resources := d.client.Resource(resource).Namespace(namespace)
+
+		list, err := resources.List(ctx, selector)
+		if err != nil {
+			log.Errorf("Unable to list %s of run %s - %v", resource.Resource, runID, err)
+			continue
+		}
+
+		for _, item := range list.Items {
+			err = resources.Delete(ctx, item.GetName(), metav1.DeleteOptions{})
+			if err != nil && !errors.IsNotFound(err) {
+				log.Errorf("Unable to delete %s %s - %v", resource.Resource, item.GetName(), err)
+			}
+		}
+		log.Debugf("Deleted %d %s of run %s", len(list.Items), resource.Resource, runID)
 	}
-	preDepCmd := exec.Command("kubectl", "delete", "pods", "--all")
-	preDepCmd.Stdout = &out
-	if err := preDepCmd.Run(); err != nil {
-		log.Error("Unable to clean up predeployment files")
+}
+
+func (d *knativeDeployer) deploySingleFunction(function *common.Function, yamlPath string, knativeConfig knativeDeploymentConfiguration) bool {
+	for _, path := range function.PredeploymentPath {
+		if err := d.applyPredeployment(context.Background(), path); err != nil {
+			log.Warnf("Failed to apply the predeployment resources of %s: %v", path, err)
+		}
 	}
-	preDepCmd = exec.Command("kubectl", "delete", "services", "--all")
-	preDepCmd.Stdout = &out
-	if err := preDepCmd.Run(); err != nil {
-		log.Error("Unable to clean up predeployment files")
+
+	service, err := createKnativeService(function, yamlPath, knativeConfig, d.runID)
+	if err != nil {
+		// TODO: there should be a toggle to turn off deployment because if this is fatal then we cannot test the thing locally
+		log.Warnf("Failed to deploy function %s: %v", function.Name, err)
+		return false
+	}
+
+	ctx := context.Background()
+	services := d.client.Resource(knativeServiceResource).Namespace(service.GetNamespace())
+
+	url, reused := "", false
+	if knativeConfig.ReuseDeployment {
+		url, reused = d.reuseKnativeService(ctx, services, service)
+	}
+	if reused {
+		d.reused.Add(1)
+	} else {
+		if err = applyResource(ctx, services, service); err != nil {
+			log.Warnf("Failed to deploy function %s: %v", function.Name, err)
+			return false
+		}
+
+		url, err = waitForKnativeService(ctx, services, service.GetName())
+		if err != nil {
+			log.Warnf("Failed to deploy function %s: %v", function.Name, err)
+			return false
+		}
+	}
+
+	if endpoint := strings.TrimPrefix(strings.TrimPrefix(url, "http://"), "https://"); endpoint != "" {
+		function.Endpoint = endpoint
+	} else {
+		function.Endpoint = fmt.Sprintf("%s.%s.%s", function.Name, service.GetNamespace(), bareMetalLbGateway)
+	}
+	// adding port to the endpoint
+	function.Endpoint = fmt.Sprintf("%s:%d", function.Endpoint, knativeConfig.EndpointPort)
+	log.Debugf("Deployed function on %s\n", function.Endpoint)
+
+	return true
+}
+
+// applyPredeployment creates or updates the resources of a predeployment YAML, which may contain multiple documents,
+// labelling them with the run ID so that the cleanup removes them as well
+func (d *knativeDeployer) applyPredeployment(ctx context.Context, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	decoder := utilyaml.NewYAMLOrJSONDecoder(file, 4096)
+	for {
+		resource := &unstructured.Unstructured{}
+		if err = decoder.Decode(&resource.Object); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("invalid predeployment YAML - %v", err)
+		}
+		if len(resource.Object) == 0 {
+			continue // empty document
+		}
+
+		if resource.GetNamespace() == "" {
+			resource.SetNamespace(namespace)
+		}
+		labels := resource.GetLabels()
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		labels[runIDLabel] = d.runID
+		resource.SetLabels(labels)
+
+		// the predeployment YAMLs only contain built-in kinds, whose resource names are the plural of the kind
+		gvr, _ := meta.UnsafeGuessKindToResource(resource.GroupVersionKind())
+		resources := d.client.Resource(gvr).Namespace(resource.GetNamespace())
+		if err = applyResource(ctx, resources, resource); err != nil {
+			return fmt.Errorf("failed to apply %s %s - %v", resource.GetKind(), resource.GetName(), err)
+		}
+		log.Debugf("Applied predeployment %s %s", resource.GetKind(), resource.GetName())
 	}
 }
 
-func knativeDeploySingleFunction(function *common.Function, yamlPath string, isPartiallyPanic bool, endpointPort int, autoscalingMetric string) bool {
+// reuseKnativeService returns the URL of the existing service if it is ready and has been deployed from the same
+// specification, in which case the service is labelled with the current run ID instead of being redeployed
+func (d *knativeDeployer) reuseKnativeService(ctx context.Context, services dynamic.ResourceInterface, service *unstructured.Unstructured) (string, bool) {
+	existing, err := services.Get(ctx, service.GetName(), metav1.GetOptions{})
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			log.Warnf("Unable to get the existing service %s - %v", service.GetName(), err)
+		}
+		return "", false
+	}
+
+	if existing.GetAnnotations()[knativeSpecHashAnnotation] != service.GetAnnotations()[knativeSpecHashAnnotation] {
+		log.Debugf("Reconfiguring function %s as its specification has changed.", service.GetName())
+		return "", false
+	}
+	if ready, _ := knativeServiceReady(existing); ready != metav1.ConditionTrue {
+		log.Debugf("Redeploying function %s as it is not ready.", service.GetName())
+		return "", false
+	}
+
+	// only the labels of the service change, so no new revision is created
+	labels := existing.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	previousRunID := labels[runIDLabel]
+	labels[runIDLabel] = d.runID
+	existing.SetLabels(labels)
+	if _, err = services.Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
+		log.Warnf("Unable to label the existing service %s - %v", service.GetName(), err)
+		return "", false
+	}
+
+	url, _, _ := unstructured.NestedString(existing.Object, "status", "url")
+	log.Debugf("Reusing function %s deployed by run %s.", service.GetName(), previousRunID)
+
+	return url, true
+}
+
+// createKnativeService renders the YAML template of the function with the variables of the deployment, as envsubst
+// would, sets the annotations previously passed as kn flags, and labels the service and its pods with the run ID
+func createKnativeService(function *common.Function, yamlPath string, knativeConfig knativeDeploymentConfiguration, runID string) (*unstructured.Unstructured, error) {
 	panicWindow := "\"10.0\""
 	panicThreshold := "\"200.0\""
-	if isPartiallyPanic {
+	if knativeConfig.IsPartiallyPanic {
 		panicWindow = "\"100.0\""
 		panicThreshold = "\"1000.0\""
 	}
 	autoscalingTarget := 100 // default for concurrency
-	if autoscalingMetric == "rps" {
+	if knativeConfig.AutoscalingMetric == "rps" {
 		autoscalingTarget = int(math.Round(1000.0 / function.RuntimeStats.Average))
 		// for rps mode use the average runtime in milliseconds to determine how many requests a pod can process per
 		// second, then round to an integer as that is what the knative config expects
 	}
-	for _, path := range function.PredeploymentPath {
-		envCmd := cmd.NewCmd("kubectl", "apply", "-f", path)
-		status := <-envCmd.Start()
 
-		for _, line := range status.Stdout {
-			fmt.Println("Predeployment command response is" + line)
+	variables := map[string]string{
+		"FUNC_NAME":               function.Name,
+		"CPU_REQUEST":             strconv.Itoa(function.CPURequestsMilli) + "m",
+		"CPU_LIMITS":              strconv.Itoa(function.CPULimitsMilli) + "m",
+		"MEMORY_REQUESTS":         strconv.Itoa(function.MemoryRequestsMiB) + "Mi",
+		"PANIC_WINDOW":            panicWindow,
+		"PANIC_THRESHOLD":         panicThreshold,
+		"AUTOSCALING_METRIC":      wrapString(knativeConfig.AutoscalingMetric),
+		"AUTOSCALING_TARGET":      wrapString(strconv.Itoa(autoscalingTarget)),
+		"COLD_START_BUSY_LOOP_MS": wrapString(strconv.Itoa(function.ColdStartBusyLoopMs)),
+	}
+
+	template, err := os.ReadFile(yamlPath)
+	if err != nil {
+		return nil, err
+	}
+	rendered := os.Expand(string(template), func(name string) string {
+		if value, ok := variables[name]; ok {
+			return value
 		}
+		return os.Getenv(name)
+	})
+
+	data, err := yaml.YAMLToJSON([]byte(rendered))
+	if err != nil {
+		return nil, fmt.Errorf("invalid template %s - %v", yamlPath, err)
+	}
+	service := &unstructured.Unstructured{}
+	if err = service.UnmarshalJSON(data); err != nil {
+		return nil, fmt.Errorf("invalid template %s - %v", yamlPath, err)
 	}
-	cmd := exec.Command(
-		"bash",
-		"./pkg/driver/deployment/knative.sh",
-		yamlPath,
-		function.Name,
 
-		strconv.Itoa(function.CPURequestsMilli)+"m",
-		strconv.Itoa(function.CPULimitsMilli)+"m",
-		strconv.Itoa(function.MemoryRequestsMiB)+"Mi",
-		strconv.Itoa(function.InitialScale),
-		panicWindow,
-		panicThreshold,
+	service.SetName(function.Name)
+	if service.GetNamespace() == "" {
+		service.SetNamespace(namespace)
+	}
 
-		wrapString(autoscalingMetric),
-		wrapString(strconv.Itoa(autoscalingTarget)),
+	labels := service.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	labels[runIDLabel] = runID
+	service.SetLabels(labels)
 
-		wrapString(strconv.Itoa(function.ColdStartBusyLoopMs)),
-	)
+	// equivalent of kn service apply --scale-init <InitialScale> --concurrency-target 1
+	annotations, _, _ := unstructured.NestedStringMap(service.Object, "spec", "template", "metadata", "annotations")
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations["autoscaling.knative.dev/initial-scale"] = strconv.Itoa(function.InitialScale)
+	annotations["autoscaling.knative.dev/target"] = "1"
+	setAutoscalingAnnotations(annotations, function.Autoscaling)
+	if err = unstructured.SetNestedStringMap(service.Object, annotations, "spec", "template", "metadata", "annotations"); err != nil {
+		return nil, err
+	}
+	if function.Autoscaling != nil && function.Autoscaling.ContainerConcurrency != nil {
+		concurrency := int64(*function.Autoscaling.ContainerConcurrency)
+		if err = unstructured.SetNestedField(service.Object, concurrency, "spec", "template", "spec", "containerConcurrency"); err != nil {
+			return nil, err
+		}
+	}
 
-	stdoutStderr, err := cmd.CombinedOutput()
-	log.Debug("CMD response: ", string(stdoutStderr))
+	// hashed before the run ID is added to the pod labels, as it does not change the deployed function
+	spec, err := json.Marshal(service.Object["spec"])
 	if err != nil {
-		// TODO: there should be a toggle to turn off deployment because if this is fatal then we cannot test the thing locally
-		log.Warnf("Failed to deploy function %s: %v\n%s\n", function.Name, err, stdoutStderr)
-		return false
+		return nil, err
 	}
-	if endpoint := urlRegex.FindStringSubmatch(string(stdoutStderr))[1]; endpoint != function.Endpoint {
-		// TODO: check when this situation happens
-		log.Debugf("Update function endpoint to %s\n", endpoint)
-		function.Endpoint = endpoint
-	} else {
-		function.Endpoint = fmt.Sprintf("%s.%s.%s", function.Name, namespace, bareMetalLbGateway)
+	serviceAnnotations := service.GetAnnotations()
+	if serviceAnnotations == nil {
+		serviceAnnotations = make(map[string]string)
 	}
-	// adding port to the endpoint
-	function.Endpoint = fmt.Sprintf("%s:%d", function.Endpoint, endpointPort)
-	log.Debugf("Deployed function on %s\n", function.Endpoint)
+	serviceAnnotations[knativeSpecHashAnnotation] = strconv.FormatUint(common.Hash(string(spec)), 16)
+	service.SetAnnotations(serviceAnnotations)
 
-	return true
+	podLabels, _, _ := unstructured.NestedStringMap(service.Object, "spec", "template", "metadata", "labels")
+	if podLabels == nil {
+		podLabels = make(map[string]string)
+	}
+	podLabels[runIDLabel] = runID
+	if err = unstructured.SetNestedStringMap(service.Object, podLabels, "spec", "template", "metadata", "labels"); err != nil {
+		return nil, err
+	}
+
+	return service, nil
+}
+
+// setAutoscalingAnnotations overrides the annotations of the revision with the autoscaling settings of the function
+func setAutoscalingAnnotations(annotations map[string]string, settings *common.AutoscalingSettings) {
+	if settings == nil {
+		return
+	}
+
+	setString := func(key string, value *string) {
+		if value != nil {
+			annotations["autoscaling.knative.dev/"+key] = *value
+		}
+	}
+	setFloat := func(key string, value *float64) {
+		if value != nil {
+			annotations["autoscaling.knative.dev/"+key] = strconv.FormatFloat(*value, 'f', -1, 64)
+		}
+	}
+	setInt := func(key string, value *int, unit string) {
+		if value != nil {
+			annotations["autoscaling.knative.dev/"+key] = strconv.Itoa(*value) + unit
+		}
+	}
+
+	setString("class", settings.Class)
+	setString("metric", settings.Metric)
+	setFloat("target", settings.Target)
+	setFloat("target-utilization-percentage", settings.TargetUtilizationPercentage)
+	setInt("window", settings.StableWindowSeconds, "s")
+	setInt("scale-to-zero-pod-retention-period", settings.ScaleToZeroPodRetentionSeconds, "s")
+	setInt("min-scale", settings.MinScale, "")
+	setInt("max-scale", settings.MaxScale, "")
+	setInt("initial-scale", settings.InitialScale, "")
+}
+
+// applyResource creates the resource, or updates it if it already exists
+func applyResource(ctx context.Context, resources dynamic.ResourceInterface, resource *unstructured.Unstructured) error {
+	existing, err := resources.Get(ctx, resource.GetName(), metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		_, err = resources.Create(ctx, resource, metav1.CreateOptions{})
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	resource.SetResourceVersion(existing.GetResourceVersion())
+	_, err = resources.Update(ctx, resource, metav1.UpdateOptions{})
+
+	return err
+}
+
+// waitForKnativeService waits until the Ready condition of the service is true and returns its URL
+func waitForKnativeService(ctx context.Context, services dynamic.ResourceInterface, name string) (string, error) {
+	var url string
+	err := wait.PollUntilContextTimeout(ctx, knativeReadyPollInterval, knativeReadyTimeout, true, func(ctx context.Context) (bool, error) {
+		service, err := services.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+
+		ready, message := knativeServiceReady(service)
+		if ready == metav1.ConditionFalse {
+			return false, fmt.Errorf("service is not ready - %s", message)
+		}
+
+		url, _, _ = unstructured.NestedString(service.Object, "status", "url")
+		return ready == metav1.ConditionTrue, nil
+	})
+
+	return url, err
+}
+
+// knativeServiceReady returns the status of the Ready condition of the latest generation of the service
+func knativeServiceReady(service *unstructured.Unstructured) (metav1.ConditionStatus, string) {
+	observedGeneration, _, _ := unstructured.NestedInt64(service.Object, "status", "observedGeneration")
+	if observedGeneration < service.GetGeneration() {
+		return metav1.ConditionUnknown, ""
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(service.Object, "status", "conditions")
+	for _, condition := range conditions {
+		fields, ok := condition.(map[string]interface{})
+		if !ok || fields["type"] != "Ready" {
+			continue
+		}
+
+		status, _ := fields["status"].(string)
+		message, _ := fields["message"].(string)
+		return metav1.ConditionStatus(status), message
+	}
+
+	return metav1.ConditionUnknown, ""
 }
 
 func wrapString(value string) string {
@@ -0,0 +1,291 @@
+package deployment
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+const testKnativeTemplate = "../../../workloads/container/trace_func_go.yaml"
+
+// createFakeKnativeClient returns a client whose services report the Ready condition with the given status upon
+// creation or update
+func createFakeKnativeClient(readyStatus string) *fake.FakeDynamicClient {
+	listKinds := make(map[schema.GroupVersionResource]string)
+	for _, resource := range knativeCleanupResources {
+		listKinds[resource] = "List"
+	}
+	client := fake.NewSimpleDynamicClientWithCustomListKinds(k8sruntime.NewScheme(), listKinds)
+
+	setReady := func(action k8stesting.Action) (bool, k8sruntime.Object, error) {
+		var service *unstructured.Unstructured
+		switch action := action.(type) {
+		case k8stesting.CreateAction:
+			service = action.GetObject().(*unstructured.Unstructured)
+		case k8stesting.UpdateAction:
+			service = action.GetObject().(*unstructured.Unstructured)
+		}
+
+		_ = unstructured.SetNestedField(service.Object, "http://"+service.GetName()+".default.example.com", "status", "url")
+		_ = unstructured.SetNestedSlice(service.Object, []interface{}{
+			map[string]interface{}{"type": "Ready", "status": readyStatus, "message": "test"},
+		}, "status", "conditions")
+
+		return false, nil, nil
+	}
+	client.PrependReactor("create", "services", setReady)
+	client.PrependReactor("update", "services", setReady)
+
+	return client
+}
+
+func TestKnativeDeploySingleFunction(t *testing.T) {
+	client := createFakeKnativeClient("True")
+	deployer := &knativeDeployer{client: client, runID: "run-a"}
+	function := &common.Function{
+		Name:              "trace-func-0",
+		CPURequestsMilli:  100,
+		CPULimitsMilli:    1000,
+		MemoryRequestsMiB: 128,
+		InitialScale:      2,
+	}
+	knativeConfig := knativeDeploymentConfiguration{EndpointPort: 80, AutoscalingMetric: "concurrency"}
+
+	// the second deployment updates the existing service
+	for i := 0; i < 2; i++ {
+		if !deployer.deploySingleFunction(function, testKnativeTemplate, knativeConfig) {
+			t.Fatal("Failed to deploy the function.")
+		}
+		if function.Endpoint != "trace-func-0.default.example.com:80" {
+			t.Errorf("Unexpected endpoint %s.", function.Endpoint)
+		}
+		function.Endpoint = ""
+	}
+
+	service, err := client.Resource(knativeServiceResource).Namespace("default").Get(context.Background(), "trace-func-0", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	annotations, _, _ := unstructured.NestedStringMap(service.Object, "spec", "template", "metadata", "annotations")
+	expectedAnnotations := map[string]string{
+		"autoscaling.knative.dev/initial-scale":              "2",
+		"autoscaling.knative.dev/target":                     "1",
+		"autoscaling.knative.dev/metric":                     "concurrency",
+		"autoscaling.knative.dev/panic-window-percentage":    "10.0",
+		"autoscaling.knative.dev/panic-threshold-percentage": "200.0",
+	}
+	for key, value := range expectedAnnotations {
+		if annotations[key] != value {
+			t.Errorf("Expected annotation %s = %s, got %s.", key, value, annotations[key])
+		}
+	}
+
+	containers, _, _ := unstructured.NestedSlice(service.Object, "spec", "template", "spec", "containers")
+	memory, _, _ := unstructured.NestedString(containers[0].(map[string]interface{}), "resources", "requests", "memory")
+	if len(containers) != 1 || memory != "128Mi" {
+		t.Errorf("Unexpected containers %v.", containers)
+	}
+}
+
+func TestKnativeAutoscalingSettings(t *testing.T) {
+	metric, target, window, maxScale, initialScale, concurrency := "rps", 12.5, 30, 10, 0, 4
+	function := &common.Function{
+		Name:         "trace-func-0",
+		InitialScale: 2,
+		Autoscaling: &common.AutoscalingSettings{
+			Metric:               &metric,
+			Target:               &target,
+			StableWindowSeconds:  &window,
+			MaxScale:             &maxScale,
+			InitialScale:         &initialScale,
+			ContainerConcurrency: &concurrency,
+		},
+	}
+
+	service, err := createKnativeService(function, testKnativeTemplate, knativeDeploymentConfiguration{AutoscalingMetric: "concurrency"}, "run-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	annotations, _, _ := unstructured.NestedStringMap(service.Object, "spec", "template", "metadata", "annotations")
+	expectedAnnotations := map[string]string{
+		"autoscaling.knative.dev/metric":        "rps",
+		"autoscaling.knative.dev/target":        "12.5",
+		"autoscaling.knative.dev/window":        "30s",
+		"autoscaling.knative.dev/max-scale":     "10",
+		"autoscaling.knative.dev/min-scale":     "0",
+		"autoscaling.knative.dev/initial-scale": "0",
+	}
+	for key, value := range expectedAnnotations {
+		if annotations[key] != value {
+			t.Errorf("Expected annotation %s = %s, got %s.", key, value, annotations[key])
+		}
+	}
+
+	containerConcurrency, _, _ := unstructured.NestedInt64(service.Object, "spec", "template", "spec", "containerConcurrency")
+	if containerConcurrency != 4 {
+		t.Errorf("Expected container concurrency 4, got %d.", containerConcurrency)
+	}
+}
+
+func TestKnativeDeployNotReady(t *testing.T) {
+	deployer := &knativeDeployer{client: createFakeKnativeClient("False")}
+	function := &common.Function{Name: "trace-func-0"}
+
+	if deployer.deploySingleFunction(function, testKnativeTemplate, knativeDeploymentConfiguration{EndpointPort: 80}) {
+		t.Error("Expected the deployment of a service that is not ready to fail.")
+	}
+	if function.Endpoint != "" {
+		t.Errorf("Expected no endpoint, got %s.", function.Endpoint)
+	}
+}
+
+func TestKnativePredeployment(t *testing.T) {
+	client := createFakeKnativeClient("True")
+	predeployment := filepath.Join(t.TempDir(), "database.yaml")
+	err := os.WriteFile(predeployment, []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: database
+spec:
+  replicas: 1
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: database
+  labels:
+    app: database
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	function := &common.Function{Name: "trace-func-0", PredeploymentPath: []string{predeployment}}
+	// applying the predeployment again updates the existing resources
+	for i := 0; i < 2; i++ {
+		if !(&knativeDeployer{client: client, runID: "run-a"}).deploySingleFunction(function, testKnativeTemplate, knativeDeploymentConfiguration{EndpointPort: 80}) {
+			t.Fatal("Failed to deploy the function.")
+		}
+	}
+
+	deployments := client.Resource(schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}).Namespace("default")
+	deployment, err := deployments.Get(context.Background(), "database", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deployment.GetLabels()[runIDLabel] != "run-a" {
+		t.Errorf("Expected the deployment to be labelled with the run ID, got %v.", deployment.GetLabels())
+	}
+
+	services := client.Resource(schema.GroupVersionResource{Version: "v1", Resource: "services"}).Namespace("default")
+	service, err := services.Get(context.Background(), "database", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if service.GetLabels()[runIDLabel] != "run-a" || service.GetLabels()["app"] != "database" {
+		t.Errorf("Expected the service to keep its labels and be labelled with the run ID, got %v.", service.GetLabels())
+	}
+
+	(&knativeDeployer{client: client, runID: "run-a"}).Clean()
+
+	if _, err = deployments.Get(context.Background(), "database", metav1.GetOptions{}); err == nil {
+		t.Error("Expected the cleanup to remove the predeployment resources.")
+	}
+}
+
+func TestKnativeReuseDeployment(t *testing.T) {
+	client := createFakeKnativeClient("True")
+	knativeConfig := knativeDeploymentConfiguration{EndpointPort: 80, ReuseDeployment: true}
+	services := client.Resource(knativeServiceResource).Namespace("default")
+
+	deploy := func(runID string, function *common.Function) *unstructured.Unstructured {
+		if !(&knativeDeployer{client: client, runID: runID}).deploySingleFunction(function, testKnativeTemplate, knativeConfig) {
+			t.Fatal("Failed to deploy the function.")
+		}
+		if function.Endpoint != function.Name+".default.example.com:80" {
+			t.Errorf("Unexpected endpoint %s.", function.Endpoint)
+		}
+
+		service, err := services.Get(context.Background(), function.Name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return service
+	}
+	podRunID := func(service *unstructured.Unstructured) string {
+		podLabels, _, _ := unstructured.NestedStringMap(service.Object, "spec", "template", "metadata", "labels")
+		return podLabels[runIDLabel]
+	}
+
+	deploy("run-a", &common.Function{Name: "trace-func-0", MemoryRequestsMiB: 128})
+
+	// the unchanged function is only relabelled, so that the cleanup of the new run removes it
+	service := deploy("run-b", &common.Function{Name: "trace-func-0", MemoryRequestsMiB: 128})
+	if service.GetLabels()[runIDLabel] != "run-b" || podRunID(service) != "run-a" {
+		t.Errorf("Expected the service of run-a to be reused, got labels %v.", service.GetLabels())
+	}
+
+	// the changed function is reconfigured
+	service = deploy("run-c", &common.Function{Name: "trace-func-0", MemoryRequestsMiB: 256})
+	if service.GetLabels()[runIDLabel] != "run-c" || podRunID(service) != "run-c" {
+		t.Errorf("Expected the service to be reconfigured, got labels %v.", service.GetLabels())
+	}
+
+	// the missing function is deployed
+	deploy("run-c", &common.Function{Name: "trace-func-1", MemoryRequestsMiB: 128})
+
+	list, err := services.List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Items) != 2 {
+		t.Errorf("Expected 2 services, got %d.", len(list.Items))
+	}
+}
+
+func TestKnativeCleanRun(t *testing.T) {
+	client := createFakeKnativeClient("True")
+	knativeConfig := knativeDeploymentConfiguration{EndpointPort: 80}
+
+	// functions of two runs and a pod not created by the loader
+	for i, runID := range []string{"run-a", "run-a", "run-b"} {
+		deployer := &knativeDeployer{client: client, runID: runID}
+		if !deployer.deploySingleFunction(&common.Function{Name: fmt.Sprintf("trace-func-%d", i)}, testKnativeTemplate, knativeConfig) {
+			t.Fatal("Failed to deploy the function.")
+		}
+	}
+	pod := &unstructured.Unstructured{}
+	pod.SetAPIVersion("v1")
+	pod.SetKind("Pod")
+	pod.SetName("unrelated")
+	pods := client.Resource(schema.GroupVersionResource{Version: "v1", Resource: "pods"}).Namespace("default")
+	if _, err := pods.Create(context.Background(), pod, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	(&knativeDeployer{client: client, runID: "run-a"}).Clean()
+
+	services, err := client.Resource(knativeServiceResource).Namespace("default").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(services.Items) != 1 || services.Items[0].GetName() != "trace-func-2" {
+		t.Errorf("Expected only the service of the other run to remain, got %d services.", len(services.Items))
+	}
+
+	if _, err = pods.Get(context.Background(), "unrelated", metav1.GetOptions{}); err != nil {
+		t.Errorf("Expected the unrelated pod to remain - %v", err)
+	}
+}
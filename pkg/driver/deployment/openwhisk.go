@@ -1,18 +1,23 @@
 package deployment
 
 import (
-	"bytes"
-	"fmt"
-	"github.com/vhive-serverless/loader/pkg/config"
-	"os/exec"
-	"strings"
+	"os"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	"github.com/vhive-serverless/loader/pkg/driver/clients"
 )
 
+// openWhiskActionLocation is the source code of the action deployed for every function
+const openWhiskActionLocation = "./pkg/workload/openwhisk/workload_openwhisk.go"
+
 type openWhiskDeployer struct {
-	functions []*common.Function
+	// created from the properties of the wsk CLI on the first deployment if nil
+	client     *clients.OpenWhiskClient
+	functions  []*common.Function
+	runID      string
+	runRecords string
 }
 
 func newOpenWhiskDeployer() *openWhiskDeployer {
@@ -20,45 +25,59 @@ func newOpenWhiskDeployer() *openWhiskDeployer {
 }
 
 func (owd *openWhiskDeployer) Deploy(cfg *config.Configuration) {
+	if owd.client == nil {
+		owd.client = clients.NewOpenWhiskClient()
+	}
 	owd.functions = cfg.Functions
+	owd.runID = cfg.RunID
+	owd.runRecords = runRecordDirectory(cfg.LoaderConfiguration)
 
-	cmd := exec.Command("wsk", "-i", "property", "get", "--apihost")
-
-	var out bytes.Buffer
-	cmd.Stdout = &out
+	var names []string
+	for _, function := range owd.functions {
+		names = append(names, function.Name)
+	}
+	writeRunRecord(owd.runRecords, runRecord{RunID: owd.runID, Platform: common.PlatformOpenWhisk, Functions: names})
 
-	err := cmd.Run()
+	code, err := os.ReadFile(openWhiskActionLocation)
 	if err != nil {
-		log.Fatalf("Unable to read OpenWhisk API host data - %s", err)
+		log.Fatalf("Unable to read the OpenWhisk action - %v", err)
 	}
-	result := strings.Split(out.String(), "\t")
-	endpoint := strings.TrimSpace(result[len(result)-1])
-
-	const actionLocation = "./pkg/workload/openwhisk/workload_openwhisk.go"
 
 	for i := 0; i < len(owd.functions); i++ {
-		cmd = exec.Command("wsk", "-i", "action", "create", owd.functions[i].Name, actionLocation, "--kind", "go:1.17", "--web", "true")
-
-		err = cmd.Run()
+		err = owd.client.CreateAction(owd.functions[i].Name, "go:1.17", string(code))
 		if err != nil {
 			log.Fatalf("Unable to create OpenWhisk action for function %s - %s", owd.functions[i].Name, err)
 		}
 
-		owd.functions[i].Endpoint = fmt.Sprintf("https://%s/api/v1/web/guest/default/%s", endpoint, owd.functions[i].Name)
+		owd.functions[i].Endpoint = owd.client.ActionURL(owd.functions[i].Name)
 	}
 }
 
 func (owd *openWhiskDeployer) Clean() {
-	for i := 0; i < len(owd.functions); i++ {
-		// TODO: check if there is a command such as "... delete --all"
-		cmd := exec.Command("wsk", "-i", "action", "delete", owd.functions[i].Name)
+	var names []string
+	for _, function := range owd.functions {
+		names = append(names, function.Name)
+	}
 
-		var out bytes.Buffer
-		cmd.Stdout = &out
+	deleteOpenWhiskActions(owd.client, names)
+	removeRunRecord(owd.runRecords, owd.runID)
+}
 
-		err := cmd.Run()
-		if err != nil {
-			log.Debugf("Unable to delete OpenWhisk action for function %s - %s", owd.functions[i].Name, err)
+// CleanRun deletes the actions recorded for the run
+func (owd *openWhiskDeployer) CleanRun(cfg *config.Configuration, runID string) {
+	if owd.client == nil {
+		owd.client = clients.NewOpenWhiskClient()
+	}
+
+	directory := runRecordDirectory(cfg.LoaderConfiguration)
+	deleteOpenWhiskActions(owd.client, readRunRecord(directory, runID, common.PlatformOpenWhisk).Functions)
+	removeRunRecord(directory, runID)
+}
+
+func deleteOpenWhiskActions(client *clients.OpenWhiskClient, names []string) {
+	for _, name := range names {
+		if err := client.DeleteAction(name); err != nil {
+			log.Debugf("Unable to delete OpenWhisk action for function %s - %s", name, err)
 		}
 	}
 }
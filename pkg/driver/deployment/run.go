@@ -0,0 +1,84 @@
+package deployment
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+// runIDLabel is set on the Kubernetes resources created by the deployers to the ID of the run
+const runIDLabel = "loader.vhive.io/run-id"
+
+// NewRunID returns a new identifier of a run, which scopes the cleanup to the resources the run has created
+func NewRunID() string {
+	return fmt.Sprintf("%s-%s", time.Now().Format("20060102-150405"), uuid.NewString()[:8])
+}
+
+// runRecord lists the functions deployed by a run on a platform whose resources cannot be labelled, so that they can
+// be removed even if the run crashes
+type runRecord struct {
+	RunID     string   `json:"RunID"`
+	Platform  string   `json:"Platform"`
+	Functions []string `json:"Functions"`
+	// workflows registered on top of the functions (Dirigent only)
+	Workflows []string `json:"Workflows,omitempty"`
+}
+
+// runRecordDirectory returns the directory holding the records of the runs on the platforms without labels, which is
+// next to the outputs of the experiment unless configured
+func runRecordDirectory(cfg *config.LoaderConfiguration) string {
+	if cfg.RunRecordDirectory != "" {
+		return cfg.RunRecordDirectory
+	}
+
+	return filepath.Join(filepath.Dir(cfg.OutputPathPrefix), "runs")
+}
+
+func runRecordPath(directory string, runID string) string {
+	return filepath.Join(directory, runID+".json")
+}
+
+// writeRunRecord records the functions before they are deployed
+func writeRunRecord(directory string, record runRecord) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		log.Fatalf("Failed to marshal the record of run %s: %v", record.RunID, err)
+	}
+
+	if err = os.MkdirAll(directory, 0755); err != nil {
+		log.Fatalf("Failed to create the directory of run records: %v", err)
+	}
+	if err = os.WriteFile(runRecordPath(directory, record.RunID), data, 0644); err != nil {
+		log.Fatalf("Failed to write the record of run %s: %v", record.RunID, err)
+	}
+}
+
+// readRunRecord returns the record of the resources deployed by the run on the platform
+func readRunRecord(directory string, runID string, platform string) runRecord {
+	data, err := os.ReadFile(runRecordPath(directory, runID))
+	if err != nil {
+		log.Fatalf("Failed to read the record of run %s: %v", runID, err)
+	}
+
+	var record runRecord
+	if err = json.Unmarshal(data, &record); err != nil {
+		log.Fatalf("Failed to unmarshal the record of run %s: %v", runID, err)
+	}
+	if record.Platform != platform {
+		log.Fatalf("Run %s was deployed on %s, not on %s.", runID, record.Platform, platform)
+	}
+
+	return record
+}
+
+func removeRunRecord(directory string, runID string) {
+	if err := os.Remove(runRecordPath(directory, runID)); err != nil && !os.IsNotExist(err) {
+		log.Warnf("Failed to remove the record of run %s: %v", runID, err)
+	}
+}
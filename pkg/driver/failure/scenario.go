@@ -0,0 +1,260 @@
+package failure
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gocarina/gocsv"
+	"github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+// TimelineRecord is an injected event, timed as the start times of the invocation records
+type TimelineRecord struct {
+	Time           int64 `csv:"time"`           // Unix time in microseconds
+	ExperimentTime int64 `csv:"experimentTime"` // Microseconds since the beginning of the experiment
+	// index of the event in the scenario and of its repetition
+	Event      int    `csv:"event"`
+	Occurrence int    `csv:"occurrence"`
+	Action     string `csv:"action"`
+	Component  string `csv:"component"`
+	// empty if the command ran on the loader node
+	Node    string `csv:"node"`
+	Command string `csv:"command"`
+	Success bool   `csv:"success"`
+}
+
+// scheduledEvent is an event of the scenario with its resolved command
+type scheduledEvent struct {
+	config.FailureEvent
+	index   int
+	command []string
+	remote  bool
+	// whether the component comes back by itself after the failure, e.g., pods recreated by Kubernetes
+	selfRecovering bool
+}
+
+// failedComponent is a component left down by a failure event, on a node or on the loader node if empty
+type failedComponent struct {
+	component string
+	node      string
+}
+
+// Scheduler injects the events of the failure scenario at their time since the beginning of the experiment and records
+// them in a timeline
+type Scheduler struct {
+	events []scheduledEvent
+
+	start time.Time
+	stop  chan struct{}
+	wg    sync.WaitGroup
+
+	mutex    sync.Mutex
+	timeline []TimelineRecord
+	failed   map[failedComponent]bool
+}
+
+// NewScheduler validates the scenario of the configuration, which is empty if failures are disabled
+func NewScheduler(platform string, cfg *config.FailureConfiguration) *Scheduler {
+	s := &Scheduler{stop: make(chan struct{}), failed: make(map[failedComponent]bool)}
+	if cfg == nil || !cfg.FailureEnabled {
+		return s
+	}
+
+	events := cfg.Events
+	if len(events) == 0 {
+		events = legacyFailureEvent(cfg)
+	}
+
+	for i, event := range events {
+		if event.Action == "" {
+			event.Action = config.FailureActionFail
+		}
+
+		command, remote, err := validateEvent(platform, &event)
+		if err != nil {
+			logrus.Fatalf("Invalid failure event %d - %v", i, err)
+		}
+
+		s.events = append(s.events, scheduledEvent{
+			FailureEvent:   event,
+			index:          i,
+			command:        command,
+			remote:         remote,
+			selfRecovering: selfRecovering(platform, &event),
+		})
+	}
+
+	return s
+}
+
+func validateEvent(platform string, event *config.FailureEvent) ([]string, bool, error) {
+	if event.Action != config.FailureActionFail && event.Action != config.FailureActionRecover && event.Action != config.FailureActionRestart {
+		return nil, false, fmt.Errorf("unknown action '%s'", event.Action)
+	}
+	if event.At < 0 {
+		return nil, false, fmt.Errorf("the time must be non-negative")
+	}
+	if event.RepeatCount < 0 || (event.RepeatCount > 0 && event.RepeatEverySeconds <= 0) {
+		return nil, false, fmt.Errorf("a repeated event needs a positive period")
+	}
+
+	return eventCommand(platform, event)
+}
+
+// Enabled returns whether the scenario has any event
+func (s *Scheduler) Enabled() bool {
+	return len(s.events) > 0
+}
+
+// Start schedules the events relative to now, which is the beginning of the experiment
+func (s *Scheduler) Start() {
+	s.start = time.Now()
+
+	for i := range s.events {
+		s.wg.Add(1)
+		go s.schedule(&s.events[i])
+	}
+}
+
+// Stop cancels the events that have not been injected yet, waits for the running ones, recovers the components still
+// failed and returns the timeline
+func (s *Scheduler) Stop() []TimelineRecord {
+	close(s.stop)
+	s.wg.Wait()
+
+	s.recoverFailed()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	sort.SliceStable(s.timeline, func(i, j int) bool {
+		return s.timeline[i].Time < s.timeline[j].Time
+	})
+	return s.timeline
+}
+
+func (s *Scheduler) schedule(event *scheduledEvent) {
+	defer s.wg.Done()
+
+	for occurrence := 0; occurrence <= event.RepeatCount; occurrence++ {
+		at := time.Duration(event.At+occurrence*event.RepeatEverySeconds) * time.Second
+
+		timer := time.NewTimer(time.Until(s.start.Add(at)))
+		select {
+		case <-s.stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		s.inject(event, occurrence, event.nodes())
+	}
+}
+
+// recoverFailed injects the recovery events of the components left down by the cancelled recoveries, in the timeline
+// with occurrence -1, and reports the components without any recovery event
+func (s *Scheduler) recoverFailed() {
+	for i := range s.events {
+		event := &s.events[i]
+		if event.Action != config.FailureActionRecover {
+			continue
+		}
+
+		var nodes []string
+		s.mutex.Lock()
+		for _, node := range event.nodes() {
+			if s.failed[failedComponent{component: event.Component, node: node}] {
+				nodes = append(nodes, node)
+			}
+		}
+		s.mutex.Unlock()
+
+		if len(nodes) > 0 {
+			logrus.Warnf("Recovering %s left failed at the end of the experiment", event.Component)
+			s.inject(event, -1, nodes)
+		}
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for failed := range s.failed {
+		node := failed.node
+		if node == "" {
+			node = "the loader node"
+		}
+		logrus.Errorf("Component %s left failed on %s, as it has no recovery event.", failed.component, node)
+	}
+}
+
+// nodes returns the nodes the command of the event runs on, where the empty node is the loader node
+func (e *scheduledEvent) nodes() []string {
+	if !e.remote || len(e.Nodes) == 0 {
+		return []string{""}
+	}
+
+	return e.Nodes
+}
+
+// inject runs the command of the event, in parallel on each of the nodes
+func (s *Scheduler) inject(event *scheduledEvent, occurrence int, nodes []string) {
+	logrus.Infof("Injecting failure event %d (%s %s)", event.index, event.Action, event.Component)
+
+	wg := sync.WaitGroup{}
+	for _, node := range nodes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			now := time.Now()
+			record := TimelineRecord{
+				Time:           now.UnixMicro(),
+				ExperimentTime: now.Sub(s.start).Microseconds(),
+				Event:          event.index,
+				Occurrence:     occurrence,
+				Action:         event.Action,
+				Component:      event.Component,
+				Node:           node,
+				Command:        strings.Join(event.command, " "),
+			}
+
+			var err error
+			if node == "" {
+				err = invokeLocally(event.command)
+			} else {
+				err = invokeRemotely(event.command, node)
+			}
+			record.Success = err == nil
+
+			s.mutex.Lock()
+			s.timeline = append(s.timeline, record)
+			if record.Success {
+				key := failedComponent{component: event.Component, node: node}
+				if event.Action == config.FailureActionFail && !event.selfRecovering {
+					s.failed[key] = true
+				} else if event.Action != config.FailureActionFail {
+					delete(s.failed, key)
+				}
+			}
+			s.mutex.Unlock()
+		}()
+	}
+	wg.Wait()
+}
+
+func WriteTimeline(filename string, records []TimelineRecord) {
+	file, err := os.Create(filename)
+	common.Check(err)
+	defer file.Close()
+
+	if err := gocsv.MarshalCSV(records, gocsv.NewSafeCSVWriter(csv.NewWriter(file))); err != nil {
+		logrus.Errorf("Failed to write the failure timeline - %v", err)
+	}
+}
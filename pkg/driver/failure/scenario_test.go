@@ -0,0 +1,210 @@
+package failure
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gocarina/gocsv"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+// recordCommands replaces the execution of commands for the duration of the test
+func recordCommands(t *testing.T) func() []string {
+	var mutex sync.Mutex
+	var commands []string
+
+	original := runCommand
+	runCommand = func(command []string) ([]byte, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		commands = append(commands, strings.Join(command, " "))
+		if command[len(command)-1] == "false" {
+			return nil, errors.New("exit status 1")
+		}
+		return nil, nil
+	}
+	t.Cleanup(func() { runCommand = original })
+
+	return func() []string {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		return append([]string{}, commands...)
+	}
+}
+
+func TestFailureScenario(t *testing.T) {
+	commands := recordCommands(t)
+
+	scheduler := NewScheduler(common.PlatformDirigent, &config.FailureConfiguration{
+		FailureEnabled: true,
+		Events: []config.FailureEvent{
+			{At: 0, Component: WorkerNodeFailure, Nodes: []string{"node-1", "node-2"}},
+			{At: 0, Action: config.FailureActionRecover, Component: ControlPlaneFailure, RepeatEverySeconds: 1, RepeatCount: 1},
+			{At: 0, Command: []string{"false"}},
+			// cancelled at the end of the experiment
+			{At: 3600, Component: DataPlaneFailure},
+		},
+	})
+	if !scheduler.Enabled() {
+		t.Fatal("Expected the scenario to be enabled.")
+	}
+
+	scheduler.Start()
+	time.Sleep(1500 * time.Millisecond)
+	timeline := scheduler.Stop()
+
+	if len(timeline) != 5 {
+		t.Fatalf("Expected 5 injected events, got %d: %+v.", len(timeline), timeline)
+	}
+	for i := 1; i < len(timeline); i++ {
+		if timeline[i].Time < timeline[i-1].Time {
+			t.Error("Expected the timeline to be sorted by time.")
+		}
+	}
+
+	nodes := map[string]bool{}
+	var repeated []TimelineRecord
+	for _, record := range timeline {
+		switch record.Event {
+		case 0:
+			nodes[record.Node] = true
+			if record.Command != "sudo systemctl stop worker_node" || !record.Success {
+				t.Errorf("Unexpected worker node failure %+v.", record)
+			}
+		case 1:
+			repeated = append(repeated, record)
+		case 2:
+			if record.Success || record.Node != "" {
+				t.Errorf("Expected the failed custom command to run locally and be recorded as failed, got %+v.", record)
+			}
+		default:
+			t.Errorf("Unexpected event %+v.", record)
+		}
+	}
+	if !nodes["node-1"] || !nodes["node-2"] {
+		t.Errorf("Expected the failure to be injected on each node, got %v.", nodes)
+	}
+	if len(repeated) != 2 || repeated[1].Occurrence != 1 || repeated[1].ExperimentTime < 1e6 ||
+		repeated[0].Action != config.FailureActionRecover || repeated[0].Command != "sudo systemctl start control_plane" {
+
+		t.Errorf("Unexpected repeated recovery %+v.", repeated)
+	}
+	if count := len(commands()); count != 5 {
+		t.Errorf("Expected 5 commands to run, got %d.", count)
+	}
+
+	filename := filepath.Join(t.TempDir(), "failures.csv")
+	WriteTimeline(filename, timeline)
+
+	file, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var written []TimelineRecord
+	if err = gocsv.UnmarshalFile(file, &written); err != nil || len(written) != len(timeline) {
+		t.Errorf("Expected the timeline to be written, got %v - %v.", written, err)
+	}
+}
+
+func TestFailureRecoveredOnStop(t *testing.T) {
+	commands := recordCommands(t)
+
+	scheduler := NewScheduler(common.PlatformDirigent, &config.FailureConfiguration{
+		FailureEnabled: true,
+		Events: []config.FailureEvent{
+			{At: 0, Component: WorkerNodeFailure, Nodes: []string{"node-1", "node-2"}},
+			{At: 0, Component: DataPlaneFailure},
+			{At: 1, Action: config.FailureActionRestart, Component: DataPlaneFailure},
+			// cancelled at the end of the experiment, but run for the nodes still failed
+			{At: 3600, Action: config.FailureActionRecover, Component: WorkerNodeFailure, Nodes: []string{"node-2", "node-3"}},
+			{At: 3600, Action: config.FailureActionRecover, Component: DataPlaneFailure},
+		},
+	})
+
+	scheduler.Start()
+	time.Sleep(1200 * time.Millisecond)
+	timeline := scheduler.Stop()
+
+	var recovered []TimelineRecord
+	for _, record := range timeline {
+		if record.Occurrence == -1 {
+			recovered = append(recovered, record)
+		}
+	}
+	if len(recovered) != 1 || recovered[0].Event != 3 || recovered[0].Node != "node-2" ||
+		recovered[0].Command != "sudo systemctl start worker_node" {
+
+		t.Errorf("Expected only the worker node to be recovered on node-2, got %+v.", recovered)
+	}
+	if count := len(commands()); count != 5 {
+		t.Errorf("Expected 5 commands to run, got %d.", count)
+	}
+
+	// node-1 has no recovery event
+	if len(scheduler.failed) != 1 || !scheduler.failed[failedComponent{component: WorkerNodeFailure, node: "node-1"}] {
+		t.Errorf("Expected only the worker node on node-1 to be left failed, got %v.", scheduler.failed)
+	}
+}
+
+func TestLegacyFailure(t *testing.T) {
+	commands := recordCommands(t)
+
+	// the Knative control plane is failed through kubectl from the loader node
+	scheduler := NewScheduler(common.PlatformKnative, &config.FailureConfiguration{
+		FailureEnabled: true,
+		FailAt:         1,
+		FailComponent:  ControlPlaneFailure,
+		FailNode:       "node-1 node-2",
+	})
+	scheduler.Start()
+	time.Sleep(1200 * time.Millisecond)
+	timeline := scheduler.Stop()
+
+	if len(timeline) != 1 || timeline[0].Node != "" || timeline[0].ExperimentTime < 1e6 || timeline[0].Action != config.FailureActionRestart {
+		t.Errorf("Unexpected timeline %+v.", timeline)
+	}
+	if executed := commands(); len(executed) != 1 || executed[0] != "bash ./pkg/driver/failure/knative_delete_control_plane.sh" {
+		t.Errorf("Unexpected commands %v.", executed)
+	}
+
+	if NewScheduler(common.PlatformKnative, &config.FailureConfiguration{FailAt: 1, FailComponent: ControlPlaneFailure}).Enabled() {
+		t.Error("Expected no events if failures are disabled.")
+	}
+}
+
+func TestValidateFailureEvent(t *testing.T) {
+	tests := []struct {
+		name     string
+		platform string
+		event    config.FailureEvent
+		valid    bool
+	}{
+		{"worker node", common.PlatformKnative, config.FailureEvent{Action: config.FailureActionFail, Component: WorkerNodeFailure}, true},
+		{"worker node restart", common.PlatformDirigent, config.FailureEvent{Action: config.FailureActionRestart, Component: WorkerNodeFailure}, true},
+		{"knative plane recovery", common.PlatformKnative, config.FailureEvent{Action: config.FailureActionRecover, Component: DataPlaneFailure}, false},
+		{"custom recovery", common.PlatformKnative, config.FailureEvent{Action: config.FailureActionRecover, Command: []string{"true"}}, true},
+		{"unknown component", common.PlatformDirigent, config.FailureEvent{Action: config.FailureActionFail, Component: "scheduler"}, false},
+		{"unknown action", common.PlatformDirigent, config.FailureEvent{Action: "pause", Component: WorkerNodeFailure}, false},
+		{"unsupported platform", common.PlatformOpenWhisk, config.FailureEvent{Action: config.FailureActionFail, Component: WorkerNodeFailure}, false},
+		{"custom command", common.PlatformOpenWhisk, config.FailureEvent{Action: config.FailureActionFail, Command: []string{"true"}}, true},
+		{"repeat without period", common.PlatformDirigent, config.FailureEvent{Action: config.FailureActionFail, Component: WorkerNodeFailure, RepeatCount: 2}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, _, err := validateEvent(test.platform, &test.event); (err == nil) != test.valid {
+				t.Errorf("Expected valid=%v, got error %v.", test.valid, err)
+			}
+		})
+	}
+}
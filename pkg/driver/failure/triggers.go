@@ -1,11 +1,10 @@
 package failure
 
 import (
+	"fmt"
 	"github.com/vhive-serverless/loader/pkg/common"
 	"os/exec"
 	"strings"
-	"sync"
-	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/vhive-serverless/loader/pkg/config"
@@ -19,86 +18,112 @@ const (
 	WorkerNodeFailure   = "worker_node"
 )
 
-func ScheduleFailure(platform string, config *config.FailureConfiguration) {
-	if config != nil && config.FailureEnabled && config.FailAt != 0 && config.FailComponent != "" {
-		time.Sleep(time.Duration(config.FailAt) * time.Second)
-
-		switch platform {
-		case common.PlatformKnative:
-			triggerKnativeFailure(config.FailNode, config.FailComponent)
-		case common.PlatformDirigent:
-			triggerDirigentFailure(config.FailNode, config.FailComponent)
-		default:
-			logrus.Errorf("No specified failure handler for given type of system.")
-		}
-	}
+// runCommand runs the command on the loader node and returns its combined output
+var runCommand = func(command []string) ([]byte, error) {
+	return exec.Command(command[0], command[1:]...).CombinedOutput()
 }
 
-func invokeRemotely(command []string, nodes string) {
-	splitNodes := strings.Split(nodes, NodeSeparator)
-	wg := &sync.WaitGroup{}
+// legacyFailureEvent converts the single failure of the configuration to an event, which restarts the component as it
+// has no recovery
+func legacyFailureEvent(cfg *config.FailureConfiguration) []config.FailureEvent {
+	if cfg.FailAt == 0 || cfg.FailComponent == "" {
+		return nil
+	}
 
-	for _, node := range splitNodes {
-		wg.Add(1)
+	return []config.FailureEvent{{
+		At:        cfg.FailAt,
+		Action:    config.FailureActionRestart,
+		Component: cfg.FailComponent,
+		Nodes:     strings.Fields(cfg.FailNode),
+	}}
+}
 
-		go func(command []string, node string) {
-			defer wg.Done()
+// eventCommand returns the command injecting the event and whether it runs on the nodes of the event, as the
+// failures of the Knative planes are triggered through kubectl from the loader node
+func eventCommand(platform string, event *config.FailureEvent) ([]string, bool, error) {
+	if len(event.Command) > 0 {
+		return event.Command, true, nil
+	}
 
-			finalCommand := append([]string{"ssh", "-o", "StrictHostKeyChecking=no", node}, command...)
-			invokeLocally(finalCommand)
-		}(command, node)
+	switch platform {
+	case common.PlatformKnative:
+		return knativeCommand(event.Component, event.Action)
+	case common.PlatformDirigent:
+		command, err := dirigentCommand(event.Component, event.Action)
+		return command, true, err
+	default:
+		return nil, false, fmt.Errorf("no failure handler for platform %s, a custom command is required", platform)
 	}
+}
+
+// systemctlCommand stops the service on failure, starts it on recovery or restarts it
+func systemctlCommand(service string, action string) []string {
+	verb := "stop"
+	switch action {
+	case config.FailureActionRecover:
+		verb = "start"
+	case config.FailureActionRestart:
+		verb = "restart"
+	}
+
+	return []string{"sudo", "systemctl", verb, service}
+}
 
-	wg.Wait()
+// selfRecovering returns whether the failure of the component ends without a recovery, as the deleted pods of the
+// Knative planes are recreated by Kubernetes
+func selfRecovering(platform string, event *config.FailureEvent) bool {
+	return platform == common.PlatformKnative && len(event.Command) == 0 &&
+		(event.Component == ControlPlaneFailure || event.Component == DataPlaneFailure)
 }
 
-func triggerKnativeFailure(nodes string, component string) {
-	var command []string
+func knativeCommand(component string, action string) ([]string, bool, error) {
 	switch component {
-	case ControlPlaneFailure:
-		command = []string{"bash", "./pkg/driver/failure/knative_delete_control_plane.sh"}
-	case DataPlaneFailure:
-		command = []string{"bash", "./pkg/driver/failure/knative_delete_data_plane.sh"}
+	case ControlPlaneFailure, DataPlaneFailure:
+		if action == config.FailureActionRecover {
+			// the deleted pods are recreated by Kubernetes
+			return nil, false, fmt.Errorf("no recovery command for the Knative %s, a custom command is required", component)
+		}
+
+		// deleting the pods both fails and restarts the component
+		script := "./pkg/driver/failure/knative_delete_control_plane.sh"
+		if component == DataPlaneFailure {
+			script = "./pkg/driver/failure/knative_delete_data_plane.sh"
+		}
+		return []string{"bash", script}, false, nil
 	case WorkerNodeFailure:
-		command = []string{"sudo", "systemctl", "restart", "kubelet"}
+		return systemctlCommand("kubelet", action), true, nil
 	default:
-		logrus.Fatal("Invalid component to fail.")
-	}
-
-	if component != "worker_node" {
-		invokeLocally(command)
-	} else {
-		invokeRemotely(command, nodes)
+		return nil, false, fmt.Errorf("invalid component to fail '%s'", component)
 	}
 }
 
-func triggerDirigentFailure(nodes string, component string) {
-	var command []string
+func dirigentCommand(component string, action string) ([]string, error) {
+	var service string
 	switch component {
 	case ControlPlaneFailure:
-		command = []string{"sudo", "systemctl", "restart", "control_plane"}
+		service = "control_plane"
 	case DataPlaneFailure:
-		command = []string{"sudo", "systemctl", "restart", "data_plane"}
+		service = "data_plane"
 	case WorkerNodeFailure:
-		command = []string{"sudo", "systemctl", "restart", "worker_node"}
+		service = "worker_node"
 	default:
-		logrus.Fatal("Invalid component to fail.")
+		return nil, fmt.Errorf("invalid component to fail '%s'", component)
 	}
 
-	if nodes == "" {
-		invokeLocally(command)
-	} else {
-		invokeRemotely(command, nodes)
-	}
+	return systemctlCommand(service, action), nil
+}
+
+func invokeRemotely(command []string, node string) error {
+	return invokeLocally(append([]string{"ssh", "-o", "StrictHostKeyChecking=no", node}, command...))
 }
 
-func invokeLocally(command []string) {
-	cmd := exec.Command(command[0], command[1:]...)
-	output, err := cmd.CombinedOutput()
+func invokeLocally(command []string) error {
+	output, err := runCommand(command)
 	if err != nil {
 		logrus.Errorf("Error triggering %s failure - %v", command, err)
-		return
+		return err
 	}
 
 	logrus.Infof("Failure triggered - %s", string(output))
+	return nil
 }
@@ -0,0 +1,133 @@
+package driver
+
+import (
+	"encoding/csv"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gocarina/gocsv"
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+const (
+	defaultReadinessAttempts         = 5
+	defaultReadinessInitialBackoffMs = 1000
+	defaultReadinessMaxBackoffMs     = 30000
+)
+
+// readinessProbeSpecification is the health invocation, which returns as soon as possible
+var readinessProbeSpecification = common.RuntimeSpecification{Runtime: 1, Memory: 1}
+
+// ReadinessRecord is the outcome of probing a deployed function
+type ReadinessRecord struct {
+	Function string `csv:"function"`
+	Attempts int    `csv:"attempts"`
+	Ready    bool   `csv:"ready"`
+}
+
+// verifyDeployment probes every deployed function and applies the readiness policy to the unusable ones. It returns
+// false if the run should be aborted.
+func (d *Driver) verifyDeployment() bool {
+	probe := d.Configuration.LoaderConfiguration.ReadinessProbe
+	if probe == nil {
+		return true
+	}
+
+	// validated when the configuration is read
+	policy := probe.Policy
+	if policy == "" {
+		policy = config.ReadinessPolicyAbort
+	}
+
+	records := d.probeFunctions(probe)
+	WriteReadinessRecords(d.outputFilename("readiness"), records)
+
+	var ready []*common.Function
+	var unusable []string
+	for i, record := range records {
+		if record.Ready {
+			ready = append(ready, d.Configuration.Functions[i])
+		} else {
+			unusable = append(unusable, record.Function)
+		}
+	}
+	if len(unusable) == 0 {
+		log.Infof("All %d functions passed the readiness probe.", len(records))
+		return true
+	}
+
+	log.Warnf("%d out of %d functions failed the readiness probe: %v", len(unusable), len(records), unusable)
+	switch policy {
+	case config.ReadinessPolicyAbort:
+		log.Errorf("Aborting the run as some functions are unusable.")
+		return false
+	case config.ReadinessPolicyDrop:
+		if len(ready) == 0 {
+			log.Errorf("Aborting the run as no function is usable.")
+			return false
+		}
+
+		log.Warnf("Dropping the unusable functions from the run.")
+		d.Configuration.Functions = ready
+	}
+
+	return true
+}
+
+// probeFunctions invokes every function until an invocation succeeds or the attempts run out
+func (d *Driver) probeFunctions(probe *config.ReadinessProbe) []ReadinessRecord {
+	attempts := probe.Attempts
+	if attempts <= 0 {
+		attempts = defaultReadinessAttempts
+	}
+	initialBackoff := time.Duration(probe.InitialBackoffMs) * time.Millisecond
+	if probe.InitialBackoffMs <= 0 {
+		initialBackoff = defaultReadinessInitialBackoffMs * time.Millisecond
+	}
+	maxBackoff := time.Duration(probe.MaxBackoffMs) * time.Millisecond
+	if probe.MaxBackoffMs <= 0 {
+		maxBackoff = defaultReadinessMaxBackoffMs * time.Millisecond
+	}
+
+	records := make([]ReadinessRecord, len(d.Configuration.Functions))
+	wg := sync.WaitGroup{}
+	for i, function := range d.Configuration.Functions {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			records[i].Function = function.Name
+			backoff := initialBackoff
+			for records[i].Attempts < attempts {
+				records[i].Attempts++
+
+				specification := readinessProbeSpecification
+				if records[i].Ready, _ = d.ProbeInvoker.Invoke(function, &specification); records[i].Ready {
+					return
+				}
+
+				if records[i].Attempts < attempts {
+					log.Debugf("Readiness probe of function %s failed, retrying in %v.", function.Name, backoff)
+					time.Sleep(backoff)
+					backoff = min(2*backoff, maxBackoff)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	return records
+}
+
+func WriteReadinessRecords(filename string, records []ReadinessRecord) {
+	file, err := os.Create(filename)
+	common.Check(err)
+	defer file.Close()
+
+	if err := gocsv.MarshalCSV(records, gocsv.NewSafeCSVWriter(csv.NewWriter(file))); err != nil {
+		log.Errorf("Failed to write readiness probe results - %v", err)
+	}
+}
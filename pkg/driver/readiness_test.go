@@ -0,0 +1,76 @@
+package driver
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	"github.com/vhive-serverless/loader/pkg/metric"
+)
+
+// flakyInvoker fails the first failures[name] invocations of each function, or all of them if negative
+type flakyInvoker struct {
+	mutex       sync.Mutex
+	failures    map[string]int
+	invocations map[string]int
+}
+
+func (i *flakyInvoker) Invoke(function *common.Function, _ *common.RuntimeSpecification) (bool, *metric.ExecutionRecord) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	i.invocations[function.Name]++
+	failures := i.failures[function.Name]
+
+	return failures >= 0 && i.invocations[function.Name] > failures, &metric.ExecutionRecord{}
+}
+
+func TestVerifyDeployment(t *testing.T) {
+	tests := []struct {
+		policy            string
+		expectedProceed   bool
+		expectedFunctions []string
+	}{
+		{policy: config.ReadinessPolicyAbort, expectedProceed: false, expectedFunctions: []string{"ready", "slow", "broken"}},
+		{policy: config.ReadinessPolicyDrop, expectedProceed: true, expectedFunctions: []string{"ready", "slow"}},
+		{policy: config.ReadinessPolicyProceed, expectedProceed: true, expectedFunctions: []string{"ready", "slow", "broken"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.policy, func(t *testing.T) {
+			invoker := &flakyInvoker{
+				failures:    map[string]int{"ready": 0, "slow": 2, "broken": -1},
+				invocations: make(map[string]int),
+			}
+			d := &Driver{
+				Configuration: &config.Configuration{
+					LoaderConfiguration: &config.LoaderConfiguration{
+						OutputPathPrefix: filepath.Join(t.TempDir(), "test"),
+						ReadinessProbe:   &config.ReadinessProbe{Attempts: 3, InitialBackoffMs: 1, MaxBackoffMs: 2, Policy: test.policy},
+					},
+					Functions: []*common.Function{{Name: "ready"}, {Name: "slow"}, {Name: "broken"}},
+				},
+				ProbeInvoker: invoker,
+			}
+
+			if proceed := d.verifyDeployment(); proceed != test.expectedProceed {
+				t.Errorf("Expected the run to proceed: %t, got %t.", test.expectedProceed, proceed)
+			}
+
+			if len(d.Configuration.Functions) != len(test.expectedFunctions) {
+				t.Fatalf("Expected %d functions, got %d.", len(test.expectedFunctions), len(d.Configuration.Functions))
+			}
+			for i, function := range d.Configuration.Functions {
+				if function.Name != test.expectedFunctions[i] {
+					t.Errorf("Expected function %s, got %s.", test.expectedFunctions[i], function.Name)
+				}
+			}
+
+			if invoker.invocations["ready"] != 1 || invoker.invocations["slow"] != 3 || invoker.invocations["broken"] != 3 {
+				t.Errorf("Unexpected number of probes %v.", invoker.invocations)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package driver
+
+import (
+	"encoding/csv"
+	"os"
+
+	"github.com/gocarina/gocsv"
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+type SaturationTrial struct {
+	Step   int     `csv:"step"`
+	RPS    float64 `csv:"rps"`
+	Passed bool    `csv:"passed"`
+}
+
+// SaturationTrialRunner runs an experiment at the given RPS and returns whether all the SLOs have been met, or an error
+// if the trial has been aborted
+type SaturationTrialRunner func(step int, rps float64) (bool, error)
+
+// SearchSaturation looks for the maximum RPS at which the SLOs are met. It first tries MaxRps and MinRps and then
+// bisects the range between them until it is narrower than the precision or the trials run out. It returns the
+// highest RPS that passed, 0 if none did, and the results of all the trials in the order they were run. The search
+// stops with the error of the first aborted trial.
+func SearchSaturation(cfg *config.SaturationSearch, runTrial SaturationTrialRunner) (float64, []SaturationTrial, error) {
+	if cfg.MinRps <= 0 || cfg.MaxRps <= cfg.MinRps {
+		log.Fatalf("Invalid saturation search range [%.2f, %.2f] RPS.", cfg.MinRps, cfg.MaxRps)
+	}
+	if cfg.Precision <= 0 {
+		log.Fatalf("Saturation search precision should be positive.")
+	}
+
+	var trials []SaturationTrial
+	var err error
+	trial := func(rps float64) bool {
+		log.Infof("Saturation search step %d - running trial at %.2f RPS", len(trials), rps)
+
+		var passed bool
+		if passed, err = runTrial(len(trials), rps); err != nil {
+			log.Errorf("Saturation search step %d - trial at %.2f RPS aborted - %v", len(trials), rps, err)
+			return false
+		}
+		trials = append(trials, SaturationTrial{Step: len(trials), RPS: rps, Passed: passed})
+
+		if passed {
+			log.Infof("Saturation search step %d - SLOs met at %.2f RPS", len(trials)-1, rps)
+		} else {
+			log.Infof("Saturation search step %d - SLOs violated at %.2f RPS", len(trials)-1, rps)
+		}
+
+		return passed
+	}
+	trialsLeft := func() bool {
+		return err == nil && (cfg.MaxTrials <= 0 || len(trials) < cfg.MaxTrials)
+	}
+
+	if trial(cfg.MaxRps) {
+		return cfg.MaxRps, trials, nil
+	}
+	if !trialsLeft() || !trial(cfg.MinRps) {
+		return 0, trials, err
+	}
+
+	// invariant: low passed, high failed
+	low, high := cfg.MinRps, cfg.MaxRps
+	for high-low > cfg.Precision && trialsLeft() {
+		middle := (low + high) / 2
+		if trial(middle) {
+			low = middle
+		} else {
+			high = middle
+		}
+	}
+	if err != nil {
+		return 0, trials, err
+	}
+
+	return low, trials, nil
+}
+
+func WriteSaturationTrials(filename string, trials []SaturationTrial) {
+	file, err := os.Create(filename)
+	common.Check(err)
+	defer file.Close()
+
+	if err := gocsv.MarshalCSV(trials, gocsv.NewSafeCSVWriter(csv.NewWriter(file))); err != nil {
+		log.Errorf("Failed to write saturation search results - %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package driver
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+func TestSearchSaturation(t *testing.T) {
+	tests := []struct {
+		name           string
+		cfg            config.SaturationSearch
+		sustainableRPS float64
+		expectedRPS    float64
+		expectedTrials []float64
+	}{
+		{
+			name:           "saturation_within_range",
+			cfg:            config.SaturationSearch{MinRps: 10, MaxRps: 100, Precision: 5},
+			sustainableRPS: 42,
+			expectedRPS:    40.9375,
+			expectedTrials: []float64{100, 10, 55, 32.5, 43.75, 38.125, 40.9375},
+		},
+		{
+			name:           "max_rps_sustainable",
+			cfg:            config.SaturationSearch{MinRps: 10, MaxRps: 100, Precision: 5},
+			sustainableRPS: 200,
+			expectedRPS:    100,
+			expectedTrials: []float64{100},
+		},
+		{
+			name:           "min_rps_not_sustainable",
+			cfg:            config.SaturationSearch{MinRps: 10, MaxRps: 100, Precision: 5},
+			sustainableRPS: 5,
+			expectedRPS:    0,
+			expectedTrials: []float64{100, 10},
+		},
+		{
+			name:           "limited_trials",
+			cfg:            config.SaturationSearch{MinRps: 10, MaxRps: 100, Precision: 1, MaxTrials: 4},
+			sustainableRPS: 42,
+			expectedRPS:    32.5,
+			expectedTrials: []float64{100, 10, 55, 32.5},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rps, trials, err := SearchSaturation(&test.cfg, func(step int, rps float64) (bool, error) {
+				return rps <= test.sustainableRPS, nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if rps != test.expectedRPS {
+				t.Errorf("Expected maximum sustainable RPS %.4f, got %.4f.", test.expectedRPS, rps)
+			}
+
+			if len(trials) != len(test.expectedTrials) {
+				t.Fatalf("Expected %d trials, got %d.", len(test.expectedTrials), len(trials))
+			}
+
+			for i, trial := range trials {
+				if trial.Step != i || trial.RPS != test.expectedTrials[i] || trial.Passed != (trial.RPS <= test.sustainableRPS) {
+					t.Errorf("Unexpected trial %+v at step %d.", trial, i)
+				}
+			}
+		})
+	}
+}
+
+func TestSearchSaturationAborted(t *testing.T) {
+	cfg := &config.SaturationSearch{MinRps: 10, MaxRps: 100, Precision: 5}
+
+	rps, trials, err := SearchSaturation(cfg, func(step int, rps float64) (bool, error) {
+		if step == 2 {
+			return false, ErrDeploymentNotReady
+		}
+		return rps <= 42, nil
+	})
+
+	if !errors.Is(err, ErrDeploymentNotReady) || rps != 0 {
+		t.Errorf("Expected the search to be aborted, got %.2f RPS - %v.", rps, err)
+	}
+	if len(trials) != 2 {
+		t.Errorf("Expected only the trials before the aborted one to be recorded, got %+v.", trials)
+	}
+}
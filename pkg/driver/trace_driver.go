@@ -27,7 +27,9 @@ package driver
 import (
 	"container/list"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"os"
 	"strconv"
 	"sync"
@@ -50,10 +52,16 @@ type Driver struct {
 	Configuration          *config.Configuration
 	SpecificationGenerator *generator.SpecificationGenerator
 	Invoker                clients.Invoker
+	ProbeInvoker           clients.Invoker // of the readiness probes, which are not part of the experiment
 
-	AsyncRecords          *common.LockFreeQueue[*mc.ExecutionRecord]
-	readOpenWhiskMetadata sync.Mutex
-	allFunctionsInvoked   sync.WaitGroup
+	AsyncRecords        *common.LockFreeQueue[*mc.ExecutionRecord]
+	asyncFunctions      sync.Map // functions of the submitted asynchronous invocations by record
+	durationCalibration *mc.DurationCalibration
+	sloEvaluator        *mc.SLOEvaluator
+	coldStartRatio      *mc.ColdStartRatio
+
+	dagRecordsMutex sync.Mutex
+	dagRecords      []*mc.DAGExecutionRecord
 }
 
 func NewDriver(driverConfig *config.Configuration) *Driver {
@@ -61,12 +69,16 @@ func NewDriver(driverConfig *config.Configuration) *Driver {
 		Configuration:          driverConfig,
 		SpecificationGenerator: generator.NewSpecificationGenerator(driverConfig.LoaderConfiguration.Seed),
 
-		AsyncRecords:          common.NewLockFreeQueue[*mc.ExecutionRecord](),
-		readOpenWhiskMetadata: sync.Mutex{},
-		allFunctionsInvoked:   sync.WaitGroup{},
+		AsyncRecords:        common.NewLockFreeQueue[*mc.ExecutionRecord](),
+		durationCalibration: mc.NewDurationCalibration(),
+		sloEvaluator:        mc.NewSLOEvaluator(driverConfig.LoaderConfiguration.SLOs),
+		coldStartRatio:      mc.NewColdStartRatio(),
 	}
 
-	d.Invoker = clients.CreateInvoker(driverConfig, &d.allFunctionsInvoked, &d.readOpenWhiskMetadata)
+	d.Invoker = clients.CreateInvoker(driverConfig)
+	if driverConfig.LoaderConfiguration.ReadinessProbe != nil {
+		d.ProbeInvoker = clients.CreateProbeInvoker(driverConfig)
+	}
 
 	return d
 }
@@ -83,12 +95,17 @@ func (d *Driver) outputFilename(name string) string {
 /////////////////////////////////////////
 
 type InvocationMetadata struct {
-	RootFunction *list.List
-	Phase        common.ExperimentPhase
+	RootFunction    *list.List
+	Phase           common.ExperimentPhase
+	ColdStartWarmup bool
 
 	InvocationID string
 	IatIndex     int
 
+	// set only in the DAG mode
+	DAGInvocation     *DAGInvocation
+	InputPayloadBytes int
+
 	SuccessCount        *int64
 	FailedCount         *int64
 	FunctionsInvoked    *int64
@@ -112,54 +129,173 @@ func composeInvocationID(timeGranularity common.TraceGranularity, minuteIndex in
 	return fmt.Sprintf("%s%d.inv%d", timePrefix, minuteIndex, invocationIndex)
 }
 
+// nodeRuntimeSpecification returns the runtime specification of the invocation of the node, with the payload size
+// set by the topology of the DAG or passed from the parents of the node
+func nodeRuntimeSpecification(node *common.Node, iatIndex int, inputPayloadBytes int) *common.RuntimeSpecification {
+	runtimeSpecification := &node.Function.Specification.RuntimeSpecification[iatIndex]
+
+	payloadBytes := inputPayloadBytes
+	if node.PayloadBytes > 0 {
+		payloadBytes = node.PayloadBytes
+	}
+
+	if payloadBytes > 0 {
+		withPayload := *runtimeSpecification
+		withPayload.RequestPayloadBytes = payloadBytes
+		return &withPayload
+	}
+
+	return runtimeSpecification
+}
+
+// collectsAsyncResponses returns whether the records of asynchronous invocations are completed once their response is
+// available, by polling Dirigent or by receiving completion callbacks
+func (d *Driver) collectsAsyncResponses() bool {
+	return d.Configuration.LoaderConfiguration.CompletionCallback != nil ||
+		(d.Configuration.DirigentConfiguration != nil && d.Configuration.DirigentConfiguration.AsyncMode)
+}
+
+// completeAsyncRecords evaluates the asynchronous invocations completed by the collector against the SLOs, as only then
+// is known whether they succeeded, and hands their records over for writing
+func (d *Driver) completeAsyncRecords(completed <-chan *mc.ExecutionRecord, recordOutputChannel chan<- *mc.ExecutionRecord) {
+	for record := range completed {
+		if function, ok := d.asyncFunctions.LoadAndDelete(record); ok {
+			d.sloEvaluator.Add(function.(*common.Function), record, !record.FunctionTimeout)
+		}
+
+		recordOutputChannel <- record
+	}
+}
+
+// recordInvocation completes the record of an invocation of the node and hands it over for writing
+func (d *Driver) recordInvocation(metadata *InvocationMetadata, node *common.Node, record *mc.ExecutionRecord, success bool) {
+	function := node.Function
+
+	record.Phase = int(metadata.Phase)
+	record.Instance = fmt.Sprintf("%s%s", node.DAG, record.Instance)
+	record.InvocationID = metadata.InvocationID
+	record.ColdStartWarmup = metadata.ColdStartWarmup
+
+	if d.collectsAsyncResponses() && record.AsyncResponseID != "" {
+		record.TimeToSubmitMs = record.ResponseTime
+		d.asyncFunctions.Store(record, function)
+		d.AsyncRecords.Enqueue(record)
+	} else {
+		d.sloEvaluator.Add(function, record, success)
+		metadata.RecordOutputChannel <- record
+	}
+	atomic.AddInt64(metadata.FunctionsInvoked, 1)
+	if metadata.DAGInvocation != nil {
+		metadata.DAGInvocation.invocations.Add(1)
+	}
+	if !success {
+		log.Errorf("Invocation with for function %s with ID %s failed.", function.Name, metadata.InvocationID)
+		atomic.AddInt64(metadata.FailedCount, 1)
+		if metadata.DAGInvocation != nil {
+			metadata.DAGInvocation.failed.Store(true)
+		}
+		return
+	}
+	atomic.AddInt64(metadata.SuccessCount, 1)
+	d.durationCalibration.Add(function.Name, record)
+	d.coldStartRatio.Add(record)
+}
+
+// invokeFanOut issues the additional parallel invocations of a node with a fan-out and returns a function waiting for
+// them, which reports whether all of them succeeded
+func (d *Driver) invokeFanOut(metadata *InvocationMetadata, node *common.Node, inputPayloadBytes int) func() bool {
+	var wg sync.WaitGroup
+	var failed atomic.Bool
+	for i := 1; i < node.FanOut; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			success, record := d.Invoker.Invoke(node.Function, nodeRuntimeSpecification(node, metadata.IatIndex, inputPayloadBytes))
+			d.recordInvocation(metadata, node, record, success)
+			if !success {
+				failed.Store(true)
+			}
+		}()
+	}
+
+	return func() bool {
+		wg.Wait()
+		return !failed.Load()
+	}
+}
+
 func (d *Driver) invokeFunction(metadata *InvocationMetadata) {
 	defer metadata.AnnounceDoneWG.Done()
 
+	dag := metadata.DAGInvocation
+	if dag != nil {
+		defer func() {
+			if dagRecord := dag.branchCompleted(); dagRecord != nil {
+				d.dagRecordsMutex.Lock()
+				d.dagRecords = append(d.dagRecords, dagRecord)
+				d.dagRecordsMutex.Unlock()
+			}
+		}()
+	}
+
 	var success bool
 	node := metadata.RootFunction.Front()
 	var record *mc.ExecutionRecord
-	var runtimeSpecifications *common.RuntimeSpecification
 	var branches []*list.List
 	var invocationRetries int
+
+	inputPayloadBytes := metadata.InputPayloadBytes
+	if join := node.Value.(*common.Node); dag != nil && join.Parents > 1 {
+		var allParentsCompleted bool
+		if allParentsCompleted, inputPayloadBytes = dag.join(join, inputPayloadBytes); !allParentsCompleted {
+			return
+		}
+	}
+
 	for node != nil {
-		function := node.Value.(*common.Node).Function
-		runtimeSpecifications = &function.Specification.RuntimeSpecification[metadata.IatIndex]
+		dagNode := node.Value.(*common.Node)
+		function := dagNode.Function
 
-		success, record = d.Invoker.Invoke(function, runtimeSpecifications)
+		waitForFanOut := d.invokeFanOut(metadata, dagNode, inputPayloadBytes)
+		success, record = d.Invoker.Invoke(function, nodeRuntimeSpecification(dagNode, metadata.IatIndex, inputPayloadBytes))
 
 		if !success && (d.Configuration.LoaderConfiguration.DAGMode && invocationRetries == 0) {
 			log.Debugf("Invocation with for function %s with ID %s failed. Retrying Invocation", function.Name, metadata.InvocationID)
 			invocationRetries += 1
-			continue
-		}
-		record.Phase = int(metadata.Phase)
-		record.Instance = fmt.Sprintf("%s%s", node.Value.(*common.Node).DAG, record.Instance)
-		record.InvocationID = metadata.InvocationID
-
-		if d.Configuration.DirigentConfiguration != nil &&
-			d.Configuration.DirigentConfiguration.AsyncMode && record.AsyncResponseID != "" {
-			record.TimeToSubmitMs = record.ResponseTime
-			d.AsyncRecords.Enqueue(record)
-		} else {
-			metadata.RecordOutputChannel <- record
+			success, record = d.Invoker.Invoke(function, nodeRuntimeSpecification(dagNode, metadata.IatIndex, inputPayloadBytes))
 		}
-		atomic.AddInt64(metadata.FunctionsInvoked, 1)
-		if !success {
-			log.Errorf("Invocation with for function %s with ID %s failed.", function.Name, metadata.InvocationID)
-			atomic.AddInt64(metadata.FailedCount, 1)
+		d.recordInvocation(metadata, dagNode, record, success)
+		if !waitForFanOut() || !success {
 			break
 		}
-		atomic.AddInt64(metadata.SuccessCount, 1)
-		branches = node.Value.(*common.Node).Branches
+
+		inputPayloadBytes = 0
+		if d.Configuration.LoaderConfiguration.DAGDataPassing {
+			inputPayloadBytes = int(record.BytesReceived)
+		}
+
+		branches = dagNode.Branches
 		for i := 0; i < len(branches); i++ {
+			if !dag.isTaken(dagNode, branches[i].Front().Value.(*common.Node)) {
+				continue
+			}
+
 			newMetadataValue := *metadata
 			newMetadata := &newMetadataValue
 			newMetadata.RootFunction = branches[i]
+			newMetadata.InputPayloadBytes = inputPayloadBytes
 			newMetadata.AnnounceDoneWG.Add(1)
+			if dag != nil {
+				dag.branchStarted()
+			}
 			go d.invokeFunction(newMetadata)
 		}
 
 		node = node.Next()
+		if node != nil && !dag.isTaken(dagNode, node.Value.(*common.Node)) {
+			break
+		}
 	}
 }
 
@@ -195,6 +331,11 @@ func (d *Driver) functionsDriver(functionLinkedList *list.List, announceFunction
 		log.Infof("Warmup phase has started.")
 	}
 
+	var coldStartWarmupUs int64
+	if d.Configuration.ColdStartTarget != nil {
+		coldStartWarmupUs = d.Configuration.ColdStartTarget.Warmup.Microseconds()
+	}
+
 	startOfExperiment := time.Now()
 	var previousIATSum int64
 
@@ -214,12 +355,22 @@ func (d *Driver) functionsDriver(functionLinkedList *list.List, announceFunction
 		previousIATSum += iat.Microseconds()
 
 		if !d.Configuration.TestMode {
+			var dagInvocation *DAGInvocation
+			if d.Configuration.LoaderConfiguration.DAGMode {
+				dagInvocation = NewDAGInvocation(functionLinkedList, currentPhase,
+					composeInvocationID(d.Configuration.TraceGranularity, minuteIndex, invocationSinceTheBeginningOfMinute),
+					d.Configuration.LoaderConfiguration.Seed)
+				dagInvocation.branchStarted()
+			}
+
 			waitForInvocations.Add(1)
 			go d.invokeFunction(&InvocationMetadata{
 				RootFunction:        functionLinkedList,
 				Phase:               currentPhase,
+				ColdStartWarmup:     previousIATSum < coldStartWarmupUs,
 				InvocationID:        composeInvocationID(d.Configuration.TraceGranularity, minuteIndex, invocationSinceTheBeginningOfMinute),
 				IatIndex:            iatIndex,
+				DAGInvocation:       dagInvocation,
 				SuccessCount:        &successfulInvocations,
 				FailedCount:         &failedInvocations,
 				FunctionsInvoked:    &functionsInvoked,
@@ -359,7 +510,7 @@ func (d *Driver) startBackgroundProcesses(allRecordsWritten *sync.WaitGroup) (*s
 	return auxiliaryProcessBarrier, globalMetricsCollector, totalIssuedChannel, finishCh
 }
 
-func (d *Driver) internalRun() {
+func (d *Driver) internalRun(failureScheduler *failure.Scheduler) {
 	var successfulInvocations int64
 	var failedInvocations int64
 	var invocationsIssued int64
@@ -372,6 +523,24 @@ func (d *Driver) internalRun() {
 	backgroundProcessesInitializationBarrier, globalMetricsCollector, totalIssuedChannel, scraperFinishCh := d.startBackgroundProcesses(&allRecordsWritten)
 	backgroundProcessesInitializationBarrier.Wait()
 
+	var collector asyncCollector
+	var collectTimeout time.Duration
+	asyncCompleted := make(chan *mc.ExecutionRecord)
+	if callback := d.Configuration.LoaderConfiguration.CompletionCallback; callback != nil {
+		collector = newAsyncCompletionReceiver(callback, d.Configuration.LoaderConfiguration.Platform, d.AsyncRecords, asyncCompleted)
+		collectTimeout = time.Duration(callback.WaitToCollectMin) * time.Minute
+	} else if d.Configuration.DirigentConfiguration != nil && d.Configuration.DirigentConfiguration.AsyncMode {
+		collector = newAsyncResponseCollector(d.Configuration.DirigentConfiguration, d.AsyncRecords, asyncCompleted)
+		collectTimeout = time.Duration(d.Configuration.DirigentConfiguration.AsyncWaitToCollectMin) * time.Minute
+	}
+	if collector != nil {
+		go d.completeAsyncRecords(asyncCompleted, globalMetricsCollector)
+		go collector.Run()
+	}
+
+	// started together with the invocation drivers, so that the events are timed from the start of the experiment
+	failureScheduler.Start()
+
 	if d.Configuration.LoaderConfiguration.DAGMode {
 		functions := d.Configuration.Functions
 		dagLists := generator.GenerateDAGs(d.Configuration.LoaderConfiguration, functions, false)
@@ -406,17 +575,12 @@ func (d *Driver) internalRun() {
 		}
 	}
 	allIndividualDriversCompleted.Wait()
+	if collector != nil {
+		collector.Finish(collectTimeout)
+		close(asyncCompleted)
+	}
 	if atomic.LoadInt64(&successfulInvocations)+atomic.LoadInt64(&failedInvocations) != 0 {
 		log.Debugf("Waiting for all the invocations record to be written.\n")
-
-		if d.Configuration.DirigentConfiguration != nil && d.Configuration.DirigentConfiguration.AsyncMode {
-			sleepFor := time.Duration(d.Configuration.DirigentConfiguration.AsyncWaitToCollectMin) * time.Minute
-
-			log.Infof("Sleeping for %v...", sleepFor)
-			time.Sleep(sleepFor)
-
-			d.writeAsyncRecordsToLog(globalMetricsCollector)
-		}
 		totalIssuedChannel <- atomic.LoadInt64(&invocationsIssued)
 		scraperFinishCh <- 0 // Ask the scraper to finish metrics collection
 
@@ -431,6 +595,68 @@ func (d *Driver) internalRun() {
 	log.Infof("Number of failed invocations: \t%d", statFailed)
 	log.Infof("Total invocations: \t\t\t%d", statSuccess+statFailed)
 	log.Infof("Failure rate: \t\t\t%.2f%%", float64(statFailed)*100.0/float64(statSuccess+statFailed))
+
+	if d.Configuration.LoaderConfiguration.DAGMode {
+		mc.WriteDAGExecutionRecords(d.outputFilename("dag"), d.dagRecords)
+	}
+	if recorder, ok := d.Invoker.(clients.PlatformRecorder); ok {
+		recorder.WritePlatformRecords(d.outputFilename(recorder.PlatformRecordsName()))
+	}
+
+	d.checkDurationCalibration()
+	d.checkColdStartRatio()
+}
+
+// checkDurationCalibration warns about functions whose actual duration differs from the requested one, which is the
+// case when the iterations multiplier of the deployment does not match the hardware.
+func (d *Driver) checkDurationCalibration() {
+	miscalibrated := d.durationCalibration.Miscalibrated(mc.DurationCalibrationTolerance)
+	if len(miscalibrated) == 0 {
+		return
+	}
+
+	log.Warnf("The actual duration of %d function(s) differs from the requested one by more than %.0f%%. "+
+		"Calibrate the iterations multiplier of the deployment (see tools/calibrator).",
+		len(miscalibrated), mc.DurationCalibrationTolerance*100)
+
+	multipliers := make(map[string]int)
+	for _, function := range d.Configuration.Functions {
+		if function.DirigentMetadata != nil {
+			multipliers[function.Name] = function.DirigentMetadata.IterationMultiplier
+		}
+	}
+
+	for _, function := range miscalibrated {
+		if multiplier := multipliers[function.Name]; multiplier > 0 {
+			log.Warnf("\t%s: actual/requested duration = %.2f over %d invocations, suggested ITERATIONS_MULTIPLIER = %d",
+				function.Name, function.MeanRatio, function.Samples, int(math.Round(float64(multiplier)/function.MeanRatio)))
+		} else {
+			log.Warnf("\t%s: actual/requested duration = %.2f over %d invocations",
+				function.Name, function.MeanRatio, function.Samples)
+		}
+	}
+}
+
+// checkColdStartRatio warns if the achieved cold start ratio of the RPS mode does not match the target, e.g., because
+// the keep-alive model does not match the platform.
+func (d *Driver) checkColdStartRatio() {
+	target := d.Configuration.ColdStartTarget
+	if target == nil {
+		return
+	}
+
+	achieved, samples := d.coldStartRatio.Achieved()
+	if samples == 0 {
+		log.Infof("Cold start ratio cannot be validated as the functions do not report the start type.")
+		return
+	}
+
+	if math.Abs(achieved-target.RatioPercentage) > mc.ColdStartRatioTolerance {
+		log.Warnf("Achieved cold start ratio %.2f%% differs from the target %.2f%% (%d invocations). "+
+			"Check the keep-alive model of the RPS mode.", achieved, target.RatioPercentage, samples)
+	} else {
+		log.Infof("Achieved cold start ratio: %.2f%% (target %.2f%%, %d invocations)", achieved, target.RatioPercentage, samples)
+	}
 }
 
 func (d *Driver) GenerateSpecification() {
@@ -452,6 +678,14 @@ func (d *Driver) GenerateSpecification() {
 	}
 }
 
+// GeneratePayloadSpecification draws payload sizes for functions whose specification has not been generated by
+// GenerateSpecification, i.e., in the RPS mode
+func (d *Driver) GeneratePayloadSpecification() {
+	for _, function := range d.Configuration.Functions {
+		d.SpecificationGenerator.GeneratePayloadSpecification(function)
+	}
+}
+
 func (d *Driver) outputIATsToFile() {
 	for i, function := range d.Configuration.Functions {
 		file, _ := json.MarshalIndent(function.Specification, "", " ")
@@ -489,21 +723,85 @@ func (d *Driver) ReadOrWriteFileSpecification(writeIATsToFile bool, readIATsFrom
 	}
 }
 
-func (d *Driver) RunExperiment() {
+// ErrDeploymentNotReady is returned when the run is aborted as the deployed functions failed the readiness probe
+var ErrDeploymentNotReady = errors.New("the deployed functions failed the readiness probe")
+
+// RunExperiment runs the experiment and returns false if any of the SLOs has been violated. It returns
+// ErrDeploymentNotReady without running the experiment if the readiness policy aborts the run.
+func (d *Driver) RunExperiment() (bool, error) {
 	if d.Configuration.WithWarmup() {
 		trace.DoStaticTraceProfiling(d.Configuration.Functions)
 	}
 
 	trace.ApplyResourceLimits(d.Configuration.Functions, d.Configuration.LoaderConfiguration.CPULimit)
 
+	if d.Configuration.RunID == "" {
+		d.Configuration.RunID = deployment.NewRunID()
+	}
+	log.Infof("Run ID: %s - leftovers of a crashed run can be removed with 'cleanup run %s'", d.Configuration.RunID, d.Configuration.RunID)
+
+	// validated before the deployment
+	failureScheduler := failure.NewScheduler(d.Configuration.LoaderConfiguration.Platform, d.Configuration.FailureConfiguration)
+
 	deployer := deployment.CreateDeployer(d.Configuration)
 	deployer.Deploy(d.Configuration)
 
-	go failure.ScheduleFailure(d.Configuration.LoaderConfiguration.Platform, d.Configuration.FailureConfiguration)
+	if !d.verifyDeployment() {
+		d.cleanDeployment(deployer)
+		return false, ErrDeploymentNotReady
+	}
 
 	// Generate load
-	d.internalRun()
+	d.internalRun(failureScheduler)
+
+	if timeline := failureScheduler.Stop(); failureScheduler.Enabled() {
+		failure.WriteTimeline(d.outputFilename("failures"), timeline)
+	}
 
 	// Clean up
+	d.cleanDeployment(deployer)
+
+	return d.evaluateSLOs(), nil
+}
+
+// cleanDeployment removes the deployed functions, unless they are kept to be reused by the next run
+func (d *Driver) cleanDeployment(deployer deployment.FunctionDeployer) {
+	if d.Configuration.LoaderConfiguration.ReuseDeployment && d.Configuration.LoaderConfiguration.Platform == common.PlatformKnative {
+		log.Infof("Keeping the deployed functions for the next run - they can be removed with 'cleanup run %s'", d.Configuration.RunID)
+		return
+	}
+
 	deployer.Clean()
 }
+
+func (d *Driver) evaluateSLOs() bool {
+	if len(d.Configuration.LoaderConfiguration.SLOs) == 0 {
+		return true
+	}
+
+	verdicts := d.sloEvaluator.Evaluate()
+	mc.WriteSLOVerdicts(d.outputFilename("slo"), verdicts)
+
+	passed := true
+	for _, v := range verdicts {
+		description := fmt.Sprintf("SLO %s [%s]: %s", v.SLO, v.Function, v.Metric)
+		if v.Metric != config.SLOMetricFailureRate {
+			description += fmt.Sprintf(" p%g", v.Percentile)
+		}
+
+		if v.Passed {
+			log.Infof("%s = %.2f <= %.2f (%d samples) - PASSED", description, v.Value, v.Threshold, v.Samples)
+		} else {
+			log.Errorf("%s = %.2f > %.2f (%d samples) - VIOLATED", description, v.Value, v.Threshold, v.Samples)
+			passed = false
+		}
+	}
+
+	if passed {
+		log.Infof("Verdict: all %d SLO(s) have been met.", len(verdicts))
+	} else {
+		log.Errorf("Verdict: SLO(s) have been violated.")
+	}
+
+	return passed
+}
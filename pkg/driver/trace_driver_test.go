@@ -341,6 +341,122 @@ func TestDAGInvocation(t *testing.T) {
 	}
 }
 
+func TestDAGFanOutInvocation(t *testing.T) {
+	var successCount, failureCount, functionsInvoked int64
+	invocationRecordOutputChannel := make(chan *metric.ExecutionRecord, 8)
+	announceDone := &sync.WaitGroup{}
+
+	testDriver := createTestDriver([]int{4}, false)
+	address, port := "localhost", 8088
+	function := testDriver.Configuration.Functions[0]
+	function.Endpoint = fmt.Sprintf("%s:%d", address, port)
+
+	go standard.StartGRPCServer(address, port, standard.TraceFunction, "")
+	function.Specification.RuntimeSpecification = []common.RuntimeSpecification{{
+		Runtime: 100,
+		Memory:  128,
+	}}
+
+	// entry -> map (fan-out of 3) -> reduce
+	rootFunction := list.New()
+	rootFunction.PushBack(&common.Node{Function: function, Depth: 0})
+	rootFunction.PushBack(&common.Node{Function: function, Depth: 1, FanOut: 3, PayloadBytes: 1024})
+	rootFunction.PushBack(&common.Node{Function: function, Depth: 2})
+	time.Sleep(2 * time.Second)
+
+	announceDone.Add(1)
+	testDriver.invokeFunction(&InvocationMetadata{
+		RootFunction:        rootFunction,
+		Phase:               common.ExecutionPhase,
+		InvocationID:        composeInvocationID(common.MinuteGranularity, 0, 0),
+		SuccessCount:        &successCount,
+		FailedCount:         &failureCount,
+		FunctionsInvoked:    &functionsInvoked,
+		RecordOutputChannel: invocationRecordOutputChannel,
+		AnnounceDoneWG:      announceDone,
+	})
+	announceDone.Wait()
+
+	if successCount != 5 || failureCount != 0 || functionsInvoked != 5 {
+		t.Errorf("Expected 5 successful invocations, got %d successful and %d failed.", successCount, failureCount)
+	}
+
+	withPayload := 0
+	for i := 0; i < 5; i++ {
+		if record := <-invocationRecordOutputChannel; record.BytesSent >= 1024 {
+			withPayload++
+		}
+	}
+	if withPayload != 3 {
+		t.Errorf("Expected 3 invocations with the payload of the edge, got %d.", withPayload)
+	}
+}
+
+func TestDAGJoinInvocation(t *testing.T) {
+	var successCount, failureCount, functionsInvoked int64
+	invocationRecordOutputChannel := make(chan *metric.ExecutionRecord, 8)
+	announceDone := &sync.WaitGroup{}
+
+	testDriver := createTestDriver([]int{4}, false)
+	testDriver.Configuration.LoaderConfiguration.DAGMode = true
+	address, port := "localhost", 8089
+	function := testDriver.Configuration.Functions[0]
+	function.Endpoint = fmt.Sprintf("%s:%d", address, port)
+
+	go standard.StartGRPCServer(address, port, standard.TraceFunction, "")
+	function.Specification.RuntimeSpecification = []common.RuntimeSpecification{{
+		Runtime: 100,
+		Memory:  128,
+	}}
+
+	// a -> b -> d, a -> c -> d
+	a := &common.Node{Function: function, Depth: 0, DAG: "DAG diamond,"}
+	b := &common.Node{Function: function, Depth: 1}
+	c := &common.Node{Function: function, Depth: 1}
+	d := &common.Node{Function: function, Depth: 2, Parents: 2}
+	join := list.New()
+	join.PushBack(d)
+	b.Branches = []*list.List{join}
+	c.Branches = []*list.List{join}
+	branch := list.New()
+	branch.PushBack(c)
+	a.Branches = []*list.List{branch}
+
+	rootFunction := list.New()
+	rootFunction.PushBack(a)
+	rootFunction.PushBack(b)
+	time.Sleep(2 * time.Second)
+
+	dagInvocation := NewDAGInvocation(rootFunction, common.ExecutionPhase, composeInvocationID(common.MinuteGranularity, 0, 0), 42)
+	dagInvocation.branchStarted()
+
+	announceDone.Add(1)
+	testDriver.invokeFunction(&InvocationMetadata{
+		RootFunction:        rootFunction,
+		Phase:               common.ExecutionPhase,
+		InvocationID:        composeInvocationID(common.MinuteGranularity, 0, 0),
+		DAGInvocation:       dagInvocation,
+		SuccessCount:        &successCount,
+		FailedCount:         &failureCount,
+		FunctionsInvoked:    &functionsInvoked,
+		RecordOutputChannel: invocationRecordOutputChannel,
+		AnnounceDoneWG:      announceDone,
+	})
+	announceDone.Wait()
+
+	if successCount != 4 || failureCount != 0 || functionsInvoked != 4 {
+		t.Errorf("Expected 4 successful invocations with the join node invoked once, got %d successful and %d failed.", successCount, failureCount)
+	}
+
+	if len(testDriver.dagRecords) != 1 {
+		t.Fatalf("Expected a single DAG record, got %d.", len(testDriver.dagRecords))
+	}
+	record := testDriver.dagRecords[0]
+	if record.DAG != "DAG diamond" || record.Invocations != 4 || record.Failed || record.ResponseTime <= 0 {
+		t.Errorf("Unexpected DAG record %+v.", record)
+	}
+}
+
 func TestVSwarmDAGInvocation(t *testing.T) {
 	var successCount int64 = 0
 	var failureCount int64 = 0
@@ -93,10 +93,10 @@ func generateCDF(file string) [][]float64 {
 }
 
 // Generate pseudo-random probabilities and compare it with the given CDF to obtain the depth and width of the DAG
-func getDAGStats(cdf [][]float64, maxSize int, numberOfTries int) (int, int) {
+func getDAGStats(cdf [][]float64, maxSize int, numberOfTries int, dagRand *rand.Rand) (int, int) {
 	var width, depth int
-	depthProb := rand.Float64() * 100
-	widthProb := rand.Float64() * 100
+	depthProb := dagRand.Float64() * 100
+	widthProb := dagRand.Float64() * 100
 	for i, value := range cdf[1] {
 		if value >= widthProb {
 			width = int(cdf[0][i])
@@ -114,12 +114,42 @@ func getDAGStats(cdf [][]float64, maxSize int, numberOfTries int) (int, int) {
 		if numberOfTries == 10 {
 			return 1, maxSize
 		}
-		width, depth = getDAGStats(cdf, maxSize, numberOfTries+1)
+		width, depth = getDAGStats(cdf, maxSize, numberOfTries+1, dagRand)
 	}
 	return width, depth
 }
 
+// GenerateDAGs creates the DAG workflows from the explicit topologies if DAGTopologyPath is set, and generates them
+// with a generator seeded by Seed otherwise, so that the same configuration always yields the same DAGs
 func GenerateDAGs(config *config.LoaderConfiguration, functions []*common.Function, test bool) []*list.List {
+	var totalDAGList []*list.List
+	if config.DAGTopologyPath != "" {
+		totalDAGList = GenerateDAGsFromTopologies(ReadDAGTopologies(config.DAGTopologyPath), functions)
+	} else {
+		totalDAGList = generateRandomDAGs(config, functions)
+	}
+
+	if !test {
+		for _, dag := range totalDAGList {
+			printDAG(dag)
+		}
+	}
+	if config.DAGExportPath != "" && len(totalDAGList) > 0 {
+		ExportDAGTopologies(config.DAGExportPath, totalDAGList)
+		log.Infof("DAGs exported to %s", config.DAGExportPath)
+	}
+
+	return totalDAGList
+}
+
+func generateRandomDAGs(config *config.LoaderConfiguration, functions []*common.Function) []*list.List {
+	dagRand := rand.New(rand.NewSource(config.Seed))
+
+	var DAGDistribution [][]float64
+	if config.EnableDAGDataset {
+		DAGDistribution = generateCDF(fmt.Sprintf("%s/dag_structure.csv", config.TracePath))
+	}
+
 	var width, depth int
 	var functionIndex int = 0
 	var dagIdentity int = 0
@@ -127,8 +157,7 @@ func GenerateDAGs(config *config.LoaderConfiguration, functions []*common.Functi
 	totalDAGList := []*list.List{}
 	for {
 		if config.EnableDAGDataset {
-			DAGDistribution := generateCDF(fmt.Sprintf("%s/dag_structure.csv", config.TracePath))
-			width, depth = getDAGStats(DAGDistribution, len(functions), 0)
+			width, depth = getDAGStats(DAGDistribution, len(functions), 0, dagRand)
 		} else {
 			// Sanity checking if max size of DAG exceeds number of functions available
 			width = config.Width
@@ -141,17 +170,14 @@ func GenerateDAGs(config *config.LoaderConfiguration, functions []*common.Functi
 			log.Infof("DAGs created: %d, Total Functions used: %d, Functions Unused: %d", dagIdentity, functionIndex, len(functions)-functionIndex)
 			break
 		}
-		functionLinkedList, functionIndex = createDAGWorkflow(functions, functionIndex, width, depth, dagIdentity)
+		functionLinkedList, functionIndex = createDAGWorkflow(functions, functionIndex, width, depth, dagIdentity, dagRand)
 		dagIdentity++
-		if !test {
-			printDAG(functionLinkedList)
-		}
 		totalDAGList = append(totalDAGList, functionLinkedList)
 	}
 	return totalDAGList
 }
 
-func createDAGWorkflow(functionList []*common.Function, functionID int, maxWidth int, maxDepth int, dagIdentity int) (*list.List, int) {
+func createDAGWorkflow(functionList []*common.Function, functionID int, maxWidth int, maxDepth int, dagIdentity int, dagRand *rand.Rand) (*list.List, int) {
 	DAGList := list.New()
 	dagIdentifier := fmt.Sprintf("DAG %d,", dagIdentity)
 	var function *common.Function = functionList[functionID]
@@ -159,7 +185,7 @@ func createDAGWorkflow(functionList []*common.Function, functionID int, maxWidth
 		DAGList.PushBack(&common.Node{Function: function, Depth: 0, DAG: dagIdentifier})
 		return DAGList, functionID + 1
 	}
-	widthList := generateNodeDistribution(maxWidth, maxDepth)
+	widthList := generateNodeDistribution(maxWidth, maxDepth, dagRand)
 	// Implement a FIFO queue for nodes to assign functions and branches to each node.
 	nodeQueue := []*list.Element{}
 	for i := 0; i < len(widthList); i++ {
@@ -184,7 +210,7 @@ func createDAGWorkflow(functionList []*common.Function, functionID int, maxWidth
 		// Creating parallel branches from the node, if width of next stage > width of current stage
 		var nodeList []*list.List
 		if widthList[node.Depth+1] > 0 {
-			nodeList, nodeQueue = addBranches(nodeQueue, widthList, node, functionList, functionID, dagIdentifier)
+			nodeList, nodeQueue = addBranches(nodeQueue, widthList, node, functionList, functionID, dagIdentifier, dagRand)
 			functionID += len(nodeList)
 		} else {
 			nodeList = []*list.List{}
@@ -194,12 +220,12 @@ func createDAGWorkflow(functionList []*common.Function, functionID int, maxWidth
 	return DAGList, functionID
 }
 
-func addBranches(nodeQueue []*list.Element, widthList []int, node *common.Node, functionList []*common.Function, functionID int, dagIdentifier string) ([]*list.List, []*list.Element) {
+func addBranches(nodeQueue []*list.Element, widthList []int, node *common.Node, functionList []*common.Function, functionID int, dagIdentifier string, dagRand *rand.Rand) ([]*list.List, []*list.Element) {
 	var additionalBranches int
 	if len(nodeQueue) < 1 || (nodeQueue[0].Value.(*common.Node).Depth > node.Depth) {
 		additionalBranches = widthList[node.Depth+1]
 	} else {
-		additionalBranches = rand.Intn(widthList[node.Depth+1] + 1)
+		additionalBranches = dagRand.Intn(widthList[node.Depth+1] + 1)
 	}
 	for i := node.Depth + 1; i < len(widthList); i++ {
 		widthList[i] -= additionalBranches
@@ -225,12 +251,12 @@ func createNewBranch(functionList []*common.Function, node *common.Node, maxDept
 	return DAGBranch
 }
 
-func generateNodeDistribution(maxWidth int, maxDepth int) []int {
+func generateNodeDistribution(maxWidth int, maxDepth int, dagRand *rand.Rand) []int {
 	// Generating the number of nodes per depth (stage).
 	widthList := []int{}
 	widthList = append(widthList, 1)
 	for i := 1; i < maxDepth-1; i++ {
-		widthList = append(widthList, (rand.Intn(maxWidth-widthList[i-1]+1) + widthList[i-1]))
+		widthList = append(widthList, (dagRand.Intn(maxWidth-widthList[i-1]+1) + widthList[i-1]))
 	}
 	widthList = append(widthList, maxWidth)
 	return widthList
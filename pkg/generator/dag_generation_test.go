@@ -26,6 +26,9 @@ package generator
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 
 	"github.com/vhive-serverless/loader/pkg/common"
@@ -135,3 +138,229 @@ func TestGenerateDAGByDataset(t *testing.T) {
 		t.Error("Unable to generate DAGs by Dataset")
 	}
 }
+
+func createTopologyTestFunctions() []*common.Function {
+	var result []*common.Function
+	for i, app := range []string{"app-a", "app-b", "app-a", "app-b", "app-a", "app-c"} {
+		result = append(result, &common.Function{
+			Name:            fmt.Sprintf("trace-func-%d", i),
+			InvocationStats: &common.FunctionInvocationStats{HashApp: app, HashFunction: fmt.Sprintf("func-%d", i)},
+		})
+	}
+
+	return result
+}
+
+func TestGenerateDAGsFromTopologies(t *testing.T) {
+	jsonTopology := `{"DAGs": [{"Name": "pipeline", "HashApp": "app-a",
+		"Nodes": [{"ID": "entry", "HashFunction": "func-4"}, {"ID": "map", "Choice": true}, {"ID": "reduce"}, {"ID": "log"}],
+		"Edges": [{"From": "entry", "To": "map", "FanOut": 3, "PayloadBytes": 2048},
+			{"From": "map", "To": "reduce"}, {"From": "entry", "To": "log", "Probability": 0.25}]}]}`
+	dotTopology := `// same workflow as the JSON one
+digraph pipeline {
+	app = "app-a"
+	entry [function = "func-4"];
+	entry -> map [fanout = 3, payload = 2048]
+	map [choice = true]
+	map -> reduce
+	entry -> log [probability = 0.25] /* second branch */
+}`
+
+	dir := t.TempDir()
+	for name, topology := range map[string]string{"topology.json": jsonTopology, "topology.dot": dotTopology} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name)
+			if err := os.WriteFile(path, []byte(topology), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			topologies := ReadDAGTopologies(path)
+			if len(topologies) != 1 || topologies[0].Name != "pipeline" || len(topologies[0].Nodes) != 4 || len(topologies[0].Edges) != 3 {
+				t.Fatalf("Unexpected topologies %+v.", topologies)
+			}
+
+			dags := GenerateDAGsFromTopologies(topologies, createTopologyTestFunctions())
+			if len(dags) != 1 || dags[0].Len() != 3 {
+				t.Fatalf("Expected a single DAG with the chain entry -> map -> reduce.")
+			}
+
+			// entry is mapped explicitly, the other nodes to the functions of the application in the trace order
+			chain := dags[0]
+			entry := chain.Front().Value.(*common.Node)
+			mapNode := chain.Front().Next().Value.(*common.Node)
+			reduce := chain.Back().Value.(*common.Node)
+			if entry.Function.Name != "trace-func-4" || mapNode.Function.Name != "trace-func-0" || reduce.Function.Name != "trace-func-2" {
+				t.Errorf("Unexpected mapping of the chain: %s, %s, %s.", entry.Function.Name, mapNode.Function.Name, reduce.Function.Name)
+			}
+			if mapNode.FanOut != 3 || mapNode.PayloadBytes != 2048 || mapNode.Depth != 1 || reduce.Depth != 2 || entry.DAG != "DAG pipeline," {
+				t.Errorf("Unexpected attributes of the nodes: %+v, %+v.", mapNode, reduce)
+			}
+
+			// functions are reused once the application runs out of them
+			if len(entry.Branches) != 1 || entry.Branches[0].Front().Value.(*common.Node).Function.Name != "trace-func-4" {
+				t.Fatalf("Unexpected branch of the entry node.")
+			}
+			if log := entry.Branches[0].Front().Value.(*common.Node); log.ID != "log" || log.Probability != 0.25 || !mapNode.Choice || entry.Choice {
+				t.Errorf("Unexpected conditional attributes of the nodes: %+v, %+v.", mapNode, log)
+			}
+		})
+	}
+}
+
+func TestDAGTopologyJoin(t *testing.T) {
+	// a -> b -> d, a -> c -> d, d -> e
+	topology := DAGTopology{
+		Nodes: []DAGTopologyNode{{ID: "a"}, {ID: "b"}, {ID: "c"}, {ID: "d"}, {ID: "e"}},
+		Edges: []DAGTopologyEdge{{From: "a", To: "b"}, {From: "a", To: "c"}, {From: "b", To: "d", PayloadBytes: 512},
+			{From: "c", To: "d", PayloadBytes: 512}, {From: "d", To: "e"}},
+	}
+
+	chain, err := createTopologyWorkflow(&topology, createTopologyTestFunctions(), make(map[*common.Function]bool))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chain.Len() != 2 {
+		t.Fatalf("Expected the chain a -> b, got %d nodes.", chain.Len())
+	}
+
+	a := chain.Front().Value.(*common.Node)
+	b := chain.Back().Value.(*common.Node)
+	if len(a.Branches) != 1 || len(b.Branches) != 1 {
+		t.Fatalf("Expected a single branch from a and b.")
+	}
+	c := a.Branches[0].Front().Value.(*common.Node)
+	if len(c.Branches) != 1 || c.Branches[0] != b.Branches[0] {
+		t.Fatalf("Expected b and c to share the branch of the join node.")
+	}
+
+	join := b.Branches[0]
+	d := join.Front().Value.(*common.Node)
+	e := join.Back().Value.(*common.Node)
+	if join.Len() != 2 || d.Parents != 2 || d.Depth != 2 || d.PayloadBytes != 512 || e.Parents != 1 || e.Depth != 3 {
+		t.Errorf("Unexpected join branch: %+v, %+v.", d, e)
+	}
+}
+
+func TestInvalidDAGTopologies(t *testing.T) {
+	tests := []struct {
+		name     string
+		topology DAGTopology
+	}{
+		{name: "no_nodes", topology: DAGTopology{}},
+		{name: "duplicate_nodes", topology: DAGTopology{Nodes: []DAGTopologyNode{{ID: "a"}, {ID: "a"}}}},
+		{name: "unknown_node", topology: DAGTopology{Nodes: []DAGTopologyNode{{ID: "a"}}, Edges: []DAGTopologyEdge{{From: "a", To: "b"}}}},
+		{name: "multiple_entries", topology: DAGTopology{Nodes: []DAGTopologyNode{{ID: "a"}, {ID: "b"}}}},
+		{name: "conflicting_join_edges", topology: DAGTopology{Nodes: []DAGTopologyNode{{ID: "a"}, {ID: "b"}, {ID: "c"}},
+			Edges: []DAGTopologyEdge{{From: "a", To: "b"}, {From: "a", To: "c", FanOut: 2}, {From: "b", To: "c"}}}},
+		{name: "invalid_probability", topology: DAGTopology{Nodes: []DAGTopologyNode{{ID: "a"}, {ID: "b"}},
+			Edges: []DAGTopologyEdge{{From: "a", To: "b", Probability: 1.5}}}},
+		{name: "cycle", topology: DAGTopology{Nodes: []DAGTopologyNode{{ID: "a"}, {ID: "b"}, {ID: "c"}},
+			Edges: []DAGTopologyEdge{{From: "b", To: "c"}, {From: "c", To: "b"}}}},
+		{name: "unknown_app", topology: DAGTopology{HashApp: "app-x", Nodes: []DAGTopologyNode{{ID: "a"}}}},
+		{name: "unknown_function", topology: DAGTopology{Nodes: []DAGTopologyNode{{ID: "a", HashFunction: "func-x"}}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := createTopologyWorkflow(&test.topology, createTopologyTestFunctions(), make(map[*common.Function]bool))
+			if err == nil {
+				t.Error("Expected an error for the invalid topology.")
+			}
+		})
+	}
+}
+
+func TestInvalidDOTTopologies(t *testing.T) {
+	tests := []struct {
+		name     string
+		topology string
+		error    string
+	}{
+		{name: "fanout", topology: "digraph g { a -> b [fanout = many] }", error: "invalid fanout 'many' of a -> b"},
+		{name: "payload", topology: "digraph g { a -> b -> c [payload = 1.5] }", error: "invalid payload '1.5' of a -> b -> c"},
+		{name: "probability", topology: "digraph g { a -> b [probability = half] }", error: "invalid probability 'half' of a -> b"},
+		{name: "choice", topology: "digraph g { a [choice = maybe] }", error: "invalid choice 'maybe' of node a"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := parseDOTTopologies(test.topology)
+			if err == nil || err.Error() != test.error {
+				t.Errorf("Expected error '%s', got %v.", test.error, err)
+			}
+		})
+	}
+}
+
+func createExportTestFunctions(count int) []*common.Function {
+	var result []*common.Function
+	for i := 0; i < count; i++ {
+		result = append(result, &common.Function{
+			Name:            fmt.Sprintf("trace-func-%d", i),
+			InvocationStats: &common.FunctionInvocationStats{HashApp: fmt.Sprintf("app-%d", i%3), HashFunction: fmt.Sprintf("func-%d", i)},
+		})
+	}
+
+	return result
+}
+
+func TestDeterministicDAGGeneration(t *testing.T) {
+	cfg := config.LoaderConfiguration{DAGMode: true, Width: 4, Depth: 4, Seed: 42}
+	functionList := createExportTestFunctions(100)
+
+	generate := func() []DAGTopology {
+		var result []DAGTopology
+		for _, dag := range GenerateDAGs(&cfg, functionList, true) {
+			result = append(result, dagToTopology(dag))
+		}
+		return result
+	}
+
+	first := generate()
+	if len(first) == 0 {
+		t.Fatal("No DAGs generated.")
+	}
+	if second := generate(); !reflect.DeepEqual(first, second) {
+		t.Error("Expected the same DAGs for the same seed.")
+	}
+}
+
+func TestExportAndReplayDAGs(t *testing.T) {
+	functionList := createExportTestFunctions(100)
+	cfg := config.LoaderConfiguration{DAGMode: true, Width: 3, Depth: 3, Seed: 7}
+	dags := GenerateDAGs(&cfg, functionList, true)
+
+	var expected []DAGTopology
+	for _, dag := range dags {
+		expected = append(expected, dagToTopology(dag))
+	}
+
+	dir := t.TempDir()
+	for _, name := range []string{"dags.json", "dags.dot"} {
+		t.Run(name, func(t *testing.T) {
+			replayCfg := cfg
+			replayCfg.DAGExportPath = filepath.Join(dir, name)
+			GenerateDAGs(&replayCfg, functionList, true)
+
+			// the exported file replays the same DAGs on the same functions
+			replayCfg.DAGTopologyPath, replayCfg.DAGExportPath = replayCfg.DAGExportPath, ""
+			replayed := GenerateDAGs(&replayCfg, functionList, true)
+			if len(replayed) != len(dags) {
+				t.Fatalf("Expected %d replayed DAGs, got %d.", len(dags), len(replayed))
+			}
+
+			for i, dag := range replayed {
+				if topology := dagToTopology(dag); !reflect.DeepEqual(topology, expected[i]) {
+					t.Errorf("Replayed DAG %d differs: %+v, expected %+v.", i, topology, expected[i])
+				}
+
+				var width int64 = 1
+				replayedWidth, replayedDepth := GetDAGShape(dag, &width, 0)
+				width = 1
+				if originalWidth, originalDepth := GetDAGShape(dags[i], &width, 0); replayedWidth != originalWidth || replayedDepth != originalDepth {
+					t.Errorf("Replayed DAG %d has a different shape.", i)
+				}
+			}
+		})
+	}
+}
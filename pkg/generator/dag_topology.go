@@ -0,0 +1,718 @@
+package generator
+
+import (
+	"container/list"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+// DAGTopology is an explicit DAG workflow, e.g., a real application of the trace
+type DAGTopology struct {
+	Name string `json:"Name"`
+	// the nodes are mapped to the functions of this application of the trace, or to unused functions if empty
+	HashApp string            `json:"HashApp"`
+	Nodes   []DAGTopologyNode `json:"Nodes"`
+	Edges   []DAGTopologyEdge `json:"Edges"`
+}
+
+type DAGTopologyNode struct {
+	ID string `json:"ID"`
+	// the node is mapped to the function with this trace hash, or to the next function of the application if empty
+	HashFunction string `json:"HashFunction"`
+	// exactly one of the children is invoked, drawn with the probabilities of the edges as weights
+	Choice bool `json:"Choice"`
+}
+
+type DAGTopologyEdge struct {
+	From string `json:"From"`
+	To   string `json:"To"`
+	// number of parallel invocations of the child per invocation of the parent, 1 if 0
+	FanOut int `json:"FanOut"`
+	// request payload size of the invocations of the child, drawn from the payload distribution if 0
+	PayloadBytes int `json:"PayloadBytes"`
+	// probability of invoking the child per invocation of the parent, 1 if 0
+	Probability float64 `json:"Probability"`
+}
+
+type DAGTopologies struct {
+	DAGs []DAGTopology `json:"DAGs"`
+}
+
+// ReadDAGTopologies reads the DAG topologies from a DOT file if the extension is .dot or .gv, and from a JSON file
+// otherwise.
+func ReadDAGTopologies(path string) []DAGTopology {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatalf("Failed to read DAG topologies: %v", err)
+	}
+
+	var topologies []DAGTopology
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".dot", ".gv":
+		topologies, err = parseDOTTopologies(string(data))
+		if err != nil {
+			log.Fatalf("Failed to parse DAG topologies DOT: %v", err)
+		}
+	default:
+		var parsed DAGTopologies
+		if err = json.Unmarshal(data, &parsed); err != nil {
+			log.Fatalf("Failed to unmarshal DAG topologies json: %v", err)
+		}
+		topologies = parsed.DAGs
+	}
+
+	for i := range topologies {
+		if topologies[i].Name == "" {
+			topologies[i].Name = strconv.Itoa(i)
+		}
+	}
+
+	return topologies
+}
+
+// GenerateDAGsFromTopologies creates a DAG workflow for each topology, mapping the nodes to the trace functions
+func GenerateDAGsFromTopologies(topologies []DAGTopology, functions []*common.Function) []*list.List {
+	used := make(map[*common.Function]bool)
+
+	var result []*list.List
+	for _, topology := range topologies {
+		dag, err := createTopologyWorkflow(&topology, functions, used)
+		if err != nil {
+			log.Fatalf("Invalid topology of DAG %s - %v", topology.Name, err)
+		}
+
+		result = append(result, dag)
+	}
+
+	log.Infof("DAGs created: %d, Total Functions used: %d, Functions Unused: %d", len(result), len(used), len(functions)-len(used))
+
+	return result
+}
+
+// mapTopologyFunctions maps the nodes of the topology to the functions, preferring the functions of the application of
+// the topology in the trace order
+func mapTopologyFunctions(topology *DAGTopology, functions []*common.Function, used map[*common.Function]bool) (map[string]*common.Function, error) {
+	var candidates []*common.Function
+	byHash := make(map[string]*common.Function)
+	for _, function := range functions {
+		hashApp, hashFunction := "", ""
+		if function.InvocationStats != nil {
+			hashApp, hashFunction = function.InvocationStats.HashApp, function.InvocationStats.HashFunction
+		}
+
+		if topology.HashApp != "" && hashApp != topology.HashApp {
+			continue
+		}
+
+		byHash[hashFunction] = function
+		if topology.HashApp != "" || !used[function] {
+			candidates = append(candidates, function)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no functions of application '%s' are available", topology.HashApp)
+	}
+
+	result := make(map[string]*common.Function)
+	next := 0
+	for _, node := range topology.Nodes {
+		var function *common.Function
+		if node.HashFunction != "" {
+			function = byHash[node.HashFunction]
+			if function == nil {
+				return nil, fmt.Errorf("function '%s' of node %s is not in the trace", node.HashFunction, node.ID)
+			}
+		} else {
+			if next == len(candidates) {
+				log.Warnf("Functions of DAG %s are reused as the topology has more nodes than functions.", topology.Name)
+			}
+
+			function = candidates[next%len(candidates)]
+			next++
+		}
+
+		result[node.ID] = function
+		used[function] = true
+	}
+
+	return result, nil
+}
+
+func createTopologyWorkflow(topology *DAGTopology, functions []*common.Function, used map[*common.Function]bool) (*list.List, error) {
+	if len(topology.Nodes) == 0 {
+		return nil, fmt.Errorf("no nodes")
+	}
+
+	nodes := make(map[string]bool)
+	for _, node := range topology.Nodes {
+		if node.ID == "" || nodes[node.ID] {
+			return nil, fmt.Errorf("node IDs should be unique and non-empty - '%s'", node.ID)
+		}
+		nodes[node.ID] = true
+	}
+
+	children := make(map[string][]string)
+	parents := make(map[string][]*DAGTopologyEdge)
+	for i := range topology.Edges {
+		edge := &topology.Edges[i]
+		if !nodes[edge.From] || !nodes[edge.To] {
+			return nil, fmt.Errorf("edge %s -> %s connects unknown nodes", edge.From, edge.To)
+		}
+		if edge.FanOut < 0 || edge.PayloadBytes < 0 {
+			return nil, fmt.Errorf("edge %s -> %s has negative fan-out or payload size", edge.From, edge.To)
+		}
+		if edge.Probability < 0 || edge.Probability > 1 {
+			return nil, fmt.Errorf("edge %s -> %s has probability outside of [0, 1]", edge.From, edge.To)
+		}
+		if len(parents[edge.To]) > 0 {
+			first := parents[edge.To][0]
+			if first.FanOut != edge.FanOut || first.PayloadBytes != edge.PayloadBytes || first.Probability != edge.Probability {
+				return nil, fmt.Errorf("edges into node %s set different fan-out, payload sizes or probabilities", edge.To)
+			}
+		}
+
+		parents[edge.To] = append(parents[edge.To], edge)
+		children[edge.From] = append(children[edge.From], edge.To)
+	}
+
+	var roots []string
+	for _, node := range topology.Nodes {
+		if len(parents[node.ID]) == 0 {
+			roots = append(roots, node.ID)
+		}
+	}
+	if len(roots) != 1 {
+		return nil, fmt.Errorf("expected a single entry node, found %d", len(roots))
+	}
+
+	// topological order, where the depth of a node is the length of the longest path from the entry node
+	depth := map[string]int{roots[0]: 0}
+	inDegree := make(map[string]int)
+	for id, edges := range parents {
+		inDegree[id] = len(edges)
+	}
+	queue, sorted := []string{roots[0]}, 0
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		sorted++
+
+		for _, child := range children[id] {
+			depth[child] = max(depth[child], depth[id]+1)
+			if inDegree[child]--; inDegree[child] == 0 {
+				queue = append(queue, child)
+			}
+		}
+	}
+	if sorted != len(topology.Nodes) {
+		return nil, fmt.Errorf("the topology contains a cycle")
+	}
+
+	functionOf, err := mapTopologyFunctions(topology, functions, used)
+	if err != nil {
+		return nil, err
+	}
+
+	dagIdentifier := fmt.Sprintf("DAG %s,", topology.Name)
+	dagNodes := make(map[string]*common.Node)
+	for _, node := range topology.Nodes {
+		dagNode := &common.Node{
+			Function: functionOf[node.ID],
+			Depth:    depth[node.ID],
+			DAG:      dagIdentifier,
+			Parents:  len(parents[node.ID]),
+			ID:       node.ID,
+			Choice:   node.Choice,
+		}
+		if len(parents[node.ID]) > 0 {
+			edge := parents[node.ID][0]
+			dagNode.FanOut, dagNode.PayloadBytes, dagNode.Probability = edge.FanOut, edge.PayloadBytes, edge.Probability
+		}
+
+		dagNodes[node.ID] = dagNode
+	}
+
+	// The list of a node continues with its first child having a single parent, while the other children start new
+	// branches. A join node, i.e., a node with multiple parents, starts a branch shared by all its parents.
+	joinBranches := make(map[string]*list.List)
+	var createChain func(id string) *list.List
+	createChain = func(id string) *list.List {
+		chain := list.New()
+		for id != "" {
+			node := dagNodes[id]
+			chain.PushBack(node)
+
+			next := ""
+			for _, child := range children[id] {
+				if next == "" && len(parents[child]) == 1 {
+					next = child
+					continue
+				}
+
+				if len(parents[child]) > 1 {
+					if joinBranches[child] == nil {
+						joinBranches[child] = createChain(child)
+					}
+					node.Branches = append(node.Branches, joinBranches[child])
+				} else {
+					node.Branches = append(node.Branches, createChain(child))
+				}
+			}
+
+			id = next
+		}
+
+		return chain
+	}
+
+	return createChain(roots[0]), nil
+}
+
+//////////////////////////////////////////////////
+// EXPORT
+//////////////////////////////////////////////////
+
+// ExportDAGTopologies writes the DAGs as topologies to a DOT file if the extension is .dot or .gv, and to a JSON file
+// otherwise. The nodes name their functions by the trace hash, so the file can be replayed through DAGTopologyPath.
+func ExportDAGTopologies(path string, dags []*list.List) {
+	topologies := make([]DAGTopology, len(dags))
+	for i, dag := range dags {
+		topologies[i] = dagToTopology(dag)
+	}
+
+	var data []byte
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".dot", ".gv":
+		data = []byte(formatDOTTopologies(topologies))
+	default:
+		var err error
+		data, err = json.MarshalIndent(DAGTopologies{DAGs: topologies}, "", "  ")
+		if err != nil {
+			log.Fatalf("Failed to marshal DAG topologies: %v", err)
+		}
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		log.Fatalf("Failed to write DAG topologies: %v", err)
+	}
+}
+
+// dagToTopology converts the DAG workflow to its topology
+func dagToTopology(dag *list.List) DAGTopology {
+	topology := DAGTopology{Name: strings.TrimSuffix(strings.TrimPrefix(dag.Front().Value.(*common.Node).DAG, "DAG "), ",")}
+
+	ids := make(map[*common.Node]string)
+	used := make(map[string]int)
+	addNode := func(node *common.Node) (string, bool) {
+		if id, ok := ids[node]; ok {
+			return id, false
+		}
+
+		id := node.ID
+		if id == "" {
+			id = node.Function.Name
+		}
+		if used[id]++; used[id] > 1 {
+			id = fmt.Sprintf("%s-%d", id, used[id]-1)
+		}
+		ids[node] = id
+
+		hashFunction := ""
+		if node.Function.InvocationStats != nil {
+			hashFunction = node.Function.InvocationStats.HashFunction
+		}
+		topology.Nodes = append(topology.Nodes, DAGTopologyNode{ID: id, HashFunction: hashFunction, Choice: node.Choice})
+
+		return id, true
+	}
+
+	addEdge := func(from string, to string, node *common.Node) {
+		topology.Edges = append(topology.Edges, DAGTopologyEdge{
+			From:         from,
+			To:           to,
+			FanOut:       node.FanOut,
+			PayloadBytes: node.PayloadBytes,
+			Probability:  node.Probability,
+		})
+	}
+
+	// the edge to the next node of the list precedes the edges to the branches, so that the replayed DAG continues
+	// the list with the same child
+	var addBranch func(branch *list.List, parent string)
+	addBranch = func(branch *list.List, parent string) {
+		element := branch.Front()
+		id, added := addNode(element.Value.(*common.Node))
+		if parent != "" {
+			addEdge(parent, id, element.Value.(*common.Node))
+		}
+		if !added {
+			return
+		}
+
+		for element != nil {
+			node := element.Value.(*common.Node)
+
+			next, nextID := element.Next(), ""
+			if next != nil {
+				nextID, _ = addNode(next.Value.(*common.Node))
+				addEdge(id, nextID, next.Value.(*common.Node))
+			}
+
+			for _, child := range node.Branches {
+				addBranch(child, id)
+			}
+
+			element, id = next, nextID
+		}
+	}
+	addBranch(dag, "")
+
+	return topology
+}
+
+func formatDOTTopologies(topologies []DAGTopology) string {
+	var builder strings.Builder
+	for _, topology := range topologies {
+		builder.WriteString(fmt.Sprintf("digraph \"%s\" {\n", topology.Name))
+		if topology.HashApp != "" {
+			builder.WriteString(fmt.Sprintf("  app = \"%s\"\n", topology.HashApp))
+		}
+
+		for _, node := range topology.Nodes {
+			var attributes []string
+			if node.HashFunction != "" {
+				attributes = append(attributes, fmt.Sprintf("function = \"%s\"", node.HashFunction))
+			}
+			if node.Choice {
+				attributes = append(attributes, "choice = true")
+			}
+			builder.WriteString(fmt.Sprintf("  \"%s\"%s\n", node.ID, formatDOTAttributes(attributes)))
+		}
+
+		for _, edge := range topology.Edges {
+			var attributes []string
+			if edge.FanOut > 0 {
+				attributes = append(attributes, fmt.Sprintf("fanout = %d", edge.FanOut))
+			}
+			if edge.PayloadBytes > 0 {
+				attributes = append(attributes, fmt.Sprintf("payload = %d", edge.PayloadBytes))
+			}
+			if edge.Probability > 0 {
+				attributes = append(attributes, fmt.Sprintf("probability = %s", strconv.FormatFloat(edge.Probability, 'g', -1, 64)))
+			}
+			builder.WriteString(fmt.Sprintf("  \"%s\" -> \"%s\"%s\n", edge.From, edge.To, formatDOTAttributes(attributes)))
+		}
+
+		builder.WriteString("}\n")
+	}
+
+	return builder.String()
+}
+
+func formatDOTAttributes(attributes []string) string {
+	if len(attributes) == 0 {
+		return ""
+	}
+
+	return " [" + strings.Join(attributes, ", ") + "]"
+}
+
+//////////////////////////////////////////////////
+// DOT PARSING
+//////////////////////////////////////////////////
+
+// parseDOTTopologies parses the subset of the DOT language needed to describe DAG topologies, i.e., digraphs with node
+// statements carrying the function and choice attributes, edge statements carrying the fanout, payload and probability
+// attributes, and the app graph attribute:
+//
+//	digraph workflow {
+//	  app = "<HashApp>"
+//	  a [function = "<HashFunction>"]
+//	  a -> b -> c
+//	  a -> d [fanout = 4, payload = 1024, probability = 0.5]
+//	}
+func parseDOTTopologies(data string) ([]DAGTopology, error) {
+	p := &dotParser{tokens: tokenizeDOT(data)}
+
+	var result []DAGTopology
+	for !p.done() {
+		topology, err := p.parseGraph()
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, *topology)
+	}
+
+	return result, nil
+}
+
+// parseDOTAttribute parses the attribute of the node or edge, which is zero if not set
+func parseDOTAttribute[T any](attributes map[string]string, name string, element string, parse func(string) (T, error)) (T, error) {
+	value, ok := attributes[name]
+	if !ok {
+		var zero T
+		return zero, nil
+	}
+
+	result, err := parse(value)
+	if err != nil {
+		return result, fmt.Errorf("invalid %s '%s' of %s", name, value, element)
+	}
+
+	return result, nil
+}
+
+func tokenizeDOT(data string) []string {
+	var tokens []string
+	for i := 0; i < len(data); {
+		c := data[i]
+		switch {
+		case unicode.IsSpace(rune(c)):
+			i++
+		case strings.HasPrefix(data[i:], "//") || c == '#':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(data[i:], "/*"):
+			end := strings.Index(data[i+2:], "*/")
+			if end < 0 {
+				return tokens
+			}
+			i += end + 4
+		case strings.HasPrefix(data[i:], "->"):
+			tokens = append(tokens, "->")
+			i += 2
+		case strings.ContainsRune("{}[]=;,", rune(c)):
+			tokens = append(tokens, string(c))
+			i++
+		case c == '"':
+			j := i + 1
+			for j < len(data) && data[j] != '"' {
+				if data[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			tokens = append(tokens, strings.ReplaceAll(data[i:min(j+1, len(data))], `\"`, `"`))
+			i = j + 1
+		default:
+			j := i
+			for j < len(data) && !unicode.IsSpace(rune(data[j])) && !strings.ContainsRune("{}[]=;,\"", rune(data[j])) &&
+				!strings.HasPrefix(data[j:], "->") {
+				j++
+			}
+			tokens = append(tokens, data[i:j])
+			i = j
+		}
+	}
+
+	return tokens
+}
+
+type dotParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *dotParser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *dotParser) peek() string {
+	if p.done() {
+		return ""
+	}
+
+	return p.tokens[p.pos]
+}
+
+func (p *dotParser) next() string {
+	token := p.peek()
+	p.pos++
+
+	return token
+}
+
+func (p *dotParser) expect(token string) error {
+	if actual := p.next(); actual != token {
+		return fmt.Errorf("expected '%s', found '%s'", token, actual)
+	}
+
+	return nil
+}
+
+// id returns the next token as an identifier, without the quotes
+func (p *dotParser) id() (string, error) {
+	token := p.next()
+	if token == "" || strings.ContainsAny(token[:1], "{}[]=;,") || token == "->" {
+		return "", fmt.Errorf("expected an identifier, found '%s'", token)
+	}
+
+	return strings.Trim(token, `"`), nil
+}
+
+func (p *dotParser) parseAttributes() (map[string]string, error) {
+	attributes := make(map[string]string)
+	for p.peek() == "[" {
+		p.next()
+		for p.peek() != "]" {
+			key, err := p.id()
+			if err != nil {
+				return nil, err
+			}
+			if err = p.expect("="); err != nil {
+				return nil, err
+			}
+			value, err := p.id()
+			if err != nil {
+				return nil, err
+			}
+			attributes[strings.ToLower(key)] = value
+
+			if p.peek() == "," || p.peek() == ";" {
+				p.next()
+			}
+		}
+		p.next()
+	}
+
+	return attributes, nil
+}
+
+func (p *dotParser) parseGraph() (*DAGTopology, error) {
+	if strings.EqualFold(p.peek(), "strict") {
+		p.next()
+	}
+	if keyword := p.next(); !strings.EqualFold(keyword, "digraph") {
+		return nil, fmt.Errorf("expected 'digraph', found '%s'", keyword)
+	}
+
+	topology := &DAGTopology{}
+	if p.peek() != "{" {
+		name, err := p.id()
+		if err != nil {
+			return nil, err
+		}
+		topology.Name = name
+	}
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+
+	declared := make(map[string]int)
+	declare := func(id string) int {
+		if index, ok := declared[id]; ok {
+			return index
+		}
+
+		declared[id] = len(topology.Nodes)
+		topology.Nodes = append(topology.Nodes, DAGTopologyNode{ID: id})
+
+		return declared[id]
+	}
+
+	for p.peek() != "}" {
+		if p.done() {
+			return nil, fmt.Errorf("unterminated digraph %s", topology.Name)
+		}
+
+		first, err := p.id()
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case first == "graph" || first == "node" || first == "edge":
+			attributes, err := p.parseAttributes()
+			if err != nil {
+				return nil, err
+			}
+			if app, ok := attributes["app"]; ok && first == "graph" {
+				topology.HashApp = app
+			}
+		case p.peek() == "=":
+			p.next()
+			value, err := p.id()
+			if err != nil {
+				return nil, err
+			}
+			if strings.EqualFold(first, "app") {
+				topology.HashApp = value
+			}
+		default:
+			chain := []string{first}
+			for p.peek() == "->" {
+				p.next()
+				id, err := p.id()
+				if err != nil {
+					return nil, err
+				}
+				chain = append(chain, id)
+			}
+
+			attributes, err := p.parseAttributes()
+			if err != nil {
+				return nil, err
+			}
+
+			for _, id := range chain {
+				declare(id)
+			}
+
+			if len(chain) == 1 {
+				if function, ok := attributes["function"]; ok {
+					topology.Nodes[declared[first]].HashFunction = function
+				}
+				if _, ok := attributes["choice"]; ok {
+					topology.Nodes[declared[first]].Choice, err = parseDOTAttribute(attributes, "choice", "node "+first, strconv.ParseBool)
+					if err != nil {
+						return nil, err
+					}
+				}
+				break
+			}
+
+			edge := strings.Join(chain, " -> ")
+			fanOut, err := parseDOTAttribute(attributes, "fanout", edge, strconv.Atoi)
+			if err != nil {
+				return nil, err
+			}
+			payloadBytes, err := parseDOTAttribute(attributes, "payload", edge, strconv.Atoi)
+			if err != nil {
+				return nil, err
+			}
+			probability, err := parseDOTAttribute(attributes, "probability", edge, func(value string) (float64, error) {
+				return strconv.ParseFloat(value, 64)
+			})
+			if err != nil {
+				return nil, err
+			}
+			for i := 1; i < len(chain); i++ {
+				topology.Edges = append(topology.Edges, DAGTopologyEdge{
+					From:         chain[i-1],
+					To:           chain[i],
+					FanOut:       fanOut,
+					PayloadBytes: payloadBytes,
+					Probability:  probability,
+				})
+			}
+		}
+
+		if p.peek() == ";" {
+			p.next()
+		}
+	}
+	p.next()
+
+	return topology, nil
+}
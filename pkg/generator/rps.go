@@ -2,13 +2,217 @@ package generator
 
 import (
 	"fmt"
+	"github.com/gocarina/gocsv"
 	"github.com/sirupsen/logrus"
 	"github.com/vhive-serverless/loader/pkg/common"
 	"github.com/vhive-serverless/loader/pkg/config"
+	"hash/fnv"
 	"math"
 	"math/rand"
+	"os"
 )
 
+const (
+	RpsProfileConstant   = "constant"
+	RpsProfileRamp       = "ramp"
+	RpsProfileStep       = "step"
+	RpsProfileSinusoidal = "sinusoidal"
+	RpsProfileCSV        = "csv"
+
+	RpsRuntimeConstant    = "constant"
+	RpsRuntimeUniform     = "uniform"
+	RpsRuntimeExponential = "exponential"
+
+	KeepAliveFixed   = "fixed"
+	KeepAliveKnative = "knative"
+
+	// Knative autoscaler defaults
+	knativeStableWindowSeconds           = 60.0
+	knativeScaleToZeroGracePeriodSeconds = 30.0
+
+	// period over which the target RPS of a profile is considered constant
+	rpsProfileStepUs = 10_000.0
+)
+
+// RPSProfile returns the target RPS at the given time since the beginning of the experiment in seconds
+type RPSProfile func(t float64) float64
+
+// RPSProfilePoint is a row of the CSV RPS profile. The target RPS applies from the given minute until the next point.
+type RPSProfilePoint struct {
+	Minute float64 `csv:"minute"`
+	RPS    float64 `csv:"rps"`
+}
+
+// CreateRPSProfile returns the RPS profile of the configuration spanning the given experiment duration in minutes, or
+// nil if the target RPS is constant
+func CreateRPSProfile(cfg *config.LoaderConfiguration, experimentDuration int) (RPSProfile, error) {
+	durationSeconds := float64(experimentDuration * 60)
+
+	switch cfg.RpsProfile {
+	case "", RpsProfileConstant:
+		return nil, nil
+	case RpsProfileRamp:
+		start, end := cfg.RpsStartTarget, cfg.RpsTarget
+
+		return func(t float64) float64 {
+			return start + (end-start)*t/durationSeconds
+		}, nil
+	case RpsProfileStep:
+		if cfg.RpsStepMinutes <= 0 {
+			return nil, fmt.Errorf("RpsStepMinutes should be positive for the step RPS profile")
+		}
+
+		steps := int(math.Ceil(float64(experimentDuration) / float64(cfg.RpsStepMinutes)))
+		increment := 0.0
+		if steps > 1 {
+			increment = (cfg.RpsTarget - cfg.RpsStartTarget) / float64(steps-1)
+		}
+		start, stepSeconds := cfg.RpsStartTarget, float64(cfg.RpsStepMinutes*60)
+
+		return func(t float64) float64 {
+			return start + math.Floor(t/stepSeconds)*increment
+		}, nil
+	case RpsProfileSinusoidal:
+		if cfg.RpsPeriodMinutes <= 0 {
+			return nil, fmt.Errorf("RpsPeriodMinutes should be positive for the sinusoidal RPS profile")
+		}
+		mean, amplitude, periodSeconds := cfg.RpsTarget, cfg.RpsAmplitude, cfg.RpsPeriodMinutes*60
+
+		return func(t float64) float64 {
+			return math.Max(mean+amplitude*math.Sin(2*math.Pi*t/periodSeconds), 0)
+		}, nil
+	case RpsProfileCSV:
+		points, err := readRPSProfilePoints(cfg.RpsProfilePath)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(t float64) float64 {
+			i := 0
+			for i+1 < len(points) && points[i+1].Minute*60 <= t {
+				i++
+			}
+
+			return points[i].RPS
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported RPS profile '%s'", cfg.RpsProfile)
+	}
+}
+
+func readRPSProfilePoints(path string) ([]RPSProfilePoint, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open RPS profile - %v", err)
+	}
+	defer f.Close()
+
+	var points []RPSProfilePoint
+	if err = gocsv.UnmarshalFile(f, &points); err != nil {
+		return nil, fmt.Errorf("failed to parse RPS profile - %v", err)
+	}
+
+	if len(points) == 0 || points[0].Minute != 0 {
+		return nil, fmt.Errorf("RPS profile should start at minute 0")
+	}
+	for i := range points {
+		if points[i].RPS < 0 || (i > 0 && points[i].Minute <= points[i-1].Minute) {
+			return nil, fmt.Errorf("RPS profile should have increasing minutes and non-negative RPS")
+		}
+	}
+
+	return points, nil
+}
+
+// ScaleRPSProfile returns the profile with the target RPS multiplied by the factor
+func ScaleRPSProfile(profile RPSProfile, factor float64) RPSProfile {
+	return func(t float64) float64 {
+		return profile(t) * factor
+	}
+}
+
+// generateTimestampsByProfile returns the times of invocations in μs since the beginning of the experiment. An
+// invocation is issued each time the integral of the target RPS over time reaches the next integer.
+func generateTimestampsByProfile(experimentDuration int, profile RPSProfile) []float64 {
+	totalExperimentDurationUs := float64(experimentDuration * 60_000_000.0)
+
+	var result []float64
+	expected, next := 0.0, 0.0
+	for t := 0.0; t < totalExperimentDurationUs; t += rpsProfileStepUs {
+		rps := math.Max(profile(t/1e6), 0)
+		added := rps * rpsProfileStepUs / 1e6
+
+		for rps > 0 && next <= expected+added {
+			timestamp := t + (next-expected)/rps*1e6
+			if timestamp >= totalExperimentDurationUs {
+				break
+			}
+
+			result = append(result, timestamp)
+			next++
+		}
+
+		expected += added
+	}
+
+	return result
+}
+
+func timestampsToIAT(timestamps []float64) common.IATArray {
+	var result common.IATArray
+	previous := 0.0
+	for _, t := range timestamps {
+		result = append(result, t-previous)
+		previous = t
+	}
+
+	return result
+}
+
+// maxOfRPSProfile returns the maximum target RPS of the profile sampled every second
+func maxOfRPSProfile(experimentDuration int, profile RPSProfile) float64 {
+	result := 0.0
+	for t := 0; t <= experimentDuration*60; t++ {
+		result = math.Max(result, profile(float64(t)))
+	}
+
+	return result
+}
+
+// GenerateWarmStartFunctionByProfile generates the invocations of a single function following the RPS profile
+func GenerateWarmStartFunctionByProfile(experimentDuration int, profile RPSProfile) (common.IATArray, []int) {
+	iat := timestampsToIAT(generateTimestampsByProfile(experimentDuration, profile))
+	count := countNumberOfInvocationsPerMinute(experimentDuration, iat)
+
+	return iat, count
+}
+
+// GenerateColdStartFunctionsByProfile generates invocations following the RPS profile and assigns them to functions
+// in a round-robin fashion. The number of functions is chosen so that each function is invoked at most once per
+// cooldown period at the maximum RPS of the profile, i.e., each invocation is a cold start.
+func GenerateColdStartFunctionsByProfile(experimentDuration int, profile RPSProfile, cooldownSeconds int) ([]common.IATArray, [][]int) {
+	totalFunctions := int(math.Ceil(maxOfRPSProfile(experimentDuration, profile) * float64(cooldownSeconds)))
+	if totalFunctions == 0 {
+		return nil, nil
+	}
+
+	timestamps := make([][]float64, totalFunctions)
+	for i, t := range generateTimestampsByProfile(experimentDuration, profile) {
+		timestamps[i%totalFunctions] = append(timestamps[i%totalFunctions], t)
+	}
+
+	var functions []common.IATArray
+	var countResult [][]int
+	for i := 0; i < totalFunctions; i++ {
+		iat := timestampsToIAT(timestamps[i])
+
+		functions = append(functions, iat)
+		countResult = append(countResult, countNumberOfInvocationsPerMinute(experimentDuration, iat))
+	}
+
+	return functions, countResult
+}
+
 func generateFunctionByRPS(experimentDuration int, rpsTarget float64) common.IATArray {
 	iat := 1000000.0 / float64(rpsTarget) // μs
 
@@ -72,7 +276,46 @@ func GenerateWarmStartFunction(experimentDuration int, rpsTarget float64) (commo
 	return iat, count
 }
 
-// GenerateColdStartFunctions It is recommended that the first 10% of cold starts are discarded from the experiment results for low cold start RPS.
+// KeepAliveSeconds returns for how long an idle function instance is kept alive according to the model, including
+// the safety margin. Functions with the given cooldown are invoked less often than that, so that every invocation is
+// a cold start.
+func KeepAliveSeconds(model *config.KeepAliveModel, cooldownSeconds int) (float64, error) {
+	if model == nil {
+		return float64(cooldownSeconds), nil
+	}
+
+	var keepAlive float64
+	switch model.Model {
+	case KeepAliveFixed, "":
+		keepAlive = model.Seconds
+		if keepAlive == 0 {
+			keepAlive = float64(cooldownSeconds)
+		}
+	case KeepAliveKnative:
+		stableWindow, gracePeriod := model.StableWindowSeconds, model.ScaleToZeroGracePeriodSeconds
+		if stableWindow == 0 {
+			stableWindow = knativeStableWindowSeconds
+		}
+		if gracePeriod == 0 {
+			gracePeriod = knativeScaleToZeroGracePeriodSeconds
+		}
+
+		// the revision is scaled to zero once no request arrives over the stable window, and the last instance is
+		// kept for the longer of the grace and the retention period afterwards
+		keepAlive = stableWindow + max(gracePeriod, model.ScaleToZeroPodRetentionSeconds)
+	default:
+		return 0, fmt.Errorf("unsupported keep-alive model '%s'", model.Model)
+	}
+
+	if keepAlive < 0 || model.MarginPercentage < 0 {
+		return 0, fmt.Errorf("keep-alive and its margin should not be negative")
+	}
+
+	return keepAlive * (1 + model.MarginPercentage/100), nil
+}
+
+// GenerateColdStartFunctions spreads the invocations across as many functions as needed for each function to be
+// invoked at most once per cooldown period.
 func GenerateColdStartFunctions(experimentDuration int, rpsTarget float64, cooldownSeconds int) ([]common.IATArray, [][]int) {
 	iat := 1000000.0 / float64(rpsTarget) // ms
 	totalFunctions := int(math.Ceil(rpsTarget * float64(cooldownSeconds)))
@@ -101,42 +344,105 @@ func GenerateColdStartFunctions(experimentDuration int, rpsTarget float64, coold
 		countResult = append(countResult, count)
 	}
 
-	logrus.Warn("It is recommended that the first 10% of cold starts are discarded from the experiment results for low cold start RPS.")
 	return functions, countResult
 }
 
-func CreateRPSFunctions(cfg *config.LoaderConfiguration, dcfg *config.DirigentConfig, warmFunction common.IATArray, warmFunctionCount []int,
-	coldFunctions []common.IATArray, coldFunctionCount [][]int, yamlPath string) []*common.Function {
+// RPSFunctionClasses returns the function classes of the RPS specification file, or a single class made of the Rps*
+// parameters of the loader configuration if no specification file is given.
+func RPSFunctionClasses(cfg *config.LoaderConfiguration) []config.RPSFunctionClass {
+	specification := config.ReadRPSSpecification(cfg.RpsSpecificationPath)
+	if specification == nil {
+		return []config.RPSFunctionClass{{
+			RpsTarget:                cfg.RpsTarget,
+			ColdStartRatioPercentage: cfg.RpsColdStartRatioPercentage,
+			CooldownSeconds:          cfg.RpsCooldownSeconds,
+			RuntimeDistribution:      RpsRuntimeConstant,
+			RuntimeMs:                cfg.RpsRuntimeMs,
+			MemoryMB:                 cfg.RpsMemoryMB,
+			IterationMultiplier:      cfg.RpsIterationMultiplier,
+		}}
+	}
+
+	if len(specification.Classes) == 0 {
+		logrus.Fatal("RPS specification does not contain any function class.")
+	}
+
+	names := make(map[string]bool)
+	for i := range specification.Classes {
+		class := &specification.Classes[i]
+		if class.Name == "" || names[class.Name] {
+			logrus.Fatalf("Function classes of the RPS specification should have unique non-empty names - '%s'.", class.Name)
+		}
+		names[class.Name] = true
+
+		if class.RuntimeDistribution == "" {
+			class.RuntimeDistribution = RpsRuntimeConstant
+		}
+
+		switch class.RuntimeDistribution {
+		case RpsRuntimeConstant, RpsRuntimeExponential:
+		case RpsRuntimeUniform:
+			if class.RuntimeMaxMs < class.RuntimeMs {
+				logrus.Fatalf("RuntimeMaxMs of the function class %s should not be lower than RuntimeMs.", class.Name)
+			}
+		default:
+			logrus.Fatalf("Unsupported runtime distribution '%s' of the function class %s.", class.RuntimeDistribution, class.Name)
+		}
+	}
+
+	return specification.Classes
+}
+
+// CreateRPSFunctions creates the warm and the cold functions of the given class. Functions of classes from the RPS
+// specification file are prefixed with the class name.
+func CreateRPSFunctions(cfg *config.LoaderConfiguration, dcfg *config.DirigentConfig, class *config.RPSFunctionClass,
+	warmFunction common.IATArray, warmFunctionCount []int, coldFunctions []common.IATArray, coldFunctionCount [][]int, yamlPath string) []*common.Function {
 	var result []*common.Function
 
-	busyLoopFor := ComputeBusyLoopPeriod(cfg.RpsMemoryMB)
+	namePrefix := ""
+	if class.Name != "" {
+		namePrefix = class.Name + "-"
+	}
+
+	image := class.Image
+	if image == "" && dcfg != nil {
+		image = dcfg.RpsImage
+	}
+
+	// distinct seed for each class so that the runtimes of different classes are not correlated
+	seed := fnv.New64a()
+	_, _ = seed.Write([]byte(class.Name))
+	specRand := rand.New(rand.NewSource(cfg.Seed ^ int64(seed.Sum64())))
+
+	busyLoopFor := ComputeBusyLoopPeriod(class.MemoryMB)
 
 	if warmFunction != nil || warmFunctionCount != nil {
 		var dirigentMetadataWarm *common.DirigentMetadata
 		if dcfg != nil {
 			dirigentMetadataWarm = &common.DirigentMetadata{
-				Image:               dcfg.RpsImage,
+				Image:               image,
 				Port:                80,
 				Protocol:            "tcp",
 				ScalingUpperBound:   1024,
 				ScalingLowerBound:   1,
-				IterationMultiplier: cfg.RpsIterationMultiplier,
+				IterationMultiplier: class.IterationMultiplier,
 				IOPercentage:        0,
 			}
 		}
 
 		result = append(result, &common.Function{
-			Name: fmt.Sprintf("warm-function-%d", rand.Int()),
+			// the same in every run, so that the deployment of the warm function can be reused
+			Name: fmt.Sprintf("%swarm-function-0", namePrefix),
 
 			InvocationStats:  &common.FunctionInvocationStats{Invocations: warmFunctionCount},
-			RuntimeStats:     &common.FunctionRuntimeStats{Average: float64(cfg.RpsRuntimeMs)},
-			MemoryStats:      &common.FunctionMemoryStats{Percentile100: float64(cfg.RpsMemoryMB)},
+			RuntimeStats:     &common.FunctionRuntimeStats{Average: meanRuntime(class)},
+			MemoryStats:      &common.FunctionMemoryStats{Percentile100: float64(class.MemoryMB)},
 			DirigentMetadata: dirigentMetadataWarm,
 
 			Specification: &common.FunctionSpecification{
 				IAT:                  warmFunction,
 				PerMinuteCount:       warmFunctionCount,
-				RuntimeSpecification: createRuntimeSpecification(len(warmFunction), cfg.RpsRuntimeMs, cfg.RpsMemoryMB),
+				RuntimeSpecification: createRuntimeSpecification(specRand, len(warmFunction), class),
 			},
 
 			YAMLPath:            yamlPath,
@@ -148,27 +454,28 @@ func CreateRPSFunctions(cfg *config.LoaderConfiguration, dcfg *config.DirigentCo
 		var dirigentMetadataCold *common.DirigentMetadata
 		if dcfg != nil {
 			dirigentMetadataCold = &common.DirigentMetadata{
-				Image:               dcfg.RpsImage,
+				Image:               image,
 				Port:                80,
 				Protocol:            "tcp",
 				ScalingUpperBound:   1,
 				ScalingLowerBound:   0,
-				IterationMultiplier: cfg.RpsIterationMultiplier,
+				IterationMultiplier: class.IterationMultiplier,
 				IOPercentage:        0,
 			}
 		}
 
 		result = append(result, &common.Function{
-			Name: fmt.Sprintf("cold-function-%d-%d", i, rand.Int()),
+			// unique to the run, so that the cold functions never start from the instances of a previous run
+			Name: fmt.Sprintf("%scold-function-%d-%d", namePrefix, i, rand.Int()),
 
 			InvocationStats:  &common.FunctionInvocationStats{Invocations: coldFunctionCount[i]},
-			MemoryStats:      &common.FunctionMemoryStats{Percentile100: float64(cfg.RpsMemoryMB)},
+			MemoryStats:      &common.FunctionMemoryStats{Percentile100: float64(class.MemoryMB)},
 			DirigentMetadata: dirigentMetadataCold,
 
 			Specification: &common.FunctionSpecification{
 				IAT:                  coldFunctions[i],
 				PerMinuteCount:       coldFunctionCount[i],
-				RuntimeSpecification: createRuntimeSpecification(len(coldFunctions[i]), cfg.RpsRuntimeMs, cfg.RpsMemoryMB),
+				RuntimeSpecification: createRuntimeSpecification(specRand, len(coldFunctions[i]), class),
 			},
 
 			YAMLPath:            yamlPath,
@@ -179,12 +486,28 @@ func CreateRPSFunctions(cfg *config.LoaderConfiguration, dcfg *config.DirigentCo
 	return result
 }
 
-func createRuntimeSpecification(count int, runtime, memory int) common.RuntimeSpecificationArray {
+func meanRuntime(class *config.RPSFunctionClass) float64 {
+	if class.RuntimeDistribution == RpsRuntimeUniform {
+		return float64(class.RuntimeMs+class.RuntimeMaxMs) / 2
+	}
+
+	return float64(class.RuntimeMs)
+}
+
+func createRuntimeSpecification(gen *rand.Rand, count int, class *config.RPSFunctionClass) common.RuntimeSpecificationArray {
 	var result common.RuntimeSpecificationArray
 	for i := 0; i < count; i++ {
+		runtime := class.RuntimeMs
+		switch class.RuntimeDistribution {
+		case RpsRuntimeUniform:
+			runtime += gen.Intn(class.RuntimeMaxMs - class.RuntimeMs + 1)
+		case RpsRuntimeExponential:
+			runtime = common.MinOf(common.MaxExecTimeMilli, common.MaxOf(common.MinExecTimeMilli, int(math.Round(gen.ExpFloat64()*float64(class.RuntimeMs)))))
+		}
+
 		result = append(result, common.RuntimeSpecification{
 			Runtime: runtime,
-			Memory:  memory,
+			Memory:  class.MemoryMB,
 		})
 	}
 
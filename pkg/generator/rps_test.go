@@ -2,7 +2,12 @@ package generator
 
 import (
 	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
 	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -404,3 +409,220 @@ func TestColdStartMatrix(t *testing.T) {
 		})
 	}
 }
+
+func TestRPSProfiles(t *testing.T) {
+	profilePath := filepath.Join(t.TempDir(), "profile.csv")
+	if err := os.WriteFile(profilePath, []byte("minute,rps\n0,1\n1,0\n1.5,4\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		testName               string
+		experimentDuration     int
+		cfg                    *config.LoaderConfiguration
+		expectedPerMinuteCount []int
+	}{
+		{
+			testName:               "constant",
+			experimentDuration:     2,
+			cfg:                    &config.LoaderConfiguration{RpsTarget: 1},
+			expectedPerMinuteCount: nil,
+		},
+		{
+			testName:               "ramp_0_to_2rps",
+			experimentDuration:     2,
+			cfg:                    &config.LoaderConfiguration{RpsProfile: RpsProfileRamp, RpsStartTarget: 0, RpsTarget: 2},
+			expectedPerMinuteCount: []int{30, 90},
+		},
+		{
+			testName:               "step_1_to_3rps",
+			experimentDuration:     3,
+			cfg:                    &config.LoaderConfiguration{RpsProfile: RpsProfileStep, RpsStartTarget: 1, RpsTarget: 3, RpsStepMinutes: 1},
+			expectedPerMinuteCount: []int{60, 120, 180},
+		},
+		{
+			testName:               "sinusoidal_2rps",
+			experimentDuration:     2,
+			cfg:                    &config.LoaderConfiguration{RpsProfile: RpsProfileSinusoidal, RpsTarget: 2, RpsAmplitude: 1, RpsPeriodMinutes: 2},
+			expectedPerMinuteCount: []int{158, 82},
+		},
+		{
+			testName:               "csv",
+			experimentDuration:     2,
+			cfg:                    &config.LoaderConfiguration{RpsProfile: RpsProfileCSV, RpsProfilePath: profilePath},
+			expectedPerMinuteCount: []int{60, 120},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			profile, err := CreateRPSProfile(test.cfg, test.experimentDuration)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if test.expectedPerMinuteCount == nil {
+				if profile != nil {
+					t.Error("Constant RPS should not yield a profile.")
+				}
+				return
+			}
+
+			_, count := GenerateWarmStartFunctionByProfile(test.experimentDuration, profile)
+			for i := range test.expectedPerMinuteCount {
+				// tolerate rounding at the minute boundaries
+				if math.Abs(float64(count[i]-test.expectedPerMinuteCount[i])) > 1 {
+					t.Errorf("Unexpected per minute count - got: %v, expected: %v", count, test.expectedPerMinuteCount)
+				}
+			}
+		})
+	}
+}
+
+func TestColdStartFunctionsByProfile(t *testing.T) {
+	cooldownSeconds := 10
+	profile, _ := CreateRPSProfile(&config.LoaderConfiguration{RpsProfile: RpsProfileRamp, RpsStartTarget: 0.5, RpsTarget: 2}, 2)
+
+	matrix, minuteCounts := GenerateColdStartFunctionsByProfile(2, profile, cooldownSeconds)
+	if len(matrix) != 20 || len(minuteCounts) != 20 {
+		t.Fatalf("Unexpected number of functions - got: %d, expected: 20", len(matrix))
+	}
+
+	total := 0
+	for fIndex, iat := range matrix {
+		for i := 1; i < len(iat); i++ {
+			if iat[i] < float64(cooldownSeconds*1_000_000) {
+				t.Errorf("Function %d invoked within the cooldown period - IAT %f", fIndex, iat[i])
+			}
+		}
+
+		for _, count := range minuteCounts[fIndex] {
+			total += count
+		}
+	}
+
+	// the mean RPS of the ramp is 1.25
+	if total < 149 || total > 151 {
+		t.Errorf("Unexpected total number of invocations - got: %d, expected: 150", total)
+	}
+}
+
+func TestInvalidRPSProfiles(t *testing.T) {
+	invalid := []*config.LoaderConfiguration{
+		{RpsProfile: "unknown"},
+		{RpsProfile: RpsProfileStep},
+		{RpsProfile: RpsProfileSinusoidal},
+		{RpsProfile: RpsProfileCSV, RpsProfilePath: "non-existing-file"},
+	}
+
+	for _, cfg := range invalid {
+		if _, err := CreateRPSProfile(cfg, 1); err == nil {
+			t.Errorf("Expected an error while creating profile %+v", cfg)
+		}
+	}
+}
+
+func TestHeterogeneousRPSFunctions(t *testing.T) {
+	specificationPath := filepath.Join(t.TempDir(), "rps_specification.json")
+	specification := `{"Classes": [
+		{"Name": "fast", "RpsTarget": 2, "RuntimeMs": 10, "MemoryMB": 128},
+		{"Name": "slow", "RpsTarget": 1, "ColdStartRatioPercentage": 50, "CooldownSeconds": 10,
+			"RuntimeDistribution": "uniform", "RuntimeMs": 100, "RuntimeMaxMs": 200, "MemoryMB": 512, "Image": "slow:latest"}
+	]}`
+	if err := os.WriteFile(specificationPath, []byte(specification), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.LoaderConfiguration{RpsSpecificationPath: specificationPath, Seed: 42}
+	dcfg := &config.DirigentConfig{RpsImage: "default:latest"}
+
+	classes := RPSFunctionClasses(cfg)
+	if len(classes) != 2 || classes[0].RuntimeDistribution != RpsRuntimeConstant {
+		t.Fatalf("Unexpected function classes %+v.", classes)
+	}
+
+	expected := map[string]struct {
+		warm, cold int
+		image      string
+	}{
+		"fast": {warm: 1, cold: 0, image: "default:latest"},
+		"slow": {warm: 1, cold: 5, image: "slow:latest"},
+	}
+
+	for _, class := range classes {
+		warmStartRPS := class.RpsTarget * (100 - class.ColdStartRatioPercentage) / 100
+		coldStartRPS := class.RpsTarget * class.ColdStartRatioPercentage / 100
+
+		warmFunction, warmStartCount := GenerateWarmStartFunction(1, warmStartRPS)
+		coldFunctions, coldStartCount := GenerateColdStartFunctions(1, coldStartRPS, class.CooldownSeconds)
+
+		functions := CreateRPSFunctions(cfg, dcfg, &class, warmFunction, warmStartCount, coldFunctions, coldStartCount, "")
+
+		warm, cold := 0, 0
+		for _, function := range functions {
+			switch {
+			case function.Name == class.Name+"-warm-function-0":
+				warm++
+			case strings.HasPrefix(function.Name, class.Name+"-cold-function-"):
+				cold++
+			default:
+				t.Errorf("Unexpected name %s of a function of class %s.", function.Name, class.Name)
+			}
+
+			if function.DirigentMetadata.Image != expected[class.Name].image {
+				t.Errorf("Unexpected image %s of function %s.", function.DirigentMetadata.Image, function.Name)
+			}
+
+			for _, spec := range function.Specification.RuntimeSpecification {
+				if spec.Runtime < class.RuntimeMs || spec.Runtime > max(class.RuntimeMs, class.RuntimeMaxMs) || spec.Memory != class.MemoryMB {
+					t.Errorf("Unexpected runtime specification %+v of function %s.", spec, function.Name)
+				}
+			}
+		}
+
+		if warm != expected[class.Name].warm || cold != expected[class.Name].cold {
+			t.Errorf("Expected %d warm and %d cold functions of class %s, got %d and %d.",
+				expected[class.Name].warm, expected[class.Name].cold, class.Name, warm, cold)
+		}
+	}
+}
+
+func TestExponentialRuntimeSpecification(t *testing.T) {
+	class := &config.RPSFunctionClass{RuntimeDistribution: RpsRuntimeExponential, RuntimeMs: 1, MemoryMB: 128}
+
+	// with a mean of 1 ms, about 40% of the samples would round to 0 ms without the lower bound
+	for _, spec := range createRuntimeSpecification(rand.New(rand.NewSource(42)), 1000, class) {
+		if spec.Runtime < common.MinExecTimeMilli || spec.Runtime > common.MaxExecTimeMilli {
+			t.Fatalf("Runtime %d ms out of the execution time bounds.", spec.Runtime)
+		}
+	}
+}
+
+func TestKeepAliveSeconds(t *testing.T) {
+	tests := []struct {
+		name              string
+		model             *config.KeepAliveModel
+		expectedKeepAlive float64
+		expectError       bool
+	}{
+		{name: "cooldown", model: nil, expectedKeepAlive: 10},
+		{name: "fixed_cooldown_with_margin", model: &config.KeepAliveModel{Model: KeepAliveFixed, MarginPercentage: 50}, expectedKeepAlive: 15},
+		{name: "fixed", model: &config.KeepAliveModel{Model: KeepAliveFixed, Seconds: 600}, expectedKeepAlive: 600},
+		{name: "knative_defaults", model: &config.KeepAliveModel{Model: KeepAliveKnative}, expectedKeepAlive: 90},
+		{name: "knative_retention", model: &config.KeepAliveModel{Model: KeepAliveKnative, StableWindowSeconds: 30, ScaleToZeroPodRetentionSeconds: 60}, expectedKeepAlive: 90},
+		{name: "unsupported", model: &config.KeepAliveModel{Model: "aws"}, expectError: true},
+		{name: "negative_margin", model: &config.KeepAliveModel{Model: KeepAliveFixed, MarginPercentage: -10}, expectError: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			keepAlive, err := KeepAliveSeconds(test.model, 10)
+			if (err != nil) != test.expectError {
+				t.Fatalf("Unexpected error %v.", err)
+			}
+
+			if keepAlive != test.expectedKeepAlive {
+				t.Errorf("Expected keep-alive of %.2f s, got %.2f s.", test.expectedKeepAlive, keepAlive)
+			}
+		})
+	}
+}
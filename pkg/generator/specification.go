@@ -32,14 +32,16 @@ import (
 )
 
 type SpecificationGenerator struct {
-	iatRand  *rand.Rand
-	specRand *rand.Rand
+	iatRand     *rand.Rand
+	specRand    *rand.Rand
+	payloadRand *rand.Rand
 }
 
 func NewSpecificationGenerator(seed int64) *SpecificationGenerator {
 	return &SpecificationGenerator{
-		iatRand:  rand.New(rand.NewSource(seed)),
-		specRand: rand.New(rand.NewSource(seed)),
+		iatRand:     rand.New(rand.NewSource(seed)),
+		specRand:    rand.New(rand.NewSource(seed)),
+		payloadRand: rand.New(rand.NewSource(seed)),
 	}
 }
 
@@ -175,6 +177,7 @@ func (s *SpecificationGenerator) GenerateInvocationData(function *common.Functio
 			runtimeArray = append(runtimeArray, s.generateExecutionSpecs(function))
 		}
 	}
+	s.generatePayloadSpecs(function, runtimeArray)
 
 	return &common.FunctionSpecification{
 		IAT:                  iat,
@@ -273,3 +276,55 @@ func (s *SpecificationGenerator) generateExecutionSpecs(function *common.Functio
 		Memory:  memory,
 	}
 }
+
+//////////////////////////////////////////////////
+// PAYLOAD GENERATION
+//////////////////////////////////////////////////
+
+// GeneratePayloadSpec samples a payload size in bytes from the given CDF. Not thread safe.
+func GeneratePayloadSpec(gen *rand.Rand, cdf *common.PayloadCDF) int {
+	if cdf == nil || len(cdf.SizeKB) == 0 {
+		return 0
+	}
+
+	qtl := gen.Float64()
+
+	sizeKB := cdf.SizeKB[len(cdf.SizeKB)-1]
+	for i := 0; i < len(cdf.Probability); i++ {
+		if qtl > cdf.Probability[i] {
+			continue
+		}
+
+		if i == 0 || cdf.Probability[i] == cdf.Probability[i-1] {
+			sizeKB = cdf.SizeKB[i]
+		} else {
+			// linear interpolation between two neighbouring points of the CDF
+			ratio := (qtl - cdf.Probability[i-1]) / (cdf.Probability[i] - cdf.Probability[i-1])
+			sizeKB = cdf.SizeKB[i-1] + ratio*(cdf.SizeKB[i]-cdf.SizeKB[i-1])
+		}
+		break
+	}
+
+	return int(sizeKB * 1024.0)
+}
+
+func (s *SpecificationGenerator) generatePayloadSpecs(function *common.Function, runtimeArray common.RuntimeSpecificationArray) {
+	distribution := function.PayloadDistribution
+	if distribution == nil {
+		return
+	}
+
+	for i := 0; i < len(runtimeArray); i++ {
+		runtimeArray[i].RequestPayloadBytes = GeneratePayloadSpec(s.payloadRand, distribution.Request)
+		runtimeArray[i].ResponsePayloadBytes = GeneratePayloadSpec(s.payloadRand, distribution.Response)
+	}
+}
+
+// GeneratePayloadSpecification draws payload sizes for the already generated runtime specification of the function.
+func (s *SpecificationGenerator) GeneratePayloadSpecification(function *common.Function) {
+	if function.Specification == nil {
+		return
+	}
+
+	s.generatePayloadSpecs(function, function.Specification.RuntimeSpecification)
+}
@@ -836,3 +836,41 @@ func TestGenerateExecutionSpecifications(t *testing.T) {
 		})
 	}
 }
+
+func TestGeneratePayloadSpecifications(t *testing.T) {
+	function := &common.Function{
+		Name: "payload-function",
+		InvocationStats: &common.FunctionInvocationStats{
+			Invocations: []int{1000},
+		},
+		RuntimeStats: testFunction.RuntimeStats,
+		MemoryStats:  testFunction.MemoryStats,
+		PayloadDistribution: &common.PayloadDistribution{
+			Request: &common.PayloadCDF{
+				SizeKB:      []float64{1, 2, 4},
+				Probability: []float64{0, 0.5, 1},
+			},
+		},
+	}
+
+	spec := NewSpecificationGenerator(42).GenerateInvocationData(function, common.Equidistant, false, common.MinuteGranularity)
+	other := NewSpecificationGenerator(42).GenerateInvocationData(function, common.Equidistant, false, common.MinuteGranularity)
+
+	belowMedian := 0
+	for i, s := range spec.RuntimeSpecification {
+		if s.RequestPayloadBytes < 1024 || s.RequestPayloadBytes > 4096 || s.ResponsePayloadBytes != 0 {
+			t.Fatalf("Payload size out of the distribution range: %+v", s)
+		}
+		if s.RequestPayloadBytes != other.RuntimeSpecification[i].RequestPayloadBytes {
+			t.Fatal("Payload sizes are not reproducible with the same seed.")
+		}
+
+		if s.RequestPayloadBytes <= 2048 {
+			belowMedian++
+		}
+	}
+
+	if belowMedian < 400 || belowMedian > 600 {
+		t.Errorf("Payload sizes do not follow the distribution - %d out of 1000 below the median.", belowMedian)
+	}
+}
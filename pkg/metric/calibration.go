@@ -0,0 +1,88 @@
+package metric
+
+import (
+	"math"
+	"sort"
+	"sync"
+)
+
+// DurationCalibrationTolerance is the relative difference between the actual and the requested duration of a
+// function tolerated before the function is considered mis-calibrated.
+const DurationCalibrationTolerance = 0.1
+
+// minimum number of invocations of a function required to judge its calibration
+const durationCalibrationMinSamples = 10
+
+type durationRatio struct {
+	samples int
+	sum     float64
+}
+
+// DurationCalibration compares the duration functions report to the requested one, in order to detect deployments
+// whose busy-loop iterations multiplier does not match the hardware the functions run on.
+type DurationCalibration struct {
+	mutex     sync.Mutex
+	functions map[string]*durationRatio
+}
+
+type MiscalibratedFunction struct {
+	Name    string
+	Samples int
+	// Mean ratio of the actual to the requested duration
+	MeanRatio float64
+}
+
+func NewDurationCalibration() *DurationCalibration {
+	return &DurationCalibration{
+		functions: make(map[string]*durationRatio),
+	}
+}
+
+// Add accounts the record of a successful invocation of the function. Records of functions that do not report the
+// duration are ignored.
+func (c *DurationCalibration) Add(function string, record *ExecutionRecord) {
+	if record.RequestedDuration == 0 || record.ActualDuration == 0 {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	ratio, ok := c.functions[function]
+	if !ok {
+		ratio = &durationRatio{}
+		c.functions[function] = ratio
+	}
+
+	ratio.samples++
+	ratio.sum += float64(record.ActualDuration) / float64(record.RequestedDuration)
+}
+
+// Miscalibrated returns the functions whose mean ratio of the actual to the requested duration differs from 1 by more
+// than the tolerance, sorted by name.
+func (c *DurationCalibration) Miscalibrated(tolerance float64) []MiscalibratedFunction {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var result []MiscalibratedFunction
+	for name, ratio := range c.functions {
+		if ratio.samples < durationCalibrationMinSamples {
+			continue
+		}
+
+		meanRatio := ratio.sum / float64(ratio.samples)
+		if math.Abs(meanRatio-1) > tolerance {
+			result = append(result, MiscalibratedFunction{
+				Name:      name,
+				Samples:   ratio.samples,
+				MeanRatio: meanRatio,
+			})
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
@@ -0,0 +1,27 @@
+package metric
+
+import (
+	"testing"
+)
+
+func TestDurationCalibration(t *testing.T) {
+	calibration := NewDurationCalibration()
+
+	for i := 0; i < 20; i++ {
+		calibration.Add("calibrated", &ExecutionRecord{ExecutionRecordBase: ExecutionRecordBase{RequestedDuration: 1000, ActualDuration: 1050}})
+		calibration.Add("slow", &ExecutionRecord{ExecutionRecordBase: ExecutionRecordBase{RequestedDuration: 1000, ActualDuration: 1500}})
+		calibration.Add("fast", &ExecutionRecord{ExecutionRecordBase: ExecutionRecordBase{RequestedDuration: 1000, ActualDuration: 500}})
+		calibration.Add("no-duration", &ExecutionRecord{ExecutionRecordBase: ExecutionRecordBase{RequestedDuration: 1000}})
+	}
+	for i := 0; i < durationCalibrationMinSamples-1; i++ {
+		calibration.Add("few-samples", &ExecutionRecord{ExecutionRecordBase: ExecutionRecordBase{RequestedDuration: 1000, ActualDuration: 5000}})
+	}
+
+	miscalibrated := calibration.Miscalibrated(DurationCalibrationTolerance)
+	if len(miscalibrated) != 2 ||
+		miscalibrated[0].Name != "fast" || miscalibrated[0].MeanRatio != 0.5 || miscalibrated[0].Samples != 20 ||
+		miscalibrated[1].Name != "slow" || miscalibrated[1].MeanRatio != 1.5 {
+
+		t.Errorf("Unexpected mis-calibrated functions: %+v", miscalibrated)
+	}
+}
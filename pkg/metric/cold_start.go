@@ -0,0 +1,50 @@
+package metric
+
+import (
+	"sync"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+// ColdStartRatioTolerance is the difference between the achieved and the target cold start ratio in percentage points
+// tolerated before the experiment is considered not to match the target.
+const ColdStartRatioTolerance = 5.0
+
+// ColdStartRatio counts the cold starts reported by the functions or the platform
+type ColdStartRatio struct {
+	mutex       sync.Mutex
+	invocations int
+	coldStarts  int
+}
+
+func NewColdStartRatio() *ColdStartRatio {
+	return &ColdStartRatio{}
+}
+
+// Add accounts the record of a successful invocation. Records of the warmup phase, of the cold start warm-up and of
+// invocations whose start type is not reported are ignored.
+func (c *ColdStartRatio) Add(record *ExecutionRecord) {
+	if record.Phase == int(common.WarmupPhase) || record.ColdStartWarmup || !record.StartTypeReported {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.invocations++
+	if record.ColdStart {
+		c.coldStarts++
+	}
+}
+
+// Achieved returns the percentage of cold starts and the number of invocations it has been computed over
+func (c *ColdStartRatio) Achieved() (float64, int) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.invocations == 0 {
+		return 0, 0
+	}
+
+	return float64(c.coldStarts) * 100 / float64(c.invocations), c.invocations
+}
@@ -0,0 +1,42 @@
+package metric
+
+import (
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+func TestColdStartRatio(t *testing.T) {
+	ratio := NewColdStartRatio()
+
+	if achieved, samples := ratio.Achieved(); achieved != 0 || samples != 0 {
+		t.Errorf("Expected no samples, got %.2f%% over %d invocations.", achieved, samples)
+	}
+
+	for i := 0; i < 20; i++ {
+		ratio.Add(&ExecutionRecord{StartTypeReported: true, ContainerStartTime: 1, ColdStart: i%4 == 0})
+
+		// ignored records
+		ratio.Add(&ExecutionRecord{StartTypeReported: true, ColdStart: true, ColdStartWarmup: true})
+		ratio.Add(&ExecutionRecord{ExecutionRecordBase: ExecutionRecordBase{Phase: int(common.WarmupPhase)}, StartTypeReported: true, ColdStart: true})
+		ratio.Add(&ExecutionRecord{})
+	}
+
+	if achieved, samples := ratio.Achieved(); achieved != 25 || samples != 20 {
+		t.Errorf("Expected 25%% cold starts over 20 invocations, got %.2f%% over %d.", achieved, samples)
+	}
+}
+
+func TestColdStartRatioPlatformReported(t *testing.T) {
+	ratio := NewColdStartRatio()
+
+	// reported by the platform, e.g., Lambda or OpenWhisk, or by HTTP functions, without a container start time
+	ratio.Add(&ExecutionRecord{StartTypeReported: true, ColdStart: true})
+	ratio.Add(&ExecutionRecord{StartTypeReported: true})
+	// an asynchronous Lambda invocation, whose start type is unknown
+	ratio.Add(&ExecutionRecord{AsyncResponseID: "request-1"})
+
+	if achieved, samples := ratio.Achieved(); achieved != 50 || samples != 2 {
+		t.Errorf("Expected 50%% cold starts over 2 invocations, got %.2f%% over %d.", achieved, samples)
+	}
+}
@@ -25,6 +25,36 @@ func RunCSVWriter(records chan interface{}, filename string, writerDone *sync.Wa
 	writerDone.Done()
 }
 
+func WriteDAGExecutionRecords(filename string, records []*DAGExecutionRecord) {
+	file, err := os.Create(filename)
+	common.Check(err)
+	defer file.Close()
+
+	if err := gocsv.MarshalCSV(records, gocsv.NewSafeCSVWriter(csv.NewWriter(file))); err != nil {
+		log.Errorf("Failed to write DAG execution records - %v", err)
+	}
+}
+
+func WriteOpenWhiskActivationRecords(filename string, records []*ExecutionRecordOpenWhisk) {
+	file, err := os.Create(filename)
+	common.Check(err)
+	defer file.Close()
+
+	if err := gocsv.MarshalCSV(records, gocsv.NewSafeCSVWriter(csv.NewWriter(file))); err != nil {
+		log.Errorf("Failed to write OpenWhisk activation records - %v", err)
+	}
+}
+
+func WriteAWSLambdaInvocationRecords(filename string, records []*ExecutionRecordAWSLambda) {
+	file, err := os.Create(filename)
+	common.Check(err)
+	defer file.Close()
+
+	if err := gocsv.MarshalCSV(records, gocsv.NewSafeCSVWriter(csv.NewWriter(file))); err != nil {
+		log.Errorf("Failed to write AWS Lambda invocation records - %v", err)
+	}
+}
+
 func CreateGlobalMetricsCollector(filename string, collector chan *ExecutionRecord,
 	signalReady *sync.WaitGroup, signalEverythingWritten *sync.WaitGroup, totalIssuedChannel chan int64) {
 
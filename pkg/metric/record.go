@@ -69,6 +69,22 @@ type ExecutionRecordOpenWhisk struct {
 	InitTime int64 `csv:"initTime"`
 }
 
+type ExecutionRecordAWSLambda struct {
+	ExecutionRecordBase
+
+	RequestID      string    `csv:"requestID"`
+	InvocationType string    `csv:"invocationType"`
+	StatusCode     int       `csv:"statusCode"`
+	FunctionError  string    `csv:"functionError"`
+	StartType      StartType `csv:"startType"`
+
+	// Measurements in microseconds, as reported by Lambda in the log tail of RequestResponse invocations
+	BilledDuration int64 `csv:"billedDuration"`
+	InitDuration   int64 `csv:"initDuration"`
+	// MiB
+	MaxMemoryUsed uint32 `csv:"maxMemoryUsed"`
+}
+
 type ExecutionRecord struct {
 	ExecutionRecordBase
 
@@ -76,11 +92,47 @@ type ExecutionRecord struct {
 	ActualMemoryUsage       uint32 `csv:"actualMemoryUsage"`
 	MemoryAllocationTimeout bool   `csv:"memoryAllocationTimeout"`
 
+	// Payload sizes in bytes
+	BytesSent     int64 `csv:"bytesSent"`
+	BytesReceived int64 `csv:"bytesReceived"`
+
+	// Telemetry reported by functions implementing the v2 Executor protocol. The start type is also reported by the
+	// platforms that expose it, in which case StartTypeReported is set.
+	RequestID          string `csv:"requestID"`
+	ColdStart          bool   `csv:"coldStart"`
+	StartTypeReported  bool   `csv:"startTypeReported"`
+	ContainerStartTime int64  `csv:"containerStartTime"` // Unix time in microseconds
+	CPUTime            int64  `csv:"cpuTime"`            // Microseconds
+	PeakMemoryUsage    uint32 `csv:"peakMemoryUsage"`    // MiB
+
+	// Issued before instances left from the deployment have surely been removed (RPS mode only)
+	ColdStartWarmup bool `csv:"coldStartWarmup"`
+
 	AsyncResponseID     string `csv:"-"`
 	TimeToSubmitMs      int64  `csv:"timeToSubmitMs"`
 	UserCodeExecutionMs int64  `csv:"userCodeExecutionMs"`
 
 	TimeToGetResponseMs int64 `csv:"timeToGetResponseMs"`
+	// Unix time in microseconds of the poll that found the response, i.e., an upper bound of the completion
+	ResponseAvailableTime int64 `csv:"responseAvailableTime"`
+	AsyncPolls            int   `csv:"asyncPolls"`
+	// Microseconds from the submission of the asynchronous invocation until its response is available
+	AsyncLatency int64 `csv:"asyncLatency"`
+}
+
+// DAGExecutionRecord is the end-to-end record of a single invocation of a DAG workflow
+type DAGExecutionRecord struct {
+	Phase        int    `csv:"phase"`
+	DAG          string `csv:"dag"`
+	InvocationID string `csv:"invocationID"`
+	StartTime    int64  `csv:"startTime"` // Unix time in microseconds
+
+	// From the beginning of the invocation of the entry node to the end of the last invocation, in microseconds
+	ResponseTime int64 `csv:"responseTime"`
+	Invocations  int64 `csv:"invocations"`
+	Failed       bool  `csv:"failed"`
+	// nodes taken through the conditional branches, in the depth-first order
+	Path string `csv:"path"`
 }
 
 type DeploymentScale struct {
@@ -0,0 +1,208 @@
+package metric
+
+import (
+	"encoding/csv"
+	"math"
+	"os"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/gocarina/gocsv"
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+// SLOAllFunctions is the function name of verdicts evaluated over all the functions an SLO selects
+const SLOAllFunctions = "*"
+
+type SLOVerdict struct {
+	SLO        string  `csv:"slo"`
+	Function   string  `csv:"function"`
+	Metric     string  `csv:"metric"`
+	Percentile float64 `csv:"percentile"`
+	Threshold  float64 `csv:"threshold"`
+	Value      float64 `csv:"value"`
+	Samples    int     `csv:"samples"`
+	Passed     bool    `csv:"passed"`
+}
+
+type sloSamples struct {
+	hashFunction string
+
+	slowdowns     []float64
+	responseTimes []float64 // ms
+	invocations   int
+	failures      int
+}
+
+// SLOEvaluator collects the invocations of the execution phase and evaluates the SLOs over them
+type SLOEvaluator struct {
+	slos []config.SLO
+
+	mutex     sync.Mutex
+	functions map[string]*sloSamples
+}
+
+func NewSLOEvaluator(slos []config.SLO) *SLOEvaluator {
+	for _, slo := range slos {
+		switch slo.Metric {
+		case config.SLOMetricSlowdown, config.SLOMetricResponseTime:
+			if slo.Percentile <= 0 || slo.Percentile > 100 {
+				log.Fatalf("Invalid percentile %.2f of SLO %s. It should be in (0, 100].", slo.Percentile, slo.Name)
+			}
+		case config.SLOMetricFailureRate:
+		default:
+			log.Fatalf("Unsupported metric '%s' of SLO %s.", slo.Metric, slo.Name)
+		}
+	}
+
+	return &SLOEvaluator{
+		slos:      slos,
+		functions: make(map[string]*sloSamples),
+	}
+}
+
+func (e *SLOEvaluator) Add(function *common.Function, record *ExecutionRecord, success bool) {
+	if len(e.slos) == 0 || record.Phase == int(common.WarmupPhase) || record.ColdStartWarmup {
+		return
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	samples, ok := e.functions[function.Name]
+	if !ok {
+		samples = &sloSamples{}
+		if function.InvocationStats != nil {
+			samples.hashFunction = function.InvocationStats.HashFunction
+		}
+
+		e.functions[function.Name] = samples
+	}
+
+	samples.invocations++
+	if !success {
+		samples.failures++
+		return
+	}
+
+	// the response time of an asynchronous invocation only covers its submission
+	responseTime := record.ResponseTime
+	if record.AsyncLatency > 0 {
+		responseTime = record.AsyncLatency
+	}
+
+	samples.responseTimes = append(samples.responseTimes, float64(responseTime)/1e3)
+	if record.RequestedDuration > 0 {
+		samples.slowdowns = append(samples.slowdowns, float64(responseTime)/float64(record.RequestedDuration))
+	}
+}
+
+func (e *SLOEvaluator) selectFunctions(slo config.SLO) []string {
+	var result []string
+	for name, samples := range e.functions {
+		if len(slo.Functions) == 0 || slices.Contains(slo.Functions, name) ||
+			(samples.hashFunction != "" && slices.Contains(slo.Functions, samples.hashFunction)) {
+
+			result = append(result, name)
+		}
+	}
+
+	sort.Strings(result)
+
+	return result
+}
+
+// percentile returns the nearest-rank percentile of the values
+func percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+
+	return sorted[max(rank, 1)-1]
+}
+
+func evaluateSLO(slo config.SLO, function string, samples []*sloSamples) SLOVerdict {
+	verdict := SLOVerdict{
+		SLO:        slo.Name,
+		Function:   function,
+		Metric:     slo.Metric,
+		Percentile: slo.Percentile,
+		Threshold:  slo.Threshold,
+	}
+
+	var values []float64
+	invocations, failures := 0, 0
+	for _, s := range samples {
+		invocations += s.invocations
+		failures += s.failures
+
+		switch slo.Metric {
+		case config.SLOMetricSlowdown:
+			values = append(values, s.slowdowns...)
+		case config.SLOMetricResponseTime:
+			values = append(values, s.responseTimes...)
+		}
+	}
+
+	if slo.Metric == config.SLOMetricFailureRate {
+		verdict.Samples = invocations
+		if invocations > 0 {
+			verdict.Value = float64(failures) * 100 / float64(invocations)
+		}
+	} else {
+		verdict.Samples = len(values)
+		verdict.Value = percentile(values, slo.Percentile)
+	}
+
+	verdict.Passed = verdict.Value <= verdict.Threshold
+
+	return verdict
+}
+
+// Evaluate returns the verdicts of all the SLOs. An SLO evaluated per function yields one verdict per function.
+func (e *SLOEvaluator) Evaluate() []SLOVerdict {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	var result []SLOVerdict
+	for _, slo := range e.slos {
+		selected := e.selectFunctions(slo)
+		if len(selected) == 0 {
+			log.Warnf("No invocations of the functions SLO %s applies to have been recorded.", slo.Name)
+		}
+
+		if slo.PerFunction {
+			for _, name := range selected {
+				result = append(result, evaluateSLO(slo, name, []*sloSamples{e.functions[name]}))
+			}
+		} else {
+			samples := make([]*sloSamples, 0, len(selected))
+			for _, name := range selected {
+				samples = append(samples, e.functions[name])
+			}
+
+			result = append(result, evaluateSLO(slo, SLOAllFunctions, samples))
+		}
+	}
+
+	return result
+}
+
+func WriteSLOVerdicts(filename string, verdicts []SLOVerdict) {
+	file, err := os.Create(filename)
+	common.Check(err)
+	defer file.Close()
+
+	if err := gocsv.MarshalCSV(verdicts, gocsv.NewSafeCSVWriter(csv.NewWriter(file))); err != nil {
+		log.Errorf("Failed to write SLO verdicts - %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+package metric
+
+import (
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+func TestSLOEvaluator(t *testing.T) {
+	evaluator := NewSLOEvaluator([]config.SLO{
+		{Name: "global-slowdown", Metric: config.SLOMetricSlowdown, Percentile: 99, Threshold: 3},
+		{Name: "fast-latency", Metric: config.SLOMetricResponseTime, Percentile: 50, Threshold: 15, Functions: []string{"hash-fast"}},
+		{Name: "failures", Metric: config.SLOMetricFailureRate, Threshold: 5, PerFunction: true},
+	})
+
+	fast := &common.Function{Name: "fast", InvocationStats: &common.FunctionInvocationStats{HashFunction: "hash-fast"}}
+	slow := &common.Function{Name: "slow"}
+
+	for i := 1; i <= 100; i++ {
+		// 10ms requested, response time between 10 and 20ms
+		evaluator.Add(fast, &ExecutionRecord{ExecutionRecordBase: ExecutionRecordBase{
+			Phase:             int(common.ExecutionPhase),
+			RequestedDuration: 10_000,
+			ResponseTime:      int64(10_000 + i*100),
+		}}, true)
+
+		// 10% of failed invocations
+		evaluator.Add(slow, &ExecutionRecord{ExecutionRecordBase: ExecutionRecordBase{
+			Phase:             int(common.ExecutionPhase),
+			RequestedDuration: 10_000,
+			ResponseTime:      20_000,
+		}}, i%10 != 0)
+
+		// warmup invocations are not accounted
+		evaluator.Add(slow, &ExecutionRecord{ExecutionRecordBase: ExecutionRecordBase{
+			Phase:             int(common.WarmupPhase),
+			RequestedDuration: 10_000,
+			ResponseTime:      1_000_000,
+		}}, false)
+	}
+
+	expected := []SLOVerdict{
+		{SLO: "global-slowdown", Function: SLOAllFunctions, Metric: config.SLOMetricSlowdown, Percentile: 99, Threshold: 3, Value: 2, Samples: 190, Passed: true},
+		{SLO: "fast-latency", Function: SLOAllFunctions, Metric: config.SLOMetricResponseTime, Percentile: 50, Threshold: 15, Value: 15, Samples: 100, Passed: true},
+		{SLO: "failures", Function: "fast", Metric: config.SLOMetricFailureRate, Threshold: 5, Value: 0, Samples: 100, Passed: true},
+		{SLO: "failures", Function: "slow", Metric: config.SLOMetricFailureRate, Threshold: 5, Value: 10, Samples: 100, Passed: false},
+	}
+
+	verdicts := evaluator.Evaluate()
+	if len(verdicts) != len(expected) {
+		t.Fatalf("Expected %d verdicts, got %+v", len(expected), verdicts)
+	}
+	for i := range expected {
+		if verdicts[i] != expected[i] {
+			t.Errorf("Unexpected verdict %+v, expected %+v", verdicts[i], expected[i])
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package trace
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+// AutoscalingParser attaches Knative autoscaling settings to functions. Settings are taken from the optional
+// autoscaling.json trace file and from the autoscaling configuration file, and are merged field by field. The
+// default of the configuration is overridden by the trace, then by the classes and finally by the per-function entries
+// of the configuration.
+type AutoscalingParser struct {
+	directoryPath string
+	configPath    string
+	functions     []*common.Function
+}
+
+func NewAutoscalingParser(directoryPath string, configPath string, functions []*common.Function) *AutoscalingParser {
+	return &AutoscalingParser{
+		directoryPath: directoryPath,
+		configPath:    configPath,
+		functions:     functions,
+	}
+}
+
+func parseAutoscalingTrace(traceFile string) *[]common.AutoscalingSettings {
+	data, err := os.ReadFile(traceFile)
+	if err != nil {
+		return nil
+	}
+
+	log.Infof("Parsing function autoscaling settings: %s", traceFile)
+
+	var settings []common.AutoscalingSettings
+	err = json.Unmarshal(data, &settings)
+	if err != nil {
+		log.Fatalf("Failed to parse trace autoscaling settings - %v", err)
+	}
+
+	return &settings
+}
+
+func createAutoscalingMap(settings *[]common.AutoscalingSettings) map[string]*common.AutoscalingSettings {
+	result := make(map[string]*common.AutoscalingSettings)
+
+	for i := 0; i < len(*settings); i++ {
+		result[(*settings)[i].HashFunction] = &(*settings)[i]
+	}
+
+	return result
+}
+
+func overrideSetting[T any](setting **T, override *T) {
+	if override != nil {
+		*setting = override
+	}
+}
+
+// mergeAutoscalingSettings returns the settings with the fields set in the override replaced
+func mergeAutoscalingSettings(settings *common.AutoscalingSettings, override *common.AutoscalingSettings) *common.AutoscalingSettings {
+	if override == nil {
+		return settings
+	}
+
+	merged := common.AutoscalingSettings{}
+	if settings != nil {
+		merged = *settings
+	}
+
+	overrideSetting(&merged.Class, override.Class)
+	overrideSetting(&merged.Metric, override.Metric)
+	overrideSetting(&merged.Target, override.Target)
+	overrideSetting(&merged.TargetUtilizationPercentage, override.TargetUtilizationPercentage)
+	overrideSetting(&merged.StableWindowSeconds, override.StableWindowSeconds)
+	overrideSetting(&merged.ScaleToZeroPodRetentionSeconds, override.ScaleToZeroPodRetentionSeconds)
+	overrideSetting(&merged.MinScale, override.MinScale)
+	overrideSetting(&merged.MaxScale, override.MaxScale)
+	overrideSetting(&merged.InitialScale, override.InitialScale)
+	overrideSetting(&merged.ContainerConcurrency, override.ContainerConcurrency)
+
+	return &merged
+}
+
+// classAutoscalingSettings returns the settings of the longest class prefixing the name of the function
+func classAutoscalingSettings(classes map[string]*common.AutoscalingSettings, name string) *common.AutoscalingSettings {
+	var settings *common.AutoscalingSettings
+	longest := -1
+
+	for class, s := range classes {
+		if strings.HasPrefix(name, class+"-") && len(class) > longest {
+			settings = s
+			longest = len(class)
+		}
+	}
+
+	return settings
+}
+
+func validateAutoscalingSettings(settings *common.AutoscalingSettings) error {
+	if settings.Class != nil && *settings.Class != "kpa.autoscaling.knative.dev" && *settings.Class != "hpa.autoscaling.knative.dev" {
+		return fmt.Errorf("unknown autoscaler class '%s'", *settings.Class)
+	}
+	if settings.Target != nil && *settings.Target <= 0 {
+		return fmt.Errorf("the target must be positive")
+	}
+	if settings.TargetUtilizationPercentage != nil && (*settings.TargetUtilizationPercentage <= 0 || *settings.TargetUtilizationPercentage > 100) {
+		return fmt.Errorf("the target utilization must be in (0, 100]")
+	}
+	// bounds imposed by Knative
+	if settings.StableWindowSeconds != nil && (*settings.StableWindowSeconds < 6 || *settings.StableWindowSeconds > 3600) {
+		return fmt.Errorf("the stable window must be between 6 and 3600 seconds")
+	}
+
+	for name, value := range map[string]*int{
+		"scale-to-zero pod retention": settings.ScaleToZeroPodRetentionSeconds,
+		"minimum scale":               settings.MinScale,
+		"maximum scale":               settings.MaxScale,
+		"initial scale":               settings.InitialScale,
+		"container concurrency":       settings.ContainerConcurrency,
+	} {
+		if value != nil && *value < 0 {
+			return fmt.Errorf("the %s must be non-negative", name)
+		}
+	}
+
+	// a maximum scale of 0 means unlimited
+	if settings.MinScale != nil && settings.MaxScale != nil && *settings.MaxScale > 0 && *settings.MinScale > *settings.MaxScale {
+		return fmt.Errorf("the minimum scale must not exceed the maximum scale")
+	}
+
+	return nil
+}
+
+func (ap *AutoscalingParser) Parse() {
+	var autoscalingByHashFunction map[string]*common.AutoscalingSettings
+	if ap.directoryPath != "" {
+		if autoscalingTrace := parseAutoscalingTrace(ap.directoryPath + "/autoscaling.json"); autoscalingTrace != nil {
+			autoscalingByHashFunction = createAutoscalingMap(autoscalingTrace)
+		}
+	}
+
+	autoscalingConfig := config.ReadAutoscalingConfig(ap.configPath)
+
+	for _, function := range ap.functions {
+		var settings *common.AutoscalingSettings
+
+		if autoscalingConfig != nil {
+			settings = mergeAutoscalingSettings(settings, autoscalingConfig.Default)
+		}
+		if function.InvocationStats != nil {
+			settings = mergeAutoscalingSettings(settings, autoscalingByHashFunction[function.InvocationStats.HashFunction])
+		}
+		if autoscalingConfig != nil {
+			settings = mergeAutoscalingSettings(settings, classAutoscalingSettings(autoscalingConfig.Classes, function.Name))
+			if function.InvocationStats != nil {
+				settings = mergeAutoscalingSettings(settings, autoscalingConfig.Functions[function.InvocationStats.HashFunction])
+			}
+			settings = mergeAutoscalingSettings(settings, autoscalingConfig.Functions[function.Name])
+		}
+
+		if settings != nil {
+			if err := validateAutoscalingSettings(settings); err != nil {
+				log.Fatalf("Invalid autoscaling settings of %s - %v", function.Name, err)
+			}
+		}
+		function.Autoscaling = settings
+	}
+}
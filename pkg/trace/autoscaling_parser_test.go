@@ -0,0 +1,71 @@
+package trace
+
+import (
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+func TestAutoscalingParser(t *testing.T) {
+	functions := []*common.Function{
+		{
+			Name: "trace-function",
+			InvocationStats: &common.FunctionInvocationStats{
+				HashFunction: "c13acdc7567b225971cef2416a3a2b03c8a4d8d154df48afe75834e2f5c59ddf",
+			},
+		},
+		{
+			Name: "cold-function-0-1",
+		},
+		{
+			Name: "default-function",
+		},
+	}
+
+	parser := NewAutoscalingParser("test_data", "test_data/autoscaling_config.json", functions)
+	parser.Parse()
+
+	// the trace overrides the default, the function entry adds the container concurrency
+	s0 := functions[0].Autoscaling
+	if s0 == nil || *s0.Target != 10 || *s0.MaxScale != 5 || *s0.StableWindowSeconds != 60 ||
+		*s0.ContainerConcurrency != 0 || s0.Metric != nil {
+
+		t.Error("Unexpected autoscaling settings of the trace function.")
+	}
+
+	// the longest class wins over the default
+	s1 := functions[1].Autoscaling
+	if s1 == nil || *s1.Metric != "rps" || *s1.Target != 50 || *s1.StableWindowSeconds != 60 ||
+		s1.ScaleToZeroPodRetentionSeconds != nil || s1.MaxScale != nil {
+
+		t.Error("Unexpected autoscaling settings of the class.")
+	}
+
+	s2 := functions[2].Autoscaling
+	if s2 == nil || *s2.Target != 1 || *s2.StableWindowSeconds != 60 || s2.Metric != nil {
+		t.Error("Unexpected default autoscaling settings.")
+	}
+}
+
+func TestValidateAutoscalingSettings(t *testing.T) {
+	class, window, negative, minScale, maxScale, utilization := "other", 5, -1, 3, 2, 120.0
+
+	invalid := []*common.AutoscalingSettings{
+		{Class: &class},
+		{StableWindowSeconds: &window},
+		{ContainerConcurrency: &negative},
+		{MinScale: &minScale, MaxScale: &maxScale},
+		{TargetUtilizationPercentage: &utilization},
+	}
+
+	for _, settings := range invalid {
+		if validateAutoscalingSettings(settings) == nil {
+			t.Errorf("Expected settings %+v to be invalid.", *settings)
+		}
+	}
+
+	unlimited := 0
+	if validateAutoscalingSettings(&common.AutoscalingSettings{MinScale: &minScale, MaxScale: &unlimited}) != nil {
+		t.Error("Expected settings to be valid.")
+	}
+}
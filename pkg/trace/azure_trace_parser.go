@@ -32,7 +32,6 @@ import (
 	"os"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/gocarina/gocsv"
 	"github.com/vhive-serverless/loader/pkg/common"
@@ -45,21 +44,29 @@ type Parser interface {
 	Parse() []*common.Function
 }
 type AzureTraceParser struct {
-	DirectoryPath         string
-	yamlPath              string
-	duration              int
-	functionNameGenerator *rand.Rand
+	DirectoryPath string
+	yamlPath      string
+	duration      int
 }
 
 func NewAzureParser(directoryPath string, totalDuration int, yamlPath string) *AzureTraceParser {
 	return &AzureTraceParser{
-		DirectoryPath:         directoryPath,
-		yamlPath:              yamlPath,
-		duration:              totalDuration,
-		functionNameGenerator: rand.New(rand.NewSource(time.Now().UnixNano())),
+		DirectoryPath: directoryPath,
+		yamlPath:      yamlPath,
+		duration:      totalDuration,
 	}
 }
 
+// traceFunctionName derives the name of a function from its hashes in the trace, so that a trace always yields the same
+// names and the functions deployed by a previous run can be reused
+func traceFunctionName(prefix string, index int, invocationStats *common.FunctionInvocationStats) string {
+	return fmt.Sprintf("%s-%d-%d", prefix, index, traceFunctionHash(invocationStats))
+}
+
+func traceFunctionHash(invocationStats *common.FunctionInvocationStats) uint64 {
+	return common.Hash(invocationStats.HashOwner + invocationStats.HashApp + invocationStats.HashFunction)
+}
+
 func createRuntimeMap(runtime *[]common.FunctionRuntimeStats) map[string]*common.FunctionRuntimeStats {
 	result := make(map[string]*common.FunctionRuntimeStats)
 
@@ -96,13 +103,13 @@ func (p *AzureTraceParser) extractFunctions(invocations *[]common.FunctionInvoca
 	runtimeByHashFunction := createRuntimeMap(runtime)
 	memoryByHashFunction := createMemoryMap(memory)
 
-	gen := rand.New(rand.NewSource(time.Now().UnixNano()))
-
 	for i := 0; i < len(*invocations); i++ {
 		invocationStats := (*invocations)[i]
+		// seeded by the function so that the busy loop, which is part of the deployed service, is the same in every run
+		gen := rand.New(rand.NewSource(int64(traceFunctionHash(&invocationStats))))
 
 		function := &common.Function{
-			Name: fmt.Sprintf("%s-%d-%d", common.FunctionNamePrefix, i, p.functionNameGenerator.Uint64()),
+			Name: traceFunctionName(common.FunctionNamePrefix, i, &invocationStats),
 
 			InvocationStats:     &invocationStats,
 			RuntimeStats:        runtimeByHashFunction[invocationStats.HashFunction],
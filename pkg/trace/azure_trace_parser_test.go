@@ -25,6 +25,7 @@
 package trace
 
 import (
+	"fmt"
 	"github.com/vhive-serverless/loader/pkg/common"
 	"math"
 	"strings"
@@ -134,3 +135,17 @@ func TestParserWrapper(t *testing.T) {
 		t.Error("Unexpected results.")
 	}
 }
+
+func TestDeterministicFunctionNames(t *testing.T) {
+	first := NewAzureParser("test_data", 10, "workloads/container/trace_func_go.yaml").Parse()
+	second := NewAzureParser("test_data", 10, "workloads/container/trace_func_go.yaml").Parse()
+
+	stats := first[0].InvocationStats
+	expectedName := fmt.Sprintf("%s-0-%d", common.FunctionNamePrefix, common.Hash(stats.HashOwner+stats.HashApp+stats.HashFunction))
+	if first[0].Name != expectedName || second[0].Name != expectedName {
+		t.Errorf("Expected the name %s in both runs, got %s and %s.", expectedName, first[0].Name, second[0].Name)
+	}
+	if first[0].ColdStartBusyLoopMs != second[0].ColdStartBusyLoopMs {
+		t.Errorf("Expected the same cold start busy loop in both runs, got %d and %d.", first[0].ColdStartBusyLoopMs, second[0].ColdStartBusyLoopMs)
+	}
+}
@@ -2,19 +2,15 @@ package trace
 
 import (
 	"encoding/json"
-	"fmt"
-	"math/rand"
 	"os"
-	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/vhive-serverless/loader/pkg/common"
 )
 
 type MapperTraceParser struct {
-	DirectoryPath         string
-	duration              int
-	functionNameGenerator *rand.Rand
+	DirectoryPath string
+	duration      int
 }
 
 type DeploymentInfo struct {
@@ -34,8 +30,7 @@ func NewMapperParser(directoryPath string, totalDuration int) *MapperTraceParser
 	return &MapperTraceParser{
 		DirectoryPath: directoryPath,
 
-		duration:              totalDuration,
-		functionNameGenerator: rand.New(rand.NewSource(time.Now().UnixNano())),
+		duration: totalDuration,
 	}
 }
 
@@ -58,7 +53,7 @@ func (p *MapperTraceParser) extractFunctions(mapperOutput functionToProxy, deplo
 		yamlPath := deploymentInfo[proxyFunction].YamlLocation
 		predeploymentPath := deploymentInfo[proxyFunction].PredeploymentPath
 		function := &common.Function{
-			Name: fmt.Sprintf("%s-%d-%d", proxyFunction, i, p.functionNameGenerator.Uint64()),
+			Name: traceFunctionName(proxyFunction, i, &invocationStats),
 
 			InvocationStats:   &invocationStats,
 			RuntimeStats:      runtimeByHashFunction[hashFunction],
@@ -0,0 +1,152 @@
+package trace
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gocarina/gocsv"
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+var payloadPercentiles = []float64{0, 0.25, 0.50, 0.75, 0.99, 1}
+
+// PayloadParser attaches payload size distributions to functions. Distributions are taken from the optional
+// payload.csv trace file and from the payload distribution configuration file. Per-function entries of the
+// configuration take precedence over the trace, which takes precedence over the default of the configuration.
+type PayloadParser struct {
+	directoryPath string
+	configPath    string
+	functions     []*common.Function
+}
+
+func NewPayloadParser(directoryPath string, configPath string, functions []*common.Function) *PayloadParser {
+	return &PayloadParser{
+		directoryPath: directoryPath,
+		configPath:    configPath,
+		functions:     functions,
+	}
+}
+
+func parsePayloadTrace(traceFile string) *[]common.FunctionPayloadStats {
+	if _, err := os.Stat(traceFile); err != nil {
+		return nil
+	}
+
+	log.Infof("Parsing function payload trace: %s", traceFile)
+
+	f, err := os.Open(traceFile)
+	if err != nil {
+		log.Fatal("Failed to open trace payload specification file.")
+	}
+	defer f.Close()
+
+	var payload []common.FunctionPayloadStats
+	err = gocsv.UnmarshalFile(f, &payload)
+	if err != nil {
+		log.Fatal("Failed to parse trace payload specification.")
+	}
+
+	return &payload
+}
+
+func createPayloadMap(payload *[]common.FunctionPayloadStats) map[string]*common.PayloadDistribution {
+	result := make(map[string]*common.PayloadDistribution)
+
+	for _, stats := range *payload {
+		result[stats.HashFunction] = &common.PayloadDistribution{
+			Request: &common.PayloadCDF{
+				SizeKB: []float64{
+					stats.RequestPercentile0, stats.RequestPercentile25, stats.RequestPercentile50,
+					stats.RequestPercentile75, stats.RequestPercentile99, stats.RequestPercentile100,
+				},
+				Probability: payloadPercentiles,
+			},
+			Response: &common.PayloadCDF{
+				SizeKB: []float64{
+					stats.ResponsePercentile0, stats.ResponsePercentile25, stats.ResponsePercentile50,
+					stats.ResponsePercentile75, stats.ResponsePercentile99, stats.ResponsePercentile100,
+				},
+				Probability: payloadPercentiles,
+			},
+		}
+	}
+
+	return result
+}
+
+func validatePayloadCDF(cdf *common.PayloadCDF) error {
+	if cdf == nil {
+		return nil
+	}
+
+	if len(cdf.SizeKB) == 0 || len(cdf.SizeKB) != len(cdf.Probability) {
+		return fmt.Errorf("sizes and probabilities must be non-empty and of the same length")
+	}
+
+	for i := 0; i < len(cdf.SizeKB); i++ {
+		if cdf.SizeKB[i] < 0 || cdf.Probability[i] < 0 || cdf.Probability[i] > 1 {
+			return fmt.Errorf("sizes must be non-negative and probabilities in [0, 1]")
+		}
+		if i > 0 && (cdf.SizeKB[i] < cdf.SizeKB[i-1] || cdf.Probability[i] < cdf.Probability[i-1]) {
+			return fmt.Errorf("sizes and probabilities must be non-decreasing")
+		}
+	}
+
+	if cdf.Probability[len(cdf.Probability)-1] != 1 {
+		return fmt.Errorf("the last probability must be equal to 1")
+	}
+
+	return nil
+}
+
+func validatePayloadDistribution(name string, distribution *common.PayloadDistribution) {
+	if distribution == nil {
+		return
+	}
+
+	if err := validatePayloadCDF(distribution.Request); err != nil {
+		log.Fatalf("Invalid request payload distribution of %s - %v", name, err)
+	}
+	if err := validatePayloadCDF(distribution.Response); err != nil {
+		log.Fatalf("Invalid response payload distribution of %s - %v", name, err)
+	}
+}
+
+func (pp *PayloadParser) Parse() {
+	var payloadByHashFunction map[string]*common.PayloadDistribution
+	if pp.directoryPath != "" {
+		if payloadTrace := parsePayloadTrace(pp.directoryPath + "/payload.csv"); payloadTrace != nil {
+			payloadByHashFunction = createPayloadMap(payloadTrace)
+		}
+	}
+
+	payloadConfig := config.ReadPayloadDistributionConfig(pp.configPath)
+
+	for _, function := range pp.functions {
+		var distribution *common.PayloadDistribution
+
+		if payloadConfig != nil {
+			distribution = payloadConfig.Default
+		}
+		if function.InvocationStats != nil {
+			if d, ok := payloadByHashFunction[function.InvocationStats.HashFunction]; ok {
+				distribution = d
+			}
+		}
+		if payloadConfig != nil {
+			if function.InvocationStats != nil {
+				if d, ok := payloadConfig.Functions[function.InvocationStats.HashFunction]; ok {
+					distribution = d
+				}
+			}
+			if d, ok := payloadConfig.Functions[function.Name]; ok {
+				distribution = d
+			}
+		}
+
+		validatePayloadDistribution(function.Name, distribution)
+		function.PayloadDistribution = distribution
+	}
+}
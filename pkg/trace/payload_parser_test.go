@@ -0,0 +1,66 @@
+package trace
+
+import (
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+func TestPayloadParser(t *testing.T) {
+	functions := []*common.Function{
+		{
+			Name: "trace-function",
+			InvocationStats: &common.FunctionInvocationStats{
+				HashFunction: "c13acdc7567b225971cef2416a3a2b03c8a4d8d154df48afe75834e2f5c59ddf",
+			},
+		},
+		{
+			Name:            "default-function",
+			InvocationStats: &common.FunctionInvocationStats{HashFunction: "unknown"},
+		},
+		{
+			Name: "override-function",
+		},
+	}
+
+	parser := NewPayloadParser("test_data", "test_data/payload_config.json", functions)
+	parser.Parse()
+
+	p0 := functions[0].PayloadDistribution
+	if p0 == nil ||
+		len(p0.Request.SizeKB) != 6 || p0.Request.SizeKB[2] != 4.0 || p0.Request.Probability[2] != 0.5 ||
+		len(p0.Response.SizeKB) != 6 || p0.Response.SizeKB[5] != 3.0 || p0.Response.Probability[5] != 1 {
+
+		t.Error("Unexpected payload distribution parsed from the trace.")
+	}
+
+	p1 := functions[1].PayloadDistribution
+	if p1 == nil || p1.Response != nil || len(p1.Request.SizeKB) != 2 || p1.Request.SizeKB[1] != 10 {
+		t.Error("Unexpected default payload distribution.")
+	}
+
+	p2 := functions[2].PayloadDistribution
+	if p2 == nil || p2.Request != nil || p2.Response.SizeKB[0] != 64 {
+		t.Error("Unexpected per-function payload distribution.")
+	}
+}
+
+func TestValidatePayloadCDF(t *testing.T) {
+	invalid := []*common.PayloadCDF{
+		{SizeKB: []float64{}, Probability: []float64{}},
+		{SizeKB: []float64{1, 2}, Probability: []float64{1}},
+		{SizeKB: []float64{2, 1}, Probability: []float64{0.5, 1}},
+		{SizeKB: []float64{1, 2}, Probability: []float64{0.5, 0.9}},
+		{SizeKB: []float64{-1, 2}, Probability: []float64{0.5, 1}},
+	}
+
+	for _, cdf := range invalid {
+		if validatePayloadCDF(cdf) == nil {
+			t.Errorf("Expected CDF %v to be invalid.", cdf)
+		}
+	}
+
+	if validatePayloadCDF(&common.PayloadCDF{SizeKB: []float64{1, 2}, Probability: []float64{0.5, 1}}) != nil {
+		t.Error("Expected CDF to be valid.")
+	}
+}
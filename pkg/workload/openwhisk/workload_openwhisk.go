@@ -31,6 +31,9 @@ import (
 	"time"
 )
 
+// NOTE: calibrated for the OpenWhisk deployment the workload has been developed on
+var iterationsMultiplier = util.ReadIterationsMultiplier(155)
+
 type FunctionResponse struct {
 	Status        string `json:"Status"`
 	Function      string `json:"Function"`
@@ -52,7 +55,7 @@ func Main(obj map[string]interface{}) map[string]interface{} {
 	start := time.Now()
 	timeLeftMilliseconds := uint32(ts)
 
-	util.TraceFunctionExecution(start, uint32(155), timeLeftMilliseconds)
+	util.TraceFunctionExecution(start, uint32(iterationsMultiplier), timeLeftMilliseconds)
 
 	responseBytes, _ := json.Marshal(FunctionResponse{
 		Status:        "OK",
@@ -1,158 +1,551 @@
+//
+// Set up:
+// $ go install google.golang.org/protobuf/cmd/protoc-gen-go@v1.34.2
+// $ go install google.golang.org/grpc/cmd/protoc-gen-go-grpc@v1.5.1
+//
+// Add `<GOPATH>/bin` to your $PATH:
+// OR (!suboptimal since it overwrites $PATH by appending an additional
+// line as opposed to change it directly)
+// $ echo "export PATH=$PATH:$(go env GOPATH)/bin" >> ~/.profile
+// $ source ~/.profile
+// OR temporarily
+// $ export PATH="$PATH:$(go env GOPATH)/bin"
+
 // Code generated by protoc-gen-go. DO NOT EDIT.
-// source: server/faas.proto
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        (unknown)
+// source: pkg/workload/proto/faas.proto
 
 package proto
 
 import (
-	fmt "fmt"
-	proto "github.com/golang/protobuf/proto"
-	math "math"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
 )
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ = proto.Marshal
-var _ = fmt.Errorf
-var _ = math.Inf
-
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the proto package it is being compiled against.
-// A compilation error at this line likely means your copy of the
-// proto package needs to be updated.
-const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
 
 type FaasRequest struct {
-	Message              string   `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
-	RuntimeInMilliSec    uint32   `protobuf:"varint,2,opt,name=runtimeInMilliSec,proto3" json:"runtimeInMilliSec,omitempty"`
-	MemoryInMebiBytes    uint32   `protobuf:"varint,3,opt,name=memoryInMebiBytes,proto3" json:"memoryInMebiBytes,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Message           string `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`                      // Text message field (unused).
+	RuntimeInMilliSec uint32 `protobuf:"varint,2,opt,name=runtimeInMilliSec,proto3" json:"runtimeInMilliSec,omitempty"` // Execution runtime [ms].
+	MemoryInMebiBytes uint32 `protobuf:"varint,3,opt,name=memoryInMebiBytes,proto3" json:"memoryInMebiBytes,omitempty"` // Request memory usage [MiB].
 }
 
-func (m *FaasRequest) Reset()         { *m = FaasRequest{} }
-func (m *FaasRequest) String() string { return proto.CompactTextString(m) }
-func (*FaasRequest) ProtoMessage()    {}
+func (x *FaasRequest) Reset() {
+	*x = FaasRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_workload_proto_faas_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FaasRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FaasRequest) ProtoMessage() {}
+
+func (x *FaasRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_workload_proto_faas_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FaasRequest.ProtoReflect.Descriptor instead.
 func (*FaasRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4886c8193ee7bbe7, []int{0}
+	return file_pkg_workload_proto_faas_proto_rawDescGZIP(), []int{0}
 }
 
-func (m *FaasRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_FaasRequest.Unmarshal(m, b)
+func (x *FaasRequest) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
 }
-func (m *FaasRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_FaasRequest.Marshal(b, m, deterministic)
+
+func (x *FaasRequest) GetRuntimeInMilliSec() uint32 {
+	if x != nil {
+		return x.RuntimeInMilliSec
+	}
+	return 0
+}
+
+func (x *FaasRequest) GetMemoryInMebiBytes() uint32 {
+	if x != nil {
+		return x.MemoryInMebiBytes
+	}
+	return 0
 }
-func (m *FaasRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_FaasRequest.Merge(m, src)
+
+type FaasReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Message            string `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`                        // Text message field (unused).
+	DurationInMicroSec uint32 `protobuf:"varint,2,opt,name=durationInMicroSec,proto3" json:"durationInMicroSec,omitempty"` // Execution latency [µs].
+	MemoryUsageInKb    uint32 `protobuf:"varint,3,opt,name=memoryUsageInKb,proto3" json:"memoryUsageInKb,omitempty"`       // Memory usage [KB].
 }
-func (m *FaasRequest) XXX_Size() int {
-	return xxx_messageInfo_FaasRequest.Size(m)
+
+func (x *FaasReply) Reset() {
+	*x = FaasReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_workload_proto_faas_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FaasReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
-func (m *FaasRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_FaasRequest.DiscardUnknown(m)
+
+func (*FaasReply) ProtoMessage() {}
+
+func (x *FaasReply) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_workload_proto_faas_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-var xxx_messageInfo_FaasRequest proto.InternalMessageInfo
+// Deprecated: Use FaasReply.ProtoReflect.Descriptor instead.
+func (*FaasReply) Descriptor() ([]byte, []int) {
+	return file_pkg_workload_proto_faas_proto_rawDescGZIP(), []int{1}
+}
 
-func (m *FaasRequest) GetMessage() string {
-	if m != nil {
-		return m.Message
+func (x *FaasReply) GetMessage() string {
+	if x != nil {
+		return x.Message
 	}
 	return ""
 }
 
-func (m *FaasRequest) GetRuntimeInMilliSec() uint32 {
-	if m != nil {
-		return m.RuntimeInMilliSec
+func (x *FaasReply) GetDurationInMicroSec() uint32 {
+	if x != nil {
+		return x.DurationInMicroSec
 	}
 	return 0
 }
 
-func (m *FaasRequest) GetMemoryInMebiBytes() uint32 {
-	if m != nil {
-		return m.MemoryInMebiBytes
+func (x *FaasReply) GetMemoryUsageInKb() uint32 {
+	if x != nil {
+		return x.MemoryUsageInKb
 	}
 	return 0
 }
 
-type FaasReply struct {
-	Message              string   `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
-	DurationInMicroSec   uint32   `protobuf:"varint,2,opt,name=durationInMicroSec,proto3" json:"durationInMicroSec,omitempty"`
-	MemoryUsageInKb      uint32   `protobuf:"varint,3,opt,name=memoryUsageInKb,proto3" json:"memoryUsageInKb,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+type FaasRequestV2 struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Message              string `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`                            // Request payload.
+	RuntimeInMilliSec    uint32 `protobuf:"varint,2,opt,name=runtimeInMilliSec,proto3" json:"runtimeInMilliSec,omitempty"`       // Execution runtime [ms].
+	MemoryInMebiBytes    uint32 `protobuf:"varint,3,opt,name=memoryInMebiBytes,proto3" json:"memoryInMebiBytes,omitempty"`       // Request memory usage [MiB].
+	InvocationID         string `protobuf:"bytes,4,opt,name=invocationID,proto3" json:"invocationID,omitempty"`                  // Unique invocation identifier assigned by the loader.
+	RequestPayloadBytes  uint32 `protobuf:"varint,5,opt,name=requestPayloadBytes,proto3" json:"requestPayloadBytes,omitempty"`   // Size of the request payload [B].
+	ResponsePayloadBytes uint32 `protobuf:"varint,6,opt,name=responsePayloadBytes,proto3" json:"responsePayloadBytes,omitempty"` // Size of the payload to be returned in the reply message [B].
+	IoPercentage         uint32 `protobuf:"varint,7,opt,name=ioPercentage,proto3" json:"ioPercentage,omitempty"`                 // Percentage of the runtime spent in IO.
 }
 
-func (m *FaasReply) Reset()         { *m = FaasReply{} }
-func (m *FaasReply) String() string { return proto.CompactTextString(m) }
-func (*FaasReply) ProtoMessage()    {}
-func (*FaasReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4886c8193ee7bbe7, []int{1}
+func (x *FaasRequestV2) Reset() {
+	*x = FaasRequestV2{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_workload_proto_faas_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FaasRequestV2) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FaasRequestV2) ProtoMessage() {}
+
+func (x *FaasRequestV2) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_workload_proto_faas_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FaasRequestV2.ProtoReflect.Descriptor instead.
+func (*FaasRequestV2) Descriptor() ([]byte, []int) {
+	return file_pkg_workload_proto_faas_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *FaasRequestV2) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *FaasRequestV2) GetRuntimeInMilliSec() uint32 {
+	if x != nil {
+		return x.RuntimeInMilliSec
+	}
+	return 0
+}
+
+func (x *FaasRequestV2) GetMemoryInMebiBytes() uint32 {
+	if x != nil {
+		return x.MemoryInMebiBytes
+	}
+	return 0
 }
 
-func (m *FaasReply) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_FaasReply.Unmarshal(m, b)
+func (x *FaasRequestV2) GetInvocationID() string {
+	if x != nil {
+		return x.InvocationID
+	}
+	return ""
 }
-func (m *FaasReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_FaasReply.Marshal(b, m, deterministic)
+
+func (x *FaasRequestV2) GetRequestPayloadBytes() uint32 {
+	if x != nil {
+		return x.RequestPayloadBytes
+	}
+	return 0
 }
-func (m *FaasReply) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_FaasReply.Merge(m, src)
+
+func (x *FaasRequestV2) GetResponsePayloadBytes() uint32 {
+	if x != nil {
+		return x.ResponsePayloadBytes
+	}
+	return 0
+}
+
+func (x *FaasRequestV2) GetIoPercentage() uint32 {
+	if x != nil {
+		return x.IoPercentage
+	}
+	return 0
+}
+
+type FaasReplyV2 struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Message                     string `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`                                          // Reply payload.
+	DurationInMicroSec          uint32 `protobuf:"varint,2,opt,name=durationInMicroSec,proto3" json:"durationInMicroSec,omitempty"`                   // Execution latency [µs].
+	MemoryUsageInKb             uint32 `protobuf:"varint,3,opt,name=memoryUsageInKb,proto3" json:"memoryUsageInKb,omitempty"`                         // Memory usage [KB].
+	InvocationID                string `protobuf:"bytes,4,opt,name=invocationID,proto3" json:"invocationID,omitempty"`                                // Invocation identifier copied from the request.
+	InstanceID                  string `protobuf:"bytes,5,opt,name=instanceID,proto3" json:"instanceID,omitempty"`                                    // Identifier of the function instance that served the request.
+	ColdStart                   bool   `protobuf:"varint,6,opt,name=coldStart,proto3" json:"coldStart,omitempty"`                                     // True if this was the first request served by the instance.
+	ContainerStartTimeUnixMicro int64  `protobuf:"varint,7,opt,name=containerStartTimeUnixMicro,proto3" json:"containerStartTimeUnixMicro,omitempty"` // Start time of the function instance [µs since epoch].
+	CpuTimeInMicroSec           uint64 `protobuf:"varint,8,opt,name=cpuTimeInMicroSec,proto3" json:"cpuTimeInMicroSec,omitempty"`                     // CPU time consumed by the request [µs].
+	PeakRssInKb                 uint64 `protobuf:"varint,9,opt,name=peakRssInKb,proto3" json:"peakRssInKb,omitempty"`                                 // Peak resident set size of the function instance [KB].
+}
+
+func (x *FaasReplyV2) Reset() {
+	*x = FaasReplyV2{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_workload_proto_faas_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FaasReplyV2) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FaasReplyV2) ProtoMessage() {}
+
+func (x *FaasReplyV2) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_workload_proto_faas_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FaasReplyV2.ProtoReflect.Descriptor instead.
+func (*FaasReplyV2) Descriptor() ([]byte, []int) {
+	return file_pkg_workload_proto_faas_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *FaasReplyV2) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
 }
-func (m *FaasReply) XXX_Size() int {
-	return xxx_messageInfo_FaasReply.Size(m)
+
+func (x *FaasReplyV2) GetDurationInMicroSec() uint32 {
+	if x != nil {
+		return x.DurationInMicroSec
+	}
+	return 0
 }
-func (m *FaasReply) XXX_DiscardUnknown() {
-	xxx_messageInfo_FaasReply.DiscardUnknown(m)
+
+func (x *FaasReplyV2) GetMemoryUsageInKb() uint32 {
+	if x != nil {
+		return x.MemoryUsageInKb
+	}
+	return 0
 }
 
-var xxx_messageInfo_FaasReply proto.InternalMessageInfo
+func (x *FaasReplyV2) GetInvocationID() string {
+	if x != nil {
+		return x.InvocationID
+	}
+	return ""
+}
 
-func (m *FaasReply) GetMessage() string {
-	if m != nil {
-		return m.Message
+func (x *FaasReplyV2) GetInstanceID() string {
+	if x != nil {
+		return x.InstanceID
 	}
 	return ""
 }
 
-func (m *FaasReply) GetDurationInMicroSec() uint32 {
-	if m != nil {
-		return m.DurationInMicroSec
+func (x *FaasReplyV2) GetColdStart() bool {
+	if x != nil {
+		return x.ColdStart
+	}
+	return false
+}
+
+func (x *FaasReplyV2) GetContainerStartTimeUnixMicro() int64 {
+	if x != nil {
+		return x.ContainerStartTimeUnixMicro
 	}
 	return 0
 }
 
-func (m *FaasReply) GetMemoryUsageInKb() uint32 {
-	if m != nil {
-		return m.MemoryUsageInKb
+func (x *FaasReplyV2) GetCpuTimeInMicroSec() uint64 {
+	if x != nil {
+		return x.CpuTimeInMicroSec
 	}
 	return 0
 }
 
-func init() {
-	proto.RegisterType((*FaasRequest)(nil), "faas.FaasRequest")
-	proto.RegisterType((*FaasReply)(nil), "faas.FaasReply")
-}
-
-func init() { proto.RegisterFile("server/faas.proto", fileDescriptor_4886c8193ee7bbe7) }
-
-var fileDescriptor_4886c8193ee7bbe7 = []byte{
-	// 256 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x90, 0xbf, 0x4b, 0xc3, 0x40,
-	0x14, 0xc7, 0x8d, 0x8a, 0xb5, 0x4f, 0xa4, 0xe4, 0xa6, 0xe0, 0x54, 0xe3, 0x92, 0x41, 0x73, 0xa0,
-	0xa3, 0x5b, 0x41, 0x21, 0x48, 0x97, 0x88, 0x8b, 0xdb, 0x25, 0x7d, 0xb6, 0x07, 0xf7, 0x23, 0xbe,
-	0xbb, 0x13, 0x33, 0x39, 0xf8, 0x8f, 0x4b, 0x7a, 0x8a, 0xc5, 0x4a, 0xb7, 0x77, 0xef, 0xfb, 0x8e,
-	0xef, 0x87, 0x0f, 0xa4, 0x0e, 0xe9, 0x0d, 0x89, 0xbf, 0x08, 0xe1, 0xca, 0x8e, 0xac, 0xb7, 0xec,
-	0x70, 0x98, 0xf3, 0xcf, 0x04, 0x4e, 0xee, 0x85, 0x70, 0x35, 0xbe, 0x06, 0x74, 0x9e, 0x65, 0x30,
-	0xd2, 0xe8, 0x9c, 0x58, 0x62, 0x96, 0x4c, 0x93, 0x62, 0x5c, 0xff, 0x3c, 0xd9, 0x25, 0xa4, 0x14,
-	0x8c, 0x97, 0x1a, 0x2b, 0x33, 0x97, 0x4a, 0xc9, 0x47, 0x6c, 0xb3, 0xfd, 0x69, 0x52, 0x9c, 0xd6,
-	0xdb, 0xc1, 0x70, 0xad, 0x51, 0x5b, 0xea, 0x2b, 0x33, 0xc7, 0x46, 0xce, 0x7a, 0x8f, 0x2e, 0x3b,
-	0x88, 0xd7, 0x5b, 0x41, 0xfe, 0x01, 0xe3, 0x08, 0xd1, 0xa9, 0x7e, 0x07, 0x42, 0x09, 0x6c, 0x11,
-	0x48, 0x78, 0x69, 0xcd, 0x50, 0xd5, 0x92, 0xfd, 0x65, 0xf8, 0x27, 0x61, 0x05, 0x4c, 0x62, 0xd7,
-	0xd3, 0xf0, 0xbd, 0x32, 0x0f, 0xcd, 0x37, 0xc2, 0xdf, 0xf5, 0xf5, 0x2d, 0x1c, 0xdf, 0xbd, 0x63,
-	0x1b, 0xbc, 0x25, 0xc6, 0x61, 0x14, 0x67, 0x64, 0x69, 0xb9, 0x16, 0xb6, 0x21, 0xe8, 0x6c, 0xb2,
-	0xb9, 0xea, 0x54, 0x9f, 0xef, 0xcd, 0x2e, 0x9e, 0xcf, 0x97, 0xd2, 0xaf, 0x42, 0x53, 0xb6, 0x56,
-	0x73, 0xf4, 0xab, 0x2b, 0x14, 0x4e, 0x71, 0x65, 0xc5, 0x02, 0x89, 0x47, 0xf3, 0xcd, 0xd1, 0xda,
-	0xfa, 0xcd, 0x57, 0x00, 0x00, 0x00, 0xff, 0xff, 0x29, 0xf8, 0x44, 0xf1, 0x8a, 0x01, 0x00, 0x00,
+func (x *FaasReplyV2) GetPeakRssInKb() uint64 {
+	if x != nil {
+		return x.PeakRssInKb
+	}
+	return 0
+}
+
+var File_pkg_workload_proto_faas_proto protoreflect.FileDescriptor
+
+var file_pkg_workload_proto_faas_proto_rawDesc = []byte{
+	0x0a, 0x1d, 0x70, 0x6b, 0x67, 0x2f, 0x77, 0x6f, 0x72, 0x6b, 0x6c, 0x6f, 0x61, 0x64, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x66, 0x61, 0x61, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
+	0x04, 0x66, 0x61, 0x61, 0x73, 0x22, 0x83, 0x01, 0x0a, 0x0b, 0x46, 0x61, 0x61, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12,
+	0x2c, 0x0a, 0x11, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x49, 0x6e, 0x4d, 0x69, 0x6c, 0x6c,
+	0x69, 0x53, 0x65, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x11, 0x72, 0x75, 0x6e, 0x74,
+	0x69, 0x6d, 0x65, 0x49, 0x6e, 0x4d, 0x69, 0x6c, 0x6c, 0x69, 0x53, 0x65, 0x63, 0x12, 0x2c, 0x0a,
+	0x11, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x49, 0x6e, 0x4d, 0x65, 0x62, 0x69, 0x42, 0x79, 0x74,
+	0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x11, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79,
+	0x49, 0x6e, 0x4d, 0x65, 0x62, 0x69, 0x42, 0x79, 0x74, 0x65, 0x73, 0x22, 0x7f, 0x0a, 0x09, 0x46,
+	0x61, 0x61, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x12, 0x2e, 0x0a, 0x12, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e,
+	0x4d, 0x69, 0x63, 0x72, 0x6f, 0x53, 0x65, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x12,
+	0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x4d, 0x69, 0x63, 0x72, 0x6f, 0x53,
+	0x65, 0x63, 0x12, 0x28, 0x0a, 0x0f, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x55, 0x73, 0x61, 0x67,
+	0x65, 0x49, 0x6e, 0x4b, 0x62, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0f, 0x6d, 0x65, 0x6d,
+	0x6f, 0x72, 0x79, 0x55, 0x73, 0x61, 0x67, 0x65, 0x49, 0x6e, 0x4b, 0x62, 0x22, 0xb3, 0x02, 0x0a,
+	0x0d, 0x46, 0x61, 0x61, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x56, 0x32, 0x12, 0x18,
+	0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x2c, 0x0a, 0x11, 0x72, 0x75, 0x6e, 0x74,
+	0x69, 0x6d, 0x65, 0x49, 0x6e, 0x4d, 0x69, 0x6c, 0x6c, 0x69, 0x53, 0x65, 0x63, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x11, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x49, 0x6e, 0x4d, 0x69,
+	0x6c, 0x6c, 0x69, 0x53, 0x65, 0x63, 0x12, 0x2c, 0x0a, 0x11, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79,
+	0x49, 0x6e, 0x4d, 0x65, 0x62, 0x69, 0x42, 0x79, 0x74, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x11, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x49, 0x6e, 0x4d, 0x65, 0x62, 0x69, 0x42,
+	0x79, 0x74, 0x65, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x69, 0x6e, 0x76, 0x6f, 0x63, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x49, 0x44, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x69, 0x6e, 0x76, 0x6f,
+	0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x44, 0x12, 0x30, 0x0a, 0x13, 0x72, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x13, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x50, 0x61,
+	0x79, 0x6c, 0x6f, 0x61, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x32, 0x0a, 0x14, 0x72, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x42, 0x79, 0x74,
+	0x65, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x14, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x22,
+	0x0a, 0x0c, 0x69, 0x6f, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x67, 0x65, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x69, 0x6f, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x61,
+	0x67, 0x65, 0x22, 0xf5, 0x02, 0x0a, 0x0b, 0x46, 0x61, 0x61, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79,
+	0x56, 0x32, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x2e, 0x0a, 0x12,
+	0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x4d, 0x69, 0x63, 0x72, 0x6f, 0x53,
+	0x65, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x12, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x49, 0x6e, 0x4d, 0x69, 0x63, 0x72, 0x6f, 0x53, 0x65, 0x63, 0x12, 0x28, 0x0a, 0x0f,
+	0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x55, 0x73, 0x61, 0x67, 0x65, 0x49, 0x6e, 0x4b, 0x62, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0f, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x55, 0x73, 0x61,
+	0x67, 0x65, 0x49, 0x6e, 0x4b, 0x62, 0x12, 0x22, 0x0a, 0x0c, 0x69, 0x6e, 0x76, 0x6f, 0x63, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x49, 0x44, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x69, 0x6e,
+	0x76, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x44, 0x12, 0x1e, 0x0a, 0x0a, 0x69, 0x6e,
+	0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x49, 0x44, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
+	0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x49, 0x44, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x6f,
+	0x6c, 0x64, 0x53, 0x74, 0x61, 0x72, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x63,
+	0x6f, 0x6c, 0x64, 0x53, 0x74, 0x61, 0x72, 0x74, 0x12, 0x40, 0x0a, 0x1b, 0x63, 0x6f, 0x6e, 0x74,
+	0x61, 0x69, 0x6e, 0x65, 0x72, 0x53, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x55, 0x6e,
+	0x69, 0x78, 0x4d, 0x69, 0x63, 0x72, 0x6f, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x1b, 0x63,
+	0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x53, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d,
+	0x65, 0x55, 0x6e, 0x69, 0x78, 0x4d, 0x69, 0x63, 0x72, 0x6f, 0x12, 0x2c, 0x0a, 0x11, 0x63, 0x70,
+	0x75, 0x54, 0x69, 0x6d, 0x65, 0x49, 0x6e, 0x4d, 0x69, 0x63, 0x72, 0x6f, 0x53, 0x65, 0x63, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x04, 0x52, 0x11, 0x63, 0x70, 0x75, 0x54, 0x69, 0x6d, 0x65, 0x49, 0x6e,
+	0x4d, 0x69, 0x63, 0x72, 0x6f, 0x53, 0x65, 0x63, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x65, 0x61, 0x6b,
+	0x52, 0x73, 0x73, 0x49, 0x6e, 0x4b, 0x62, 0x18, 0x09, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x70,
+	0x65, 0x61, 0x6b, 0x52, 0x73, 0x73, 0x49, 0x6e, 0x4b, 0x62, 0x32, 0x3b, 0x0a, 0x08, 0x45, 0x78,
+	0x65, 0x63, 0x75, 0x74, 0x6f, 0x72, 0x12, 0x2f, 0x0a, 0x07, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74,
+	0x65, 0x12, 0x11, 0x2e, 0x66, 0x61, 0x61, 0x73, 0x2e, 0x46, 0x61, 0x61, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x66, 0x61, 0x61, 0x73, 0x2e, 0x46, 0x61, 0x61, 0x73,
+	0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x32, 0x41, 0x0a, 0x0a, 0x45, 0x78, 0x65, 0x63, 0x75,
+	0x74, 0x6f, 0x72, 0x56, 0x32, 0x12, 0x33, 0x0a, 0x07, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65,
+	0x12, 0x13, 0x2e, 0x66, 0x61, 0x61, 0x73, 0x2e, 0x46, 0x61, 0x61, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x56, 0x32, 0x1a, 0x11, 0x2e, 0x66, 0x61, 0x61, 0x73, 0x2e, 0x46, 0x61, 0x61,
+	0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x56, 0x32, 0x22, 0x00, 0x42, 0x33, 0x5a, 0x31, 0x67, 0x69,
+	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x76, 0x68, 0x69, 0x76, 0x65, 0x2d, 0x73,
+	0x65, 0x72, 0x76, 0x65, 0x72, 0x6c, 0x65, 0x73, 0x73, 0x2f, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x72,
+	0x2f, 0x77, 0x6f, 0x72, 0x6b, 0x6c, 0x6f, 0x61, 0x64, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_pkg_workload_proto_faas_proto_rawDescOnce sync.Once
+	file_pkg_workload_proto_faas_proto_rawDescData = file_pkg_workload_proto_faas_proto_rawDesc
+)
+
+func file_pkg_workload_proto_faas_proto_rawDescGZIP() []byte {
+	file_pkg_workload_proto_faas_proto_rawDescOnce.Do(func() {
+		file_pkg_workload_proto_faas_proto_rawDescData = protoimpl.X.CompressGZIP(file_pkg_workload_proto_faas_proto_rawDescData)
+	})
+	return file_pkg_workload_proto_faas_proto_rawDescData
+}
+
+var file_pkg_workload_proto_faas_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_pkg_workload_proto_faas_proto_goTypes = []any{
+	(*FaasRequest)(nil),   // 0: faas.FaasRequest
+	(*FaasReply)(nil),     // 1: faas.FaasReply
+	(*FaasRequestV2)(nil), // 2: faas.FaasRequestV2
+	(*FaasReplyV2)(nil),   // 3: faas.FaasReplyV2
+}
+var file_pkg_workload_proto_faas_proto_depIdxs = []int32{
+	0, // 0: faas.Executor.Execute:input_type -> faas.FaasRequest
+	2, // 1: faas.ExecutorV2.Execute:input_type -> faas.FaasRequestV2
+	1, // 2: faas.Executor.Execute:output_type -> faas.FaasReply
+	3, // 3: faas.ExecutorV2.Execute:output_type -> faas.FaasReplyV2
+	2, // [2:4] is the sub-list for method output_type
+	0, // [0:2] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_pkg_workload_proto_faas_proto_init() }
+func file_pkg_workload_proto_faas_proto_init() {
+	if File_pkg_workload_proto_faas_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_pkg_workload_proto_faas_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*FaasRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_workload_proto_faas_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*FaasReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_workload_proto_faas_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*FaasRequestV2); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_workload_proto_faas_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*FaasReplyV2); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_pkg_workload_proto_faas_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   2,
+		},
+		GoTypes:           file_pkg_workload_proto_faas_proto_goTypes,
+		DependencyIndexes: file_pkg_workload_proto_faas_proto_depIdxs,
+		MessageInfos:      file_pkg_workload_proto_faas_proto_msgTypes,
+	}.Build()
+	File_pkg_workload_proto_faas_proto = out.File
+	file_pkg_workload_proto_faas_proto_rawDesc = nil
+	file_pkg_workload_proto_faas_proto_goTypes = nil
+	file_pkg_workload_proto_faas_proto_depIdxs = nil
 }
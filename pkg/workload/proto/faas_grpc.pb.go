@@ -1,4 +1,21 @@
+//
+// Set up:
+// $ go install google.golang.org/protobuf/cmd/protoc-gen-go@v1.34.2
+// $ go install google.golang.org/grpc/cmd/protoc-gen-go-grpc@v1.5.1
+//
+// Add `<GOPATH>/bin` to your $PATH:
+// OR (!suboptimal since it overwrites $PATH by appending an additional
+// line as opposed to change it directly)
+// $ echo "export PATH=$PATH:$(go env GOPATH)/bin" >> ~/.profile
+// $ source ~/.profile
+// OR temporarily
+// $ export PATH="$PATH:$(go env GOPATH)/bin"
+
 // Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: pkg/workload/proto/faas.proto
 
 package proto
 
@@ -11,8 +28,12 @@ import (
 
 // This is a compile-time assertion to ensure that this generated file
 // is compatible with the grpc package it is being compiled against.
-// Requires gRPC-Go v1.32.0 or later.
-const _ = grpc.SupportPackageIsVersion7
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Executor_Execute_FullMethodName = "/faas.Executor/Execute"
+)
 
 // ExecutorClient is the client API for Executor service.
 //
@@ -31,8 +52,9 @@ func NewExecutorClient(cc grpc.ClientConnInterface) ExecutorClient {
 }
 
 func (c *executorClient) Execute(ctx context.Context, in *FaasRequest, opts ...grpc.CallOption) (*FaasReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(FaasReply)
-	err := c.cc.Invoke(ctx, "/faas.Executor/Execute", in, out, opts...)
+	err := c.cc.Invoke(ctx, Executor_Execute_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -41,21 +63,25 @@ func (c *executorClient) Execute(ctx context.Context, in *FaasRequest, opts ...g
 
 // ExecutorServer is the server API for Executor service.
 // All implementations must embed UnimplementedExecutorServer
-// for forward compatibility
+// for forward compatibility.
 type ExecutorServer interface {
 	// Remote execution via RPC call.
 	Execute(context.Context, *FaasRequest) (*FaasReply, error)
 	mustEmbedUnimplementedExecutorServer()
 }
 
-// UnimplementedExecutorServer must be embedded to have forward compatible implementations.
-type UnimplementedExecutorServer struct {
-}
+// UnimplementedExecutorServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedExecutorServer struct{}
 
 func (UnimplementedExecutorServer) Execute(context.Context, *FaasRequest) (*FaasReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Execute not implemented")
 }
 func (UnimplementedExecutorServer) mustEmbedUnimplementedExecutorServer() {}
+func (UnimplementedExecutorServer) testEmbeddedByValue()                  {}
 
 // UnsafeExecutorServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to ExecutorServer will
@@ -65,6 +91,13 @@ type UnsafeExecutorServer interface {
 }
 
 func RegisterExecutorServer(s grpc.ServiceRegistrar, srv ExecutorServer) {
+	// If the following call pancis, it indicates UnimplementedExecutorServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
 	s.RegisterService(&Executor_ServiceDesc, srv)
 }
 
@@ -78,7 +111,7 @@ func _Executor_Execute_Handler(srv interface{}, ctx context.Context, dec func(in
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/faas.Executor/Execute",
+		FullMethod: Executor_Execute_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
 		return srv.(ExecutorServer).Execute(ctx, req.(*FaasRequest))
@@ -99,5 +132,117 @@ var Executor_ServiceDesc = grpc.ServiceDesc{
 		},
 	},
 	Streams:  []grpc.StreamDesc{},
-	Metadata: "server/faas.proto",
+	Metadata: "pkg/workload/proto/faas.proto",
+}
+
+const (
+	ExecutorV2_Execute_FullMethodName = "/faas.ExecutorV2/Execute"
+)
+
+// ExecutorV2Client is the client API for ExecutorV2 service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Version 2 of the Executor service, carrying additional telemetry. Functions should register both
+// services, so that loaders using either version of the protocol can invoke them. The loader falls
+// back to the Executor service for functions that do not implement ExecutorV2.
+type ExecutorV2Client interface {
+	// Remote execution via RPC call.
+	Execute(ctx context.Context, in *FaasRequestV2, opts ...grpc.CallOption) (*FaasReplyV2, error)
+}
+
+type executorV2Client struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewExecutorV2Client(cc grpc.ClientConnInterface) ExecutorV2Client {
+	return &executorV2Client{cc}
+}
+
+func (c *executorV2Client) Execute(ctx context.Context, in *FaasRequestV2, opts ...grpc.CallOption) (*FaasReplyV2, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FaasReplyV2)
+	err := c.cc.Invoke(ctx, ExecutorV2_Execute_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ExecutorV2Server is the server API for ExecutorV2 service.
+// All implementations must embed UnimplementedExecutorV2Server
+// for forward compatibility.
+//
+// Version 2 of the Executor service, carrying additional telemetry. Functions should register both
+// services, so that loaders using either version of the protocol can invoke them. The loader falls
+// back to the Executor service for functions that do not implement ExecutorV2.
+type ExecutorV2Server interface {
+	// Remote execution via RPC call.
+	Execute(context.Context, *FaasRequestV2) (*FaasReplyV2, error)
+	mustEmbedUnimplementedExecutorV2Server()
+}
+
+// UnimplementedExecutorV2Server must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedExecutorV2Server struct{}
+
+func (UnimplementedExecutorV2Server) Execute(context.Context, *FaasRequestV2) (*FaasReplyV2, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Execute not implemented")
+}
+func (UnimplementedExecutorV2Server) mustEmbedUnimplementedExecutorV2Server() {}
+func (UnimplementedExecutorV2Server) testEmbeddedByValue()                    {}
+
+// UnsafeExecutorV2Server may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ExecutorV2Server will
+// result in compilation errors.
+type UnsafeExecutorV2Server interface {
+	mustEmbedUnimplementedExecutorV2Server()
+}
+
+func RegisterExecutorV2Server(s grpc.ServiceRegistrar, srv ExecutorV2Server) {
+	// If the following call pancis, it indicates UnimplementedExecutorV2Server was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ExecutorV2_ServiceDesc, srv)
+}
+
+func _ExecutorV2_Execute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FaasRequestV2)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExecutorV2Server).Execute(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExecutorV2_Execute_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExecutorV2Server).Execute(ctx, req.(*FaasRequestV2))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ExecutorV2_ServiceDesc is the grpc.ServiceDesc for ExecutorV2 service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ExecutorV2_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "faas.ExecutorV2",
+	HandlerType: (*ExecutorV2Server)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Execute",
+			Handler:    _ExecutorV2_Execute_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pkg/workload/proto/faas.proto",
 }
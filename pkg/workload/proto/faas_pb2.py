@@ -23,7 +23,7 @@
 #  SOFTWARE.
 
 # Generated by the protocol buffer compiler.  DO NOT EDIT!
-# source: server/faas.proto
+# source: pkg/workload/proto/faas.proto
 """Generated protocol buffer code."""
 from google.protobuf import descriptor as _descriptor
 from google.protobuf import message as _message
@@ -37,12 +37,12 @@ _sym_db = _symbol_database.Default()
 
 
 DESCRIPTOR = _descriptor.FileDescriptor(
-  name='server/faas.proto',
+  name='pkg/workload/proto/faas.proto',
   package='faas',
   syntax='proto3',
-  serialized_options=b'Z!github.com/vhive-serverless/loader/server',
+  serialized_options=b'Z1github.com/vhive-serverless/loader/workload/proto',
   create_key=_descriptor._internal_create_key,
-  serialized_pb=b'\n\x11server/faas.proto\x12\x04\x66\x61\x61s\"T\n\x0b\x46\x61\x61sRequest\x12\x0f\n\x07message\x18\x01 \x01(\t\x12\x19\n\x11runtimeInMilliSec\x18\x02 \x01(\r\x12\x19\n\x11memoryInMebiBytes\x18\x03 \x01(\r\"Q\n\tFaasReply\x12\x0f\n\x07message\x18\x01 \x01(\t\x12\x1a\n\x12\x64urationInMicroSec\x18\x02 \x01(\r\x12\x17\n\x0fmemoryUsageInKb\x18\x03 \x01(\r2;\n\x08\x45xecutor\x12/\n\x07\x45xecute\x12\x11.faas.FaasRequest\x1a\x0f.faas.FaasReply\"\x00\x42#Z!github.com/vhive-serverless/loader/serverb\x06proto3'
+  serialized_pb=b'\n\x1dpkg/workload/proto/faas.proto\x12\x04\x66\x61\x61s\"T\n\x0b\x46\x61\x61sRequest\x12\x0f\n\x07message\x18\x01 \x01(\t\x12\x19\n\x11runtimeInMilliSec\x18\x02 \x01(\r\x12\x19\n\x11memoryInMebiBytes\x18\x03 \x01(\r\"Q\n\tFaasReply\x12\x0f\n\x07message\x18\x01 \x01(\t\x12\x1a\n\x12\x64urationInMicroSec\x18\x02 \x01(\r\x12\x17\n\x0fmemoryUsageInKb\x18\x03 \x01(\r\"\xbd\x01\n\rFaasRequestV2\x12\x0f\n\x07message\x18\x01 \x01(\t\x12\x19\n\x11runtimeInMilliSec\x18\x02 \x01(\r\x12\x19\n\x11memoryInMebiBytes\x18\x03 \x01(\r\x12\x14\n\x0cinvocationID\x18\x04 \x01(\t\x12\x1b\n\x13requestPayloadBytes\x18\x05 \x01(\r\x12\x1c\n\x14responsePayloadBytes\x18\x06 \x01(\r\x12\x14\n\x0cioPercentage\x18\x07 \x01(\r\"\xe5\x01\n\x0b\x46\x61\x61sReplyV2\x12\x0f\n\x07message\x18\x01 \x01(\t\x12\x1a\n\x12\x64urationInMicroSec\x18\x02 \x01(\r\x12\x17\n\x0fmemoryUsageInKb\x18\x03 \x01(\r\x12\x14\n\x0cinvocationID\x18\x04 \x01(\t\x12\x12\n\ninstanceID\x18\x05 \x01(\t\x12\x11\n\tcoldStart\x18\x06 \x01(\x08\x12#\n\x1b\x63ontainerStartTimeUnixMicro\x18\x07 \x01(\x03\x12\x19\n\x11\x63puTimeInMicroSec\x18\x08 \x01(\x04\x12\x13\n\x0bpeakRssInKb\x18\t \x01(\x04\x32;\n\x08\x45xecutor\x12/\n\x07\x45xecute\x12\x11.faas.FaasRequest\x1a\x0f.faas.FaasReply\"\x00\x32\x41\n\nExecutorV2\x12\x33\n\x07\x45xecute\x12\x13.faas.FaasRequestV2\x1a\x11.faas.FaasReplyV2\"\x00\x42\x33Z1github.com/vhive-serverless/loader/workload/protob\x06proto3'
 )
 
 
@@ -89,8 +89,8 @@ _FAASREQUEST = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=27,
-  serialized_end=111,
+  serialized_start=39,
+  serialized_end=123,
 )
 
 
@@ -135,28 +135,206 @@ _FAASREPLY = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=113,
-  serialized_end=194,
+  serialized_start=125,
+  serialized_end=206,
+)
+
+
+_FAASREQUESTV2 = _descriptor.Descriptor(
+  name='FaasRequestV2',
+  full_name='faas.FaasRequestV2',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  create_key=_descriptor._internal_create_key,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='message', full_name='faas.FaasRequestV2.message', index=0,
+      number=1, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='runtimeInMilliSec', full_name='faas.FaasRequestV2.runtimeInMilliSec', index=1,
+      number=2, type=13, cpp_type=3, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='memoryInMebiBytes', full_name='faas.FaasRequestV2.memoryInMebiBytes', index=2,
+      number=3, type=13, cpp_type=3, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='invocationID', full_name='faas.FaasRequestV2.invocationID', index=3,
+      number=4, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='requestPayloadBytes', full_name='faas.FaasRequestV2.requestPayloadBytes', index=4,
+      number=5, type=13, cpp_type=3, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='responsePayloadBytes', full_name='faas.FaasRequestV2.responsePayloadBytes', index=5,
+      number=6, type=13, cpp_type=3, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='ioPercentage', full_name='faas.FaasRequestV2.ioPercentage', index=6,
+      number=7, type=13, cpp_type=3, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=209,
+  serialized_end=398,
+)
+
+
+_FAASREPLYV2 = _descriptor.Descriptor(
+  name='FaasReplyV2',
+  full_name='faas.FaasReplyV2',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  create_key=_descriptor._internal_create_key,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='message', full_name='faas.FaasReplyV2.message', index=0,
+      number=1, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='durationInMicroSec', full_name='faas.FaasReplyV2.durationInMicroSec', index=1,
+      number=2, type=13, cpp_type=3, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='memoryUsageInKb', full_name='faas.FaasReplyV2.memoryUsageInKb', index=2,
+      number=3, type=13, cpp_type=3, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='invocationID', full_name='faas.FaasReplyV2.invocationID', index=3,
+      number=4, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='instanceID', full_name='faas.FaasReplyV2.instanceID', index=4,
+      number=5, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='coldStart', full_name='faas.FaasReplyV2.coldStart', index=5,
+      number=6, type=8, cpp_type=7, label=1,
+      has_default_value=False, default_value=False,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='containerStartTimeUnixMicro', full_name='faas.FaasReplyV2.containerStartTimeUnixMicro', index=6,
+      number=7, type=3, cpp_type=2, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='cpuTimeInMicroSec', full_name='faas.FaasReplyV2.cpuTimeInMicroSec', index=7,
+      number=8, type=4, cpp_type=4, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='peakRssInKb', full_name='faas.FaasReplyV2.peakRssInKb', index=8,
+      number=9, type=4, cpp_type=4, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=401,
+  serialized_end=630,
 )
 
 DESCRIPTOR.message_types_by_name['FaasRequest'] = _FAASREQUEST
 DESCRIPTOR.message_types_by_name['FaasReply'] = _FAASREPLY
+DESCRIPTOR.message_types_by_name['FaasRequestV2'] = _FAASREQUESTV2
+DESCRIPTOR.message_types_by_name['FaasReplyV2'] = _FAASREPLYV2
 _sym_db.RegisterFileDescriptor(DESCRIPTOR)
 
 FaasRequest = _reflection.GeneratedProtocolMessageType('FaasRequest', (_message.Message,), {
   'DESCRIPTOR' : _FAASREQUEST,
-  '__module__' : 'server.faas_pb2'
+  '__module__' : 'pkg.workload.proto.faas_pb2'
   # @@protoc_insertion_point(class_scope:faas.FaasRequest)
   })
 _sym_db.RegisterMessage(FaasRequest)
 
 FaasReply = _reflection.GeneratedProtocolMessageType('FaasReply', (_message.Message,), {
   'DESCRIPTOR' : _FAASREPLY,
-  '__module__' : 'server.faas_pb2'
+  '__module__' : 'pkg.workload.proto.faas_pb2'
   # @@protoc_insertion_point(class_scope:faas.FaasReply)
   })
 _sym_db.RegisterMessage(FaasReply)
 
+FaasRequestV2 = _reflection.GeneratedProtocolMessageType('FaasRequestV2', (_message.Message,), {
+  'DESCRIPTOR' : _FAASREQUESTV2,
+  '__module__' : 'pkg.workload.proto.faas_pb2'
+  # @@protoc_insertion_point(class_scope:faas.FaasRequestV2)
+  })
+_sym_db.RegisterMessage(FaasRequestV2)
+
+FaasReplyV2 = _reflection.GeneratedProtocolMessageType('FaasReplyV2', (_message.Message,), {
+  'DESCRIPTOR' : _FAASREPLYV2,
+  '__module__' : 'pkg.workload.proto.faas_pb2'
+  # @@protoc_insertion_point(class_scope:faas.FaasReplyV2)
+  })
+_sym_db.RegisterMessage(FaasReplyV2)
+
 
 DESCRIPTOR._options = None
 
@@ -167,8 +345,8 @@ _EXECUTOR = _descriptor.ServiceDescriptor(
   index=0,
   serialized_options=None,
   create_key=_descriptor._internal_create_key,
-  serialized_start=196,
-  serialized_end=255,
+  serialized_start=632,
+  serialized_end=691,
   methods=[
   _descriptor.MethodDescriptor(
     name='Execute',
@@ -185,4 +363,29 @@ _sym_db.RegisterServiceDescriptor(_EXECUTOR)
 
 DESCRIPTOR.services_by_name['Executor'] = _EXECUTOR
 
+_EXECUTORV2 = _descriptor.ServiceDescriptor(
+  name='ExecutorV2',
+  full_name='faas.ExecutorV2',
+  file=DESCRIPTOR,
+  index=1,
+  serialized_options=None,
+  create_key=_descriptor._internal_create_key,
+  serialized_start=693,
+  serialized_end=758,
+  methods=[
+  _descriptor.MethodDescriptor(
+    name='Execute',
+    full_name='faas.ExecutorV2.Execute',
+    index=0,
+    containing_service=None,
+    input_type=_FAASREQUESTV2,
+    output_type=_FAASREPLYV2,
+    serialized_options=None,
+    create_key=_descriptor._internal_create_key,
+  ),
+])
+_sym_db.RegisterServiceDescriptor(_EXECUTORV2)
+
+DESCRIPTOR.services_by_name['ExecutorV2'] = _EXECUTORV2
+
 # @@protoc_insertion_point(module_scope)
@@ -24,7 +24,7 @@
 """Client and server classes corresponding to protobuf-defined services."""
 import grpc
 
-from pkg.workload.proto import faas_pb2 as server_dot_faas__pb2
+from pkg.workload.proto import faas_pb2 as pkg_dot_workload_dot_proto_dot_faas__pb2
 
 
 class ExecutorStub(object):
@@ -38,8 +38,8 @@ class ExecutorStub(object):
         """
         self.Execute = channel.unary_unary(
                 '/faas.Executor/Execute',
-                request_serializer=server_dot_faas__pb2.FaasRequest.SerializeToString,
-                response_deserializer=server_dot_faas__pb2.FaasReply.FromString,
+                request_serializer=pkg_dot_workload_dot_proto_dot_faas__pb2.FaasRequest.SerializeToString,
+                response_deserializer=pkg_dot_workload_dot_proto_dot_faas__pb2.FaasReply.FromString,
                 )
 
 
@@ -58,8 +58,8 @@ def add_ExecutorServicer_to_server(servicer, server):
     rpc_method_handlers = {
             'Execute': grpc.unary_unary_rpc_method_handler(
                     servicer.Execute,
-                    request_deserializer=server_dot_faas__pb2.FaasRequest.FromString,
-                    response_serializer=server_dot_faas__pb2.FaasReply.SerializeToString,
+                    request_deserializer=pkg_dot_workload_dot_proto_dot_faas__pb2.FaasRequest.FromString,
+                    response_serializer=pkg_dot_workload_dot_proto_dot_faas__pb2.FaasReply.SerializeToString,
             ),
     }
     generic_handler = grpc.method_handlers_generic_handler(
@@ -83,7 +83,78 @@ class Executor(object):
             timeout=None,
             metadata=None):
         return grpc.experimental.unary_unary(request, target, '/faas.Executor/Execute',
-            server_dot_faas__pb2.FaasRequest.SerializeToString,
-            server_dot_faas__pb2.FaasReply.FromString,
+            pkg_dot_workload_dot_proto_dot_faas__pb2.FaasRequest.SerializeToString,
+            pkg_dot_workload_dot_proto_dot_faas__pb2.FaasReply.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+
+class ExecutorV2Stub(object):
+    """Version 2 of the Executor service, carrying additional telemetry. Functions should register both
+    services, so that loaders using either version of the protocol can invoke them. The loader falls
+    back to the Executor service for functions that do not implement ExecutorV2.
+    """
+
+    def __init__(self, channel):
+        """Constructor.
+
+        Args:
+            channel: A grpc.Channel.
+        """
+        self.Execute = channel.unary_unary(
+                '/faas.ExecutorV2/Execute',
+                request_serializer=pkg_dot_workload_dot_proto_dot_faas__pb2.FaasRequestV2.SerializeToString,
+                response_deserializer=pkg_dot_workload_dot_proto_dot_faas__pb2.FaasReplyV2.FromString,
+                )
+
+
+class ExecutorV2Servicer(object):
+    """Version 2 of the Executor service, carrying additional telemetry. Functions should register both
+    services, so that loaders using either version of the protocol can invoke them. The loader falls
+    back to the Executor service for functions that do not implement ExecutorV2.
+    """
+
+    def Execute(self, request, context):
+        """Remote execution via RPC call.
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+
+def add_ExecutorV2Servicer_to_server(servicer, server):
+    rpc_method_handlers = {
+            'Execute': grpc.unary_unary_rpc_method_handler(
+                    servicer.Execute,
+                    request_deserializer=pkg_dot_workload_dot_proto_dot_faas__pb2.FaasRequestV2.FromString,
+                    response_serializer=pkg_dot_workload_dot_proto_dot_faas__pb2.FaasReplyV2.SerializeToString,
+            ),
+    }
+    generic_handler = grpc.method_handlers_generic_handler(
+            'faas.ExecutorV2', rpc_method_handlers)
+    server.add_generic_rpc_handlers((generic_handler,))
+
+
+ # This class is part of an EXPERIMENTAL API.
+class ExecutorV2(object):
+    """Version 2 of the Executor service, carrying additional telemetry. Functions should register both
+    services, so that loaders using either version of the protocol can invoke them. The loader falls
+    back to the Executor service for functions that do not implement ExecutorV2.
+    """
+
+    @staticmethod
+    def Execute(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/faas.ExecutorV2/Execute',
+            pkg_dot_workload_dot_proto_dot_faas__pb2.FaasRequestV2.SerializeToString,
+            pkg_dot_workload_dot_proto_dot_faas__pb2.FaasReplyV2.FromString,
             options, channel_credentials,
             insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
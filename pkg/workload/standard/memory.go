@@ -0,0 +1,150 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package standard
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	util "github.com/vhive-serverless/loader/pkg/common"
+)
+
+const (
+	memoryAllocationFailure = "FAILURE - mem_alloc timeout"
+
+	// how many pages to touch between two checks of the deadline
+	pagesPerDeadlineCheck = 256
+
+	// cgroup v1 reports a value close to the maximum int64 when no limit is set
+	cgroupUnlimited = int64(1) << 60
+
+	noMemoryAllocationLimit = int64(-1)
+)
+
+var (
+	memoryAllocationEnabled bool
+	holdMemoryAllocation    bool
+
+	// upper bound of the memory allocated by all concurrent requests in bytes, noMemoryAllocationLimit if there is no bound
+	memoryAllocationLimit = noMemoryAllocationLimit
+	memoryAllocated       atomic.Int64
+
+	// memory limit files of cgroup v2 and v1, in the order they are looked up
+	cgroupMemoryLimitFiles = []string{"/sys/fs/cgroup/memory.max", "/sys/fs/cgroup/memory/memory.limit_in_bytes"}
+)
+
+func readMemoryAllocationVariables() {
+	memoryAllocationEnabled, _ = strconv.ParseBool(os.Getenv("ENABLE_MEMORY_ALLOCATION"))
+	if !memoryAllocationEnabled {
+		return
+	}
+
+	holdMemoryAllocation = true
+	if value, ok := os.LookupEnv("HOLD_MEMORY_ALLOCATION"); ok {
+		holdMemoryAllocation, _ = strconv.ParseBool(value)
+	}
+
+	if value, ok := os.LookupEnv("MEMORY_ALLOCATION_LIMIT_MIB"); ok {
+		limitMib, _ := strconv.ParseInt(value, 10, 64)
+		memoryAllocationLimit = max(limitMib, 0) << 20
+	} else if cgroupLimit := cgroupMemoryLimit(); cgroupLimit > 0 {
+		// leave room for the runtime of the function itself so that the container does not get OOM-killed
+		memoryAllocationLimit = max(cgroupLimit-util.ContainerImageSizeMB<<20, 0)
+		if memoryAllocationLimit == 0 {
+			log.Warnf("Memory limit of the container (%d MiB) leaves no room for allocations.", cgroupLimit>>20)
+		}
+	}
+
+	if memoryAllocationLimit == noMemoryAllocationLimit {
+		log.Infof("Memory allocation enabled - hold: %t, no limit\n", holdMemoryAllocation)
+	} else {
+		log.Infof("Memory allocation enabled - hold: %t, limit: %d MiB\n", holdMemoryAllocation, memoryAllocationLimit>>20)
+	}
+}
+
+// cgroupMemoryLimit returns the memory limit of the container in bytes, or 0 if the container is not limited.
+func cgroupMemoryLimit() int64 {
+	for _, path := range cgroupMemoryLimitFiles {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		limit, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+		if err != nil || limit >= cgroupUnlimited {
+			return 0 // 'max' in cgroup v2
+		}
+
+		return limit
+	}
+
+	return 0
+}
+
+// reserveMemory reserves up to the requested number of bytes within the allocation limit and returns the number of
+// bytes reserved.
+func reserveMemory(requested int64) int64 {
+	if memoryAllocationLimit == noMemoryAllocationLimit {
+		memoryAllocated.Add(requested)
+		return requested
+	}
+
+	for {
+		allocated := memoryAllocated.Load()
+		reserved := min(requested, max(memoryAllocationLimit-allocated, 0))
+		if memoryAllocated.CompareAndSwap(allocated, allocated+reserved) {
+			if reserved < requested {
+				log.Warnf("Memory allocation capped at %d MiB out of %d MiB requested.", reserved>>20, requested>>20)
+			}
+
+			return reserved
+		}
+	}
+}
+
+// allocateMemory allocates the requested memory, reduced by the memory the function container uses on its own,
+// and touches every page of it so that it becomes resident. It returns false if the deadline passed before all the
+// pages have been touched. The returned release function must be called once the memory is no longer needed.
+func allocateMemory(memoryInMebiBytes uint32, deadline time.Time) ([]byte, func(), bool) {
+	toAllocate := max(int64(memoryInMebiBytes)-util.ContainerImageSizeMB, 0) << 20
+	reserved := reserveMemory(toAllocate)
+	release := func() { memoryAllocated.Add(-reserved) }
+
+	pageSize := os.Getpagesize()
+	memory := make([]byte, reserved)
+	for i := 0; i < len(memory); i += pageSize {
+		memory[i] = 1
+
+		if (i/pageSize)%pagesPerDeadlineCheck == 0 && time.Now().After(deadline) {
+			return memory, release, false
+		}
+	}
+
+	return memory, release, true
+}
@@ -0,0 +1,216 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package standard
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	util "github.com/vhive-serverless/loader/pkg/common"
+)
+
+func TestCgroupMemoryLimit(t *testing.T) {
+	tests := []struct {
+		name     string
+		v2       string
+		v1       string
+		expected int64
+	}{
+		{name: "no_cgroup", expected: 0},
+		{name: "v2_limited", v2: "536870912\n", expected: 512 << 20},
+		{name: "v2_unlimited", v2: "max\n", expected: 0},
+		{name: "v1_limited", v1: "268435456\n", expected: 256 << 20},
+		{name: "v1_unlimited", v1: "9223372036854771712\n", expected: 0},
+		{name: "v2_preferred", v2: "536870912\n", v1: "268435456\n", expected: 512 << 20},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			files := []string{filepath.Join(dir, "memory.max"), filepath.Join(dir, "memory.limit_in_bytes")}
+			for i, content := range []string{test.v2, test.v1} {
+				if content == "" {
+					continue
+				}
+				if err := os.WriteFile(files[i], []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			defer func(original []string) { cgroupMemoryLimitFiles = original }(cgroupMemoryLimitFiles)
+			cgroupMemoryLimitFiles = files
+
+			if limit := cgroupMemoryLimit(); limit != test.expected {
+				t.Errorf("Expected cgroup memory limit %d, got %d.", test.expected, limit)
+			}
+		})
+	}
+}
+
+func TestReadMemoryAllocationVariables(t *testing.T) {
+	tests := []struct {
+		name        string
+		environment map[string]string
+		cgroup      string
+		enabled     bool
+		hold        bool
+		limit       int64
+	}{
+		{
+			name:        "disabled",
+			environment: map[string]string{"MEMORY_ALLOCATION_LIMIT_MIB": "64"},
+			limit:       noMemoryAllocationLimit,
+		},
+		{
+			name:        "no_limit",
+			environment: map[string]string{"ENABLE_MEMORY_ALLOCATION": "true"},
+			enabled:     true,
+			hold:        true,
+			limit:       noMemoryAllocationLimit,
+		},
+		{
+			name:        "no_hold",
+			environment: map[string]string{"ENABLE_MEMORY_ALLOCATION": "true", "HOLD_MEMORY_ALLOCATION": "false"},
+			enabled:     true,
+			limit:       noMemoryAllocationLimit,
+		},
+		{
+			name:        "explicit_limit",
+			environment: map[string]string{"ENABLE_MEMORY_ALLOCATION": "true", "MEMORY_ALLOCATION_LIMIT_MIB": "64"},
+			cgroup:      "536870912",
+			enabled:     true,
+			hold:        true,
+			limit:       64 << 20,
+		},
+		{
+			name:        "negative_limit",
+			environment: map[string]string{"ENABLE_MEMORY_ALLOCATION": "true", "MEMORY_ALLOCATION_LIMIT_MIB": "-1"},
+			enabled:     true,
+			hold:        true,
+			limit:       0,
+		},
+		{
+			name:        "cgroup_limit",
+			environment: map[string]string{"ENABLE_MEMORY_ALLOCATION": "true"},
+			cgroup:      "536870912",
+			enabled:     true,
+			hold:        true,
+			limit:       (512 - util.ContainerImageSizeMB) << 20,
+		},
+		{
+			name:        "cgroup_limit_below_image_size",
+			environment: map[string]string{"ENABLE_MEMORY_ALLOCATION": "true"},
+			cgroup:      "1048576",
+			enabled:     true,
+			hold:        true,
+			limit:       0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, variable := range []string{"ENABLE_MEMORY_ALLOCATION", "HOLD_MEMORY_ALLOCATION", "MEMORY_ALLOCATION_LIMIT_MIB"} {
+				t.Setenv(variable, test.environment[variable])
+				if _, ok := test.environment[variable]; !ok {
+					os.Unsetenv(variable)
+				}
+			}
+
+			cgroupFile := filepath.Join(t.TempDir(), "memory.max")
+			if test.cgroup != "" {
+				if err := os.WriteFile(cgroupFile, []byte(test.cgroup), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			defer func(original []string) { cgroupMemoryLimitFiles = original }(cgroupMemoryLimitFiles)
+			cgroupMemoryLimitFiles = []string{cgroupFile}
+
+			memoryAllocationEnabled, holdMemoryAllocation, memoryAllocationLimit = false, false, noMemoryAllocationLimit
+			defer func() { memoryAllocationLimit = noMemoryAllocationLimit }()
+
+			readMemoryAllocationVariables()
+
+			if memoryAllocationEnabled != test.enabled || holdMemoryAllocation != test.hold || memoryAllocationLimit != test.limit {
+				t.Errorf("Expected enabled %t, hold %t and limit %d, got %t, %t and %d.", test.enabled, test.hold,
+					test.limit, memoryAllocationEnabled, holdMemoryAllocation, memoryAllocationLimit)
+			}
+		})
+	}
+}
+
+func TestReserveMemory(t *testing.T) {
+	tests := []struct {
+		name      string
+		limit     int64
+		allocated int64
+		requested int64
+		expected  int64
+	}{
+		{name: "no_limit", limit: noMemoryAllocationLimit, allocated: 1 << 30, requested: 64 << 20, expected: 64 << 20},
+		{name: "within_limit", limit: 128 << 20, allocated: 32 << 20, requested: 64 << 20, expected: 64 << 20},
+		{name: "capped_at_limit", limit: 128 << 20, allocated: 96 << 20, requested: 64 << 20, expected: 32 << 20},
+		{name: "limit_reached", limit: 128 << 20, allocated: 128 << 20, requested: 64 << 20, expected: 0},
+		{name: "zero_limit", limit: 0, requested: 64 << 20, expected: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			memoryAllocationLimit = test.limit
+			memoryAllocated.Store(test.allocated)
+			defer func() {
+				memoryAllocationLimit = noMemoryAllocationLimit
+				memoryAllocated.Store(0)
+			}()
+
+			if reserved := reserveMemory(test.requested); reserved != test.expected {
+				t.Errorf("Expected %d bytes to be reserved, got %d.", test.expected, reserved)
+			}
+			if allocated := memoryAllocated.Load(); allocated != test.allocated+test.expected {
+				t.Errorf("Expected %d bytes to be accounted as allocated, got %d.", test.allocated+test.expected, allocated)
+			}
+		})
+	}
+}
+
+func TestAllocateMemory(t *testing.T) {
+	memoryAllocationLimit = 8 << 20
+	defer func() { memoryAllocationLimit = noMemoryAllocationLimit }()
+
+	memory, release, completed := allocateMemory(util.ContainerImageSizeMB+16, time.Now().Add(time.Minute))
+	if !completed {
+		t.Error("Allocation should have completed before the deadline.")
+	}
+	if len(memory) != 8<<20 {
+		t.Errorf("Expected the allocation to be capped at 8 MiB, got %d bytes.", len(memory))
+	}
+
+	release()
+	if allocated := memoryAllocated.Load(); allocated != 0 {
+		t.Errorf("Expected no memory to be accounted as allocated after release, got %d bytes.", allocated)
+	}
+}
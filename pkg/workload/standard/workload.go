@@ -31,7 +31,9 @@ import (
 	"net"
 	"os"
 	"os/signal"
+	"runtime"
 	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
@@ -40,6 +42,7 @@ import (
 	"github.com/vhive-serverless/loader/pkg/workload/proto"
 	tracing "github.com/vhive-serverless/vSwarm/utils/tracing/go"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/reflection"
 )
 
@@ -47,6 +50,10 @@ var hostname string
 var IterationsMultiplier int
 var serverSideCode FunctionType
 
+// IO percentage used when the request does not specify one
+var ioPercentage uint32
+var ioConfig *util.IOConfiguration
+
 type FunctionType int
 
 const (
@@ -58,46 +65,109 @@ type funcServer struct {
 	proto.UnimplementedExecutorServer
 }
 
-func (s *funcServer) Execute(_ context.Context, req *proto.FaasRequest) (*proto.FaasReply, error) {
-	var msg string
-	start := time.Now()
+type funcServerV2 struct {
+	proto.UnimplementedExecutorV2Server
+}
 
-	if serverSideCode == TraceFunction {
-		// Minimum execution time is AWS billing granularity - 1ms,
-		// as defined in SpecificationGenerator::generateExecutionSpecs
-		timeLeftMilliseconds := req.RuntimeInMilliSec
-		/*toAllocate := util.Mib2b(req.MemoryInMebiBytes - ContainerImageSizeMB)
-		if toAllocate < 0 {
-			toAllocate = 0
-		}*/
-
-		// make is equivalent to `calloc` in C. The memory gets allocated
-		// and zero is written to every byte, i.e. each page should be touched at least once
-		//memory := make([]byte, toAllocate)
-		// NOTE: the following statement to make sure the compiler does not treat the allocation as dead code
-		//log.Debugf("Allocated memory size: %d\n", len(memory))
-		msg = util.TraceFunctionExecution(start, uint32(IterationsMultiplier), timeLeftMilliseconds)
-	} else {
-		msg = fmt.Sprintf("OK - EMPTY - %s", hostname)
+var instance *util.InstanceTelemetry
+
+// execute runs the function and returns the reply message along with the memory usage in KiB
+func execute(start time.Time, runtimeInMilliSec uint32, memoryInMebiBytes uint32, requestIOPercentage uint32) (string, uint32) {
+	instance.ColdStart() // any request makes subsequent ones warm, regardless of the protocol version
+
+	if serverSideCode != TraceFunction {
+		return fmt.Sprintf("OK - EMPTY - %s", hostname), util.Mib2Kib(memoryInMebiBytes)
 	}
 
+	// Minimum execution time is AWS billing granularity - 1ms,
+	// as defined in SpecificationGenerator::generateExecutionSpecs
+	timeLeftMilliseconds := runtimeInMilliSec
+	if requestIOPercentage == 0 {
+		requestIOPercentage = ioPercentage
+	}
+
+	if !memoryAllocationEnabled {
+		return util.MixedFunctionExecution(start, uint32(IterationsMultiplier), timeLeftMilliseconds, requestIOPercentage, ioConfig), util.Mib2Kib(memoryInMebiBytes)
+	}
+
+	deadline := start.Add(time.Duration(runtimeInMilliSec) * time.Millisecond)
+	memory, release, ok := allocateMemory(memoryInMebiBytes, deadline)
+	defer release()
+
+	memoryUsage := uint32(util.RSSKib())
+	if !ok {
+		return memoryAllocationFailure, memoryUsage
+	}
+	if !holdMemoryAllocation {
+		memory = nil
+	}
+
+	msg := util.MixedFunctionExecution(start, uint32(IterationsMultiplier), timeLeftMilliseconds, requestIOPercentage, ioConfig)
+	// NOTE: keeps the memory allocated until the end of the execution
+	runtime.KeepAlive(memory)
+
+	return msg, memoryUsage
+}
+
+func (s *funcServer) Execute(ctx context.Context, req *proto.FaasRequest) (*proto.FaasReply, error) {
+	start := time.Now()
+	msg, memoryUsage := execute(start, req.RuntimeInMilliSec, req.MemoryInMebiBytes, 0)
+
 	return &proto.FaasReply{
-		Message:            msg,
+		Message:            msg + responsePayload(ctx),
 		DurationInMicroSec: uint32(time.Since(start).Microseconds()),
-		MemoryUsageInKb:    req.MemoryInMebiBytes * 1024,
+		MemoryUsageInKb:    memoryUsage,
 	}, nil
 }
 
-func readEnvironmentalVariables() {
-	if _, ok := os.LookupEnv("ITERATIONS_MULTIPLIER"); ok {
-		IterationsMultiplier, _ = strconv.Atoi(os.Getenv("ITERATIONS_MULTIPLIER"))
-	} else {
-		// Cloudlab xl170 benchmark @ 1 second function execution time
-		IterationsMultiplier = 102
+func (s *funcServerV2) Execute(_ context.Context, req *proto.FaasRequestV2) (*proto.FaasReplyV2, error) {
+	// CPU time is measured per OS thread
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	start := time.Now()
+	cpuStart := util.ThreadCPUTime()
+	coldStart := instance.ColdStart()
+
+	msg, memoryUsage := execute(start, req.RuntimeInMilliSec, req.MemoryInMebiBytes, req.IoPercentage)
+
+	return &proto.FaasReplyV2{
+		Message:                     msg + strings.Repeat("0", int(req.ResponsePayloadBytes)),
+		DurationInMicroSec:          uint32(time.Since(start).Microseconds()),
+		MemoryUsageInKb:             memoryUsage,
+		InvocationID:                req.InvocationID,
+		InstanceID:                  hostname,
+		ColdStart:                   coldStart,
+		ContainerStartTimeUnixMicro: instance.StartTime.UnixMicro(),
+		CpuTimeInMicroSec:           uint64((util.ThreadCPUTime() - cpuStart).Microseconds()),
+		PeakRssInKb:                 util.PeakRSSKib(),
+	}, nil
+}
+
+// responsePayload returns the padding the loader requested to be appended to the reply message
+func responsePayload(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok || len(md.Get(util.ResponsePayloadKey)) == 0 {
+		return ""
 	}
 
+	size, err := strconv.Atoi(md.Get(util.ResponsePayloadKey)[0])
+	if err != nil || size <= 0 {
+		return ""
+	}
+
+	return strings.Repeat("0", size)
+}
+
+func readEnvironmentalVariables() {
+	// Cloudlab xl170 benchmark @ 1 second function execution time
+	IterationsMultiplier = util.ReadIterationsMultiplier(102)
+
 	log.Infof("ITERATIONS_MULTIPLIER = %d\n", IterationsMultiplier)
 
+	readMemoryAllocationVariables()
+	readIOVariables()
+
 	var err error
 	hostname, err = os.Hostname()
 	if err != nil {
@@ -106,9 +176,37 @@ func readEnvironmentalVariables() {
 	}
 }
 
+func readIOVariables() {
+	percentage, _ := strconv.Atoi(os.Getenv("IO_PERCENTAGE"))
+	ioPercentage = uint32(min(max(percentage, 0), 100))
+
+	ioConfig = &util.IOConfiguration{
+		Mode:          util.IOModeSleep,
+		DiskPath:      os.Getenv("IO_DISK_PATH"),
+		DownstreamURL: os.Getenv("IO_DOWNSTREAM_URL"),
+	}
+	if mode, ok := os.LookupEnv("IO_MODE"); ok {
+		ioConfig.Mode = util.IOMode(strings.ToLower(mode))
+	}
+
+	switch ioConfig.Mode {
+	case util.IOModeSleep, util.IOModeDisk:
+	case util.IOModeDownstream:
+		if ioConfig.DownstreamURL == "" {
+			log.Warn("IO_DOWNSTREAM_URL is not set. IO will be emulated by sleeping.")
+		}
+	default:
+		log.Warnf("Unsupported IO_MODE %s. IO will be emulated by sleeping.", ioConfig.Mode)
+		ioConfig.Mode = util.IOModeSleep
+	}
+
+	log.Infof("IO_PERCENTAGE = %d, IO_MODE = %s\n", ioPercentage, ioConfig.Mode)
+}
+
 func StartGRPCServer(serverAddress string, serverPort int, functionType FunctionType, zipkinUrl string) {
 	readEnvironmentalVariables()
 	serverSideCode = functionType
+	instance = util.NewInstanceTelemetry()
 
 	if tracing.IsTracingEnabled() {
 		log.Infof("Zipkin URL: %s\n", zipkinUrl)
@@ -142,6 +240,7 @@ func StartGRPCServer(serverAddress string, serverPort int, functionType Function
 
 	reflection.Register(grpcServer) // gRPC Server Reflection is used by gRPC CLI
 	proto.RegisterExecutorServer(grpcServer, &funcServer{})
+	proto.RegisterExecutorV2Server(grpcServer, &funcServerV2{})
 	err = grpcServer.Serve(lis)
 	util.Check(err)
 }
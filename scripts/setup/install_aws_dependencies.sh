@@ -92,16 +92,10 @@ server_exec 'sudo rm -f /tmp/go.tar.gz'
 server_exec 'echo "export PATH=\$PATH:/usr/local/go/bin" >> ~/.profile'
 echo "Installed golang"
 
-# ========== Install serverless.com framework (as a standalone binary without node.js / npm dependencies) ==========
-server_exec 'curl -o- -L https://slss.io/install | VERSION=3.34.0 bash'
-# Do not require export PATH as the installation comes with it
-echo "Installed serverless.com framework"
-
 # ========== Check the installed versions ==========
 echo "Checking the installed versions:"
 server_exec 'source ~/.profile; aws --version'
 server_exec 'source ~/.profile; docker --version'
-server_exec 'source ~/.profile; serverless --version'
 server_exec 'source ~/.profile; go version'
 
 echo "Finished installing the dependencies for AWS deployment"
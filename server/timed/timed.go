@@ -32,7 +32,9 @@ import (
 	"github.com/vhive-serverless/loader/pkg/workload/proto"
 	"net"
 	"os"
+	"runtime"
 	"strconv"
+	"strings"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -61,19 +63,28 @@ func busySpin(timeoutSem <-chan time.Time) {
 	}
 }
 
-func (s *funcServer) Execute(ctx context.Context, req *proto.FaasRequest) (*proto.FaasReply, error) {
-	start := time.Now()
-	runtimeRequested := req.RuntimeInMilliSec
+type funcServerV2 struct {
+	proto.UnimplementedExecutorV2Server
+}
+
+var (
+	hostname string
+	instance = util.NewInstanceTelemetry()
+)
+
+func execute(runtimeRequested uint32, memoryRequested uint32) (string, uint32, error) {
+	instance.ColdStart() // any request makes subsequent ones warm, regardless of the protocol version
+
 	timeoutSem := time.After(time.Duration(runtimeRequested) * time.Millisecond)
 	if runtimeRequested <= 0 {
 		//* Some of the durations were incorrectly recorded as 0 in the trace.
-		return &proto.FaasReply{}, errors.New("non-positive execution time")
+		return "", 0, errors.New("non-positive execution time")
 	}
 
 	//* To avoid unecessary overheads, memory allocation is at the granularity of os pages.
 	delta := 2 //* Emperical skewness.
 	pageSize := unix.Getpagesize()
-	numPagesRequested := util.Mib2b(req.MemoryInMebiBytes) / uint32(pageSize) / uint32(delta)
+	numPagesRequested := util.Mib2b(memoryRequested) / uint32(pageSize) / uint32(delta)
 	bytes := make([]byte, numPagesRequested*uint32(pageSize))
 	timeout := false
 	for i := 0; i < int(numPagesRequested); i += pageSize {
@@ -94,10 +105,47 @@ finish:
 		msg = "Timeout when materialising allocated memory."
 	}
 
+	return msg, util.B2Kib(numPagesRequested * uint32(unix.Getpagesize())), nil
+}
+
+func (s *funcServer) Execute(ctx context.Context, req *proto.FaasRequest) (*proto.FaasReply, error) {
+	start := time.Now()
+	msg, memoryUsage, err := execute(req.RuntimeInMilliSec, req.MemoryInMebiBytes)
+	if err != nil {
+		return &proto.FaasReply{}, err
+	}
+
 	return &proto.FaasReply{
 		Message:            msg,
 		DurationInMicroSec: uint32(time.Since(start).Microseconds()),
-		MemoryUsageInKb:    util.B2Kib(numPagesRequested * uint32(unix.Getpagesize())),
+		MemoryUsageInKb:    memoryUsage,
+	}, nil
+}
+
+func (s *funcServerV2) Execute(ctx context.Context, req *proto.FaasRequestV2) (*proto.FaasReplyV2, error) {
+	//* CPU time is measured per OS thread.
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	start := time.Now()
+	cpuStart := util.ThreadCPUTime()
+	coldStart := instance.ColdStart()
+
+	msg, memoryUsage, err := execute(req.RuntimeInMilliSec, req.MemoryInMebiBytes)
+	if err != nil {
+		return &proto.FaasReplyV2{}, err
+	}
+
+	return &proto.FaasReplyV2{
+		Message:                     msg + strings.Repeat("0", int(req.ResponsePayloadBytes)),
+		DurationInMicroSec:          uint32(time.Since(start).Microseconds()),
+		MemoryUsageInKb:             memoryUsage,
+		InvocationID:                req.InvocationID,
+		InstanceID:                  hostname,
+		ColdStart:                   coldStart,
+		ContainerStartTimeUnixMicro: instance.StartTime.UnixMicro(),
+		CpuTimeInMicroSec:           uint64((util.ThreadCPUTime() - cpuStart).Microseconds()),
+		PeakRssInKb:                 util.PeakRSSKib(),
 	}, nil
 }
 
@@ -108,6 +156,8 @@ func main() {
 		serverPort, _ = strconv.Atoi(os.Args[1])
 	}
 
+	hostname, _ = os.Hostname()
+
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", serverPort))
 	if err != nil {
 		log.Fatalf("failed to listen: %v", err)
@@ -117,6 +167,7 @@ func main() {
 	grpcServer := grpc.NewServer()
 	reflection.Register(grpcServer) // gRPC Server Reflection is used by gRPC CLI.
 	proto.RegisterExecutorServer(grpcServer, funcServer)
+	proto.RegisterExecutorV2Server(grpcServer, &funcServerV2{})
 	err = grpcServer.Serve(lis)
 	util.Check(err)
 }
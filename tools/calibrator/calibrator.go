@@ -0,0 +1,36 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+var (
+	duration = flag.Duration("duration", 5*time.Second, "Duration of the measurement")
+)
+
+func init() {
+	flag.Parse()
+
+	log.SetFormatter(&log.TextFormatter{
+		TimestampFormat: time.StampMilli,
+		FullTimestamp:   true,
+	})
+	log.SetOutput(os.Stdout)
+}
+
+func main() {
+	if *duration <= 0 {
+		log.Fatal("Duration of the measurement should be positive.")
+	}
+
+	log.Infof("Calibrating the busy loop for %v...", *duration)
+	multiplier := common.CalibrateIterationsMultiplier(*duration)
+
+	fmt.Printf("ITERATIONS_MULTIPLIER=%d\n", multiplier)
+}